@@ -0,0 +1,348 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+)
+
+// compoundStep is the deposit that results from swapping a given amount of the surplus token.
+type compoundStep struct {
+	swapOutAmount    *big.Int
+	minSwapOutAmount *big.Int
+	priceImpact      decimal.Decimal
+	amountA          *big.Int
+	amountB          *big.Int
+	liquidityFromA   *big.Int
+	liquidityFromB   *big.Int
+}
+
+// GetCompoundQuote calculates how unclaimed position fees can be re-deposited into the same position.
+func (c *CpAmm) GetCompoundQuote(params GetCompoundQuoteParams) (CompoundQuote, error) {
+	poolState := params.PoolState
+	feeA, feeB, _, err := helpers.GetUnClaimLpFee(poolState, params.PositionState)
+	if err != nil {
+		return CompoundQuote{}, err
+	}
+	receivedA := feeA
+	receivedB := feeB
+	if params.TokenATokenInfo != nil {
+		receivedA = helpers.CalculateTransferFeeExcludedAmount(feeA, params.TokenATokenInfo).Amount
+	}
+	if params.TokenBTokenInfo != nil {
+		receivedB = helpers.CalculateTransferFeeExcludedAmount(feeB, params.TokenBTokenInfo).Amount
+	}
+
+	quoteMint := poolState.TokenBMint
+	if params.Policy != nil && !params.Policy.QuoteMint.IsZero() {
+		quoteMint = params.Policy.QuoteMint
+	}
+	quoteIsA := poolState.TokenAMint.Equals(quoteMint)
+	if !quoteIsA && !poolState.TokenBMint.Equals(quoteMint) {
+		return CompoundQuote{}, errors.New("quote mint does not belong to pool")
+	}
+	sqrtPrice := poolState.SqrtPrice.BigInt()
+	feeValue := valueInQuote(receivedA, receivedB, sqrtPrice, quoteIsA)
+
+	quote := CompoundQuote{
+		FeeAmountA:       feeA,
+		FeeAmountB:       feeB,
+		FeeValue:         feeValue,
+		QuoteMint:        quoteMint,
+		SwapInAmount:     big.NewInt(0),
+		SwapOutAmount:    big.NewInt(0),
+		MinSwapOutAmount: big.NewInt(0),
+		PriceImpact:      decimal.Zero,
+		LiquidityDelta:   big.NewInt(0),
+		MaxAmountTokenA:  big.NewInt(0),
+		MaxAmountTokenB:  big.NewInt(0),
+	}
+	if receivedA.Sign() == 0 && receivedB.Sign() == 0 {
+		return quote, nil
+	}
+
+	simulate := func(swapAToB bool, swapInAmount *big.Int) (compoundStep, error) {
+		step := compoundStep{
+			swapOutAmount:    big.NewInt(0),
+			minSwapOutAmount: big.NewInt(0),
+			priceImpact:      decimal.Zero,
+			amountA:          new(big.Int).Set(receivedA),
+			amountB:          new(big.Int).Set(receivedB),
+		}
+		nextSqrtPrice := sqrtPrice
+		if swapInAmount.Sign() > 0 {
+			inputTokenInfo, outputTokenInfo := params.TokenATokenInfo, params.TokenBTokenInfo
+			if !swapAToB {
+				inputTokenInfo, outputTokenInfo = params.TokenBTokenInfo, params.TokenATokenInfo
+			}
+			swapQuote, err := math.SwapQuoteExactInput(poolState, params.CurrentPoint, swapInAmount, params.Slippage, swapAToB, false, params.TokenADecimal, params.TokenBDecimal, inputTokenInfo, outputTokenInfo)
+			if err != nil {
+				return compoundStep{}, err
+			}
+			step.swapOutAmount = new(big.Int).SetUint64(swapQuote.OutputAmount)
+			if outputTokenInfo != nil {
+				step.swapOutAmount = helpers.CalculateTransferFeeExcludedAmount(step.swapOutAmount, outputTokenInfo).Amount
+			}
+			step.minSwapOutAmount = swapQuote.MinimumAmountOut
+			step.priceImpact = swapQuote.PriceImpact
+			nextSqrtPrice = swapQuote.NextSqrtPrice.BigInt()
+			if swapAToB {
+				step.amountA.Sub(step.amountA, swapInAmount)
+				step.amountB.Add(step.amountB, step.minSwapOutAmount)
+			} else {
+				step.amountB.Sub(step.amountB, swapInAmount)
+				step.amountA.Add(step.amountA, step.minSwapOutAmount)
+			}
+		}
+		step.liquidityFromA, step.liquidityFromB = depositLiquidity(poolState, nextSqrtPrice, step.amountA, step.amountB, params.TokenATokenInfo, params.TokenBTokenInfo)
+		return step, nil
+	}
+
+	best, err := simulate(true, big.NewInt(0))
+	if err != nil {
+		return CompoundQuote{}, err
+	}
+	swapAToB := compareLiquidity(best.liquidityFromA, best.liquidityFromB) > 0
+	swapInAmount := big.NewInt(0)
+	if compareLiquidity(best.liquidityFromA, best.liquidityFromB) != 0 {
+		surplus := receivedB
+		if swapAToB {
+			surplus = receivedA
+		}
+		// the scarce side grows with the swapped amount, so search the smallest amount that balances both sides.
+		balanced := func(step compoundStep) bool {
+			if swapAToB {
+				return compareLiquidity(step.liquidityFromB, step.liquidityFromA) >= 0
+			}
+			return compareLiquidity(step.liquidityFromA, step.liquidityFromB) >= 0
+		}
+		lo := big.NewInt(0)
+		hi := new(big.Int).Set(surplus)
+		for new(big.Int).Sub(hi, lo).Cmp(big.NewInt(1)) > 0 {
+			mid := new(big.Int).Add(lo, hi)
+			mid.Rsh(mid, 1)
+			step, err := simulate(swapAToB, mid)
+			if err != nil || balanced(step) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		for _, candidate := range []*big.Int{lo, hi} {
+			step, err := simulate(swapAToB, candidate)
+			if err != nil {
+				continue
+			}
+			if minLiquidity(step.liquidityFromA, step.liquidityFromB).Cmp(minLiquidity(best.liquidityFromA, best.liquidityFromB)) > 0 {
+				best = step
+				swapInAmount = candidate
+			}
+		}
+	}
+
+	quote.SwapAToB = swapAToB
+	quote.SwapInAmount = swapInAmount
+	quote.SwapOutAmount = best.swapOutAmount
+	quote.MinSwapOutAmount = best.minSwapOutAmount
+	quote.PriceImpact = best.priceImpact
+	quote.LiquidityDelta = minLiquidity(best.liquidityFromA, best.liquidityFromB)
+	quote.MaxAmountTokenA = best.amountA
+	quote.MaxAmountTokenB = best.amountB
+	quote.ShouldCompound = quote.LiquidityDelta.Sign() > 0
+	if params.Policy != nil && params.Policy.MinFeeValue != nil && feeValue.Cmp(params.Policy.MinFeeValue) < 0 {
+		quote.ShouldCompound = false
+	}
+	return quote, nil
+}
+
+// CompoundPositionFee builds a transaction that claims position fees and adds them back as liquidity.
+func (c *CpAmm) CompoundPositionFee(ctx context.Context, params CompoundPositionFeeParams) (TxBuilder, CompoundQuote, error) {
+	poolState := params.PoolState
+	quote, err := c.GetCompoundQuote(GetCompoundQuoteParams{
+		PoolState:       poolState,
+		PositionState:   params.PositionState,
+		CurrentPoint:    params.CurrentPoint,
+		Slippage:        params.Slippage,
+		TokenATokenInfo: params.TokenATokenInfo,
+		TokenBTokenInfo: params.TokenBTokenInfo,
+		TokenADecimal:   params.TokenADecimal,
+		TokenBDecimal:   params.TokenBDecimal,
+		Policy:          params.Policy,
+	})
+	if err != nil {
+		return nil, CompoundQuote{}, err
+	}
+	if !quote.ShouldCompound {
+		return nil, quote, errors.New("position fee is below compound threshold")
+	}
+
+	payer := params.Owner
+	if params.FeePayer != nil {
+		payer = *params.FeePayer
+	}
+	tokenAProgram := helpers.GetTokenProgram(poolState.TokenAFlag)
+	tokenBProgram := helpers.GetTokenProgram(poolState.TokenBFlag)
+	tokenAAccount, tokenBAccount, preIxs, err := c.prepareTokenAccounts(ctx, PrepareTokenAccountParams{
+		Payer:         payer,
+		TokenAOwner:   params.Owner,
+		TokenBOwner:   params.Owner,
+		TokenAMint:    poolState.TokenAMint,
+		TokenBMint:    poolState.TokenBMint,
+		TokenAProgram: tokenAProgram,
+		TokenBProgram: tokenBProgram,
+	})
+	if err != nil {
+		return nil, CompoundQuote{}, err
+	}
+	claimIx, err := c.buildClaimPositionFeeInstruction(ClaimPositionFeeInstructionParams{
+		Owner:              params.Owner,
+		PoolAuthority:      c.PoolAuthority,
+		Pool:               params.Pool,
+		Position:           params.Position,
+		PositionNftAccount: params.PositionNftAccount,
+		TokenAAccount:      tokenAAccount,
+		TokenBAccount:      tokenBAccount,
+		PoolState:          poolState,
+	})
+	if err != nil {
+		return nil, CompoundQuote{}, err
+	}
+
+	builder := solanago.NewTransactionBuilder()
+	for _, ix := range preIxs {
+		builder.AddInstruction(ix)
+	}
+	builder.AddInstruction(claimIx)
+
+	if quote.SwapInAmount.Sign() > 0 {
+		swapInAmount, err := toU64(quote.SwapInAmount, "compound swap amount in")
+		if err != nil {
+			return nil, CompoundQuote{}, err
+		}
+		minSwapOutAmount, err := toU64(quote.MinSwapOutAmount, "compound swap minimum amount out")
+		if err != nil {
+			return nil, CompoundQuote{}, err
+		}
+		inputTokenAccount, outputTokenAccount := tokenAAccount, tokenBAccount
+		tradeDirection := TradeDirectionAtoB
+		if !quote.SwapAToB {
+			inputTokenAccount, outputTokenAccount = tokenBAccount, tokenAAccount
+			tradeDirection = TradeDirectionBtoA
+		}
+		rateLimiterApplied, err := c.isRateLimiterApplied(ctx, poolState, tradeDirection)
+		if err != nil {
+			return nil, CompoundQuote{}, err
+		}
+		swapIx, err := dammv2gen.NewSwap2Instruction(
+			dammv2gen.SwapParameters2{
+				Amount0:  swapInAmount,
+				Amount1:  minSwapOutAmount,
+				SwapMode: uint8(SwapModeExactIn),
+			},
+			c.PoolAuthority,
+			params.Pool,
+			inputTokenAccount,
+			outputTokenAccount,
+			poolState.TokenAVault,
+			poolState.TokenBVault,
+			poolState.TokenAMint,
+			poolState.TokenBMint,
+			params.Owner,
+			tokenAProgram,
+			tokenBProgram,
+			optionalPubkey(nil),
+			c.EventAuthority,
+			dammv2gen.ProgramID,
+		)
+		if err != nil {
+			return nil, CompoundQuote{}, err
+		}
+		if rateLimiterApplied {
+			if err := appendRemainingAccounts(swapIx, []*solanago.AccountMeta{solanago.NewAccountMeta(solanago.SysVarInstructionsPubkey, false, false)}); err != nil {
+				return nil, CompoundQuote{}, err
+			}
+		}
+		builder.AddInstruction(swapIx)
+	}
+
+	addIx, err := c.buildAddLiquidityInstruction(BuildAddLiquidityParams{
+		Pool:                  params.Pool,
+		Position:              params.Position,
+		PositionNftAccount:    params.PositionNftAccount,
+		Owner:                 params.Owner,
+		TokenAAccount:         tokenAAccount,
+		TokenBAccount:         tokenBAccount,
+		TokenAMint:            poolState.TokenAMint,
+		TokenBMint:            poolState.TokenBMint,
+		TokenAVault:           poolState.TokenAVault,
+		TokenBVault:           poolState.TokenBVault,
+		TokenAProgram:         tokenAProgram,
+		TokenBProgram:         tokenBProgram,
+		LiquidityDelta:        quote.LiquidityDelta,
+		TokenAAmountThreshold: quote.MaxAmountTokenA,
+		TokenBAmountThreshold: quote.MaxAmountTokenB,
+	})
+	if err != nil {
+		return nil, CompoundQuote{}, err
+	}
+	builder.AddInstruction(addIx)
+
+	if poolState.TokenAMint.Equals(helpers.NativeMint) || poolState.TokenBMint.Equals(helpers.NativeMint) {
+		closeIx, _ := helpers.UnwrapSOLInstruction(params.Owner, params.Owner, true)
+		if closeIx != nil {
+			builder.AddInstruction(closeIx)
+		}
+	}
+	return builder, quote, nil
+}
+
+// depositLiquidity returns the liquidity each token amount supports at sqrtPrice.
+// A nil result means the token is not required at that price.
+func depositLiquidity(poolState *PoolState, sqrtPrice, amountA, amountB *big.Int, tokenAInfo, tokenBInfo *TokenInfo) (liquidityFromA, liquidityFromB *big.Int) {
+	if tokenAInfo != nil {
+		amountA = helpers.CalculateTransferFeeExcludedAmount(amountA, tokenAInfo).Amount
+	}
+	if tokenBInfo != nil {
+		amountB = helpers.CalculateTransferFeeExcludedAmount(amountB, tokenBInfo).Amount
+	}
+	if sqrtPrice.Cmp(poolState.SqrtMaxPrice.BigInt()) < 0 {
+		liquidityFromA = math.GetLiquidityDeltaFromAmountA(amountA, sqrtPrice, poolState.SqrtMaxPrice.BigInt())
+	}
+	if sqrtPrice.Cmp(poolState.SqrtMinPrice.BigInt()) > 0 {
+		liquidityFromB = math.GetLiquidityDeltaFromAmountB(amountB, poolState.SqrtMinPrice.BigInt(), sqrtPrice)
+	}
+	return
+}
+
+// compareLiquidity compares two liquidity amounts where nil is unbounded.
+func compareLiquidity(a, b *big.Int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Cmp(b)
+}
+
+// minLiquidity returns the smaller bounded liquidity, or zero when both are unbounded.
+func minLiquidity(a, b *big.Int) *big.Int {
+	switch {
+	case a == nil && b == nil:
+		return big.NewInt(0)
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return minBig(a, b)
+}
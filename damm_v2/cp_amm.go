@@ -13,6 +13,7 @@ import (
 
 	"github.com/krazyTry/meteora-go/damm_v2/helpers"
 	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	"github.com/krazyTry/meteora-go/internal/checked"
 )
 
 // CpAmm SDK class to interact with DAMM-V2.
@@ -61,10 +62,21 @@ func (c *CpAmm) getTokenBadgeAccounts(tokenAMint, tokenBMint solanago.PublicKey)
 
 // buildAddLiquidityInstruction builds an add liquidity instruction.
 func (c *CpAmm) buildAddLiquidityInstruction(params BuildAddLiquidityParams) (solanago.Instruction, error) {
+	if err := checked.AllU128("add liquidity delta", params.LiquidityDelta); err != nil {
+		return nil, err
+	}
+	tokenAAmountThreshold, err := toU64(params.TokenAAmountThreshold, "add liquidity token a threshold")
+	if err != nil {
+		return nil, err
+	}
+	tokenBAmountThreshold, err := toU64(params.TokenBAmountThreshold, "add liquidity token b threshold")
+	if err != nil {
+		return nil, err
+	}
 	ixParams := dammv2gen.AddLiquidityParameters{
 		LiquidityDelta:        u128FromBig(params.LiquidityDelta),
-		TokenAAmountThreshold: toU64(params.TokenAAmountThreshold),
-		TokenBAmountThreshold: toU64(params.TokenBAmountThreshold),
+		TokenAAmountThreshold: tokenAAmountThreshold,
+		TokenBAmountThreshold: tokenBAmountThreshold,
 	}
 	return dammv2gen.NewAddLiquidityInstruction(
 		ixParams,
@@ -87,9 +99,17 @@ func (c *CpAmm) buildAddLiquidityInstruction(params BuildAddLiquidityParams) (so
 
 // buildRemoveAllLiquidityInstruction builds remove all liquidity instruction.
 func (c *CpAmm) buildRemoveAllLiquidityInstruction(params BuildRemoveAllLiquidityInstructionParams) (solanago.Instruction, error) {
+	tokenAAmountThreshold, err := toU64(params.TokenAAmountThreshold, "remove all liquidity token a threshold")
+	if err != nil {
+		return nil, err
+	}
+	tokenBAmountThreshold, err := toU64(params.TokenBAmountThreshold, "remove all liquidity token b threshold")
+	if err != nil {
+		return nil, err
+	}
 	return dammv2gen.NewRemoveAllLiquidityInstruction(
-		toU64(params.TokenAAmountThreshold),
-		toU64(params.TokenBAmountThreshold),
+		tokenAAmountThreshold,
+		tokenBAmountThreshold,
 		params.PoolAuthority,
 		params.Pool,
 		params.Position,
@@ -260,6 +280,14 @@ func (c *CpAmm) buildCreatePositionInstruction(params CreatePositionParams) (sol
 
 // prepareCreatePoolParams prepares common pool creation params.
 func (c *CpAmm) prepareCreatePoolParams(ctx context.Context, params PrepareCustomizablePoolParams) (PreparedCreatePoolInternal, error) {
+	tokenAAmount, err := toU64(params.TokenAAmount, "create pool token a amount")
+	if err != nil {
+		return PreparedCreatePoolInternal{}, err
+	}
+	tokenBAmount, err := toU64(params.TokenBAmount, "create pool token b amount")
+	if err != nil {
+		return PreparedCreatePoolInternal{}, err
+	}
 	position := DerivePositionAddress(params.PositionNft)
 	positionNftAccount := DerivePositionNftAccount(params.PositionNft)
 	tokenAVault := DeriveTokenVaultAddress(params.TokenAMint, params.Pool)
@@ -279,11 +307,11 @@ func (c *CpAmm) prepareCreatePoolParams(ctx context.Context, params PrepareCusto
 	}
 
 	if params.TokenAMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, payerTokenA, toU64(params.TokenAAmount))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, payerTokenA, tokenAAmount)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	if params.TokenBMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, payerTokenB, toU64(params.TokenBAmount))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, payerTokenB, tokenBAmount)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 
@@ -693,11 +721,9 @@ func totalPositionLiquidity(position *PositionState) *big.Int {
 	return out
 }
 
-func toU64(v *big.Int) uint64 {
-	if v == nil {
-		return 0
-	}
-	return v.Uint64()
+// toU64 narrows an instruction amount to u64, failing with a MathOverflowError naming op. A nil v is zero.
+func toU64(v *big.Int, op string) (uint64, error) {
+	return checked.U64(v, op)
 }
 
 // Internal helper types.
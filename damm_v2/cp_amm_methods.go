@@ -9,9 +9,8 @@ import (
 
 	"github.com/krazyTry/meteora-go/damm_v2/helpers"
 	"github.com/krazyTry/meteora-go/damm_v2/math"
-	"github.com/krazyTry/meteora-go/damm_v2/math/pool_fees"
-	"github.com/krazyTry/meteora-go/damm_v2/shared"
 	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	"github.com/krazyTry/meteora-go/internal/checked"
 )
 
 // IsPoolExist checks whether a pool account exists.
@@ -87,19 +86,23 @@ func (c *CpAmm) GetQuote2(params GetQuote2Params) (Quote2Result, error) {
 }
 
 // GetDepositQuote calculates the deposit quote.
-func (c *CpAmm) GetDepositQuote(params GetDepositQuoteParams) DepositQuote {
+func (c *CpAmm) GetDepositQuote(params GetDepositQuoteParams) (DepositQuote, error) {
 	actualAmountIn := params.InAmount
 	if params.InputTokenInfo != nil {
 		actualAmountIn = helpers.CalculateTransferFeeExcludedAmount(params.InAmount, params.InputTokenInfo).Amount
 	}
 	var liquidityDelta *big.Int
 	var rawOutputAmount *big.Int
+	var err error
 	if params.IsTokenA {
 		liquidityDelta = math.GetLiquidityDeltaFromAmountA(actualAmountIn, params.SqrtPrice, params.MaxSqrtPrice)
-		rawOutputAmount = math.GetAmountBFromLiquidityDelta(params.MinSqrtPrice, params.SqrtPrice, liquidityDelta, RoundingUp)
+		rawOutputAmount, err = math.GetAmountBFromLiquidityDelta(params.MinSqrtPrice, params.SqrtPrice, liquidityDelta, RoundingUp)
 	} else {
 		liquidityDelta = math.GetLiquidityDeltaFromAmountB(actualAmountIn, params.MinSqrtPrice, params.SqrtPrice)
-		rawOutputAmount = math.GetAmountAFromLiquidityDelta(params.SqrtPrice, params.MaxSqrtPrice, liquidityDelta, RoundingUp)
+		rawOutputAmount, err = math.GetAmountAFromLiquidityDelta(params.SqrtPrice, params.MaxSqrtPrice, liquidityDelta, RoundingUp)
+	}
+	if err != nil {
+		return DepositQuote{}, err
 	}
 	outputAmount := new(big.Int).Set(rawOutputAmount)
 	if params.OutputTokenInfo != nil {
@@ -110,13 +113,19 @@ func (c *CpAmm) GetDepositQuote(params GetDepositQuoteParams) DepositQuote {
 		ConsumedInputAmount: params.InAmount,
 		LiquidityDelta:      liquidityDelta,
 		OutputAmount:        outputAmount,
-	}
+	}, nil
 }
 
 // GetWithdrawQuote calculates the withdraw quote.
-func (c *CpAmm) GetWithdrawQuote(params GetWithdrawQuoteParams) WithdrawQuote {
-	amountA := math.GetAmountAFromLiquidityDelta(params.SqrtPrice, params.MaxSqrtPrice, params.LiquidityDelta, RoundingDown)
-	amountB := math.GetAmountBFromLiquidityDelta(params.MinSqrtPrice, params.SqrtPrice, params.LiquidityDelta, RoundingDown)
+func (c *CpAmm) GetWithdrawQuote(params GetWithdrawQuoteParams) (WithdrawQuote, error) {
+	amountA, err := math.GetAmountAFromLiquidityDelta(params.SqrtPrice, params.MaxSqrtPrice, params.LiquidityDelta, RoundingDown)
+	if err != nil {
+		return WithdrawQuote{}, err
+	}
+	amountB, err := math.GetAmountBFromLiquidityDelta(params.MinSqrtPrice, params.SqrtPrice, params.LiquidityDelta, RoundingDown)
+	if err != nil {
+		return WithdrawQuote{}, err
+	}
 	outA := amountA
 	outB := amountB
 	if params.TokenATokenInfo != nil {
@@ -129,7 +138,7 @@ func (c *CpAmm) GetWithdrawQuote(params GetWithdrawQuoteParams) WithdrawQuote {
 		LiquidityDelta: params.LiquidityDelta,
 		OutAmountA:     outA,
 		OutAmountB:     outB,
-	}
+	}, nil
 }
 
 // PreparePoolCreationSingleSide calculates liquidity for single-sided creation.
@@ -190,11 +199,18 @@ func (c *CpAmm) CreatePool(ctx context.Context, params CreatePoolParams) (TxBuil
 	if err != nil {
 		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
 	}
+	if err := checked.AllU128("initialize pool", params.LiquidityDelta, params.InitSqrtPrice); err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
+	activationPoint, err := toU64Ptr(params.ActivationPoint, "initialize pool activation point")
+	if err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
 
 	initParams := dammv2gen.InitializePoolParameters{
 		Liquidity:       u128FromBig(params.LiquidityDelta),
 		SqrtPrice:       u128FromBig(params.InitSqrtPrice),
-		ActivationPoint: toU64Ptr(params.ActivationPoint),
+		ActivationPoint: activationPoint,
 	}
 	initIx, err := dammv2gen.NewInitializePoolInstruction(
 		initParams,
@@ -273,6 +289,13 @@ func (c *CpAmm) CreateCustomPool(ctx context.Context, params InitializeCustomize
 	if err != nil {
 		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
 	}
+	if err := checked.AllU128("initialize customizable pool", params.SqrtMinPrice, params.SqrtMaxPrice, params.LiquidityDelta, params.InitSqrtPrice); err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
+	activationPoint, err := toU64Ptr(params.ActivationPoint, "initialize customizable pool activation point")
+	if err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
 
 	poolFees := dammv2gen.PoolFeeParameters{
 		BaseFee:    params.PoolFees.BaseFee,
@@ -287,7 +310,7 @@ func (c *CpAmm) CreateCustomPool(ctx context.Context, params InitializeCustomize
 		SqrtPrice:       u128FromBig(params.InitSqrtPrice),
 		ActivationType:  uint8(params.ActivationType),
 		CollectFeeMode:  uint8(params.CollectFeeMode),
-		ActivationPoint: toU64Ptr(params.ActivationPoint),
+		ActivationPoint: activationPoint,
 	}
 	initIx, err := dammv2gen.NewInitializeCustomizablePoolInstruction(
 		initParams,
@@ -358,6 +381,13 @@ func (c *CpAmm) CreateCustomPoolWithDynamicConfig(ctx context.Context, params In
 	if err != nil {
 		return nil, solanago.PublicKey{}, solanago.PublicKey{}, err
 	}
+	if err := checked.AllU128("initialize pool with dynamic config", params.SqrtMinPrice, params.SqrtMaxPrice, params.LiquidityDelta, params.InitSqrtPrice); err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
+	activationPoint, err := toU64Ptr(params.ActivationPoint, "initialize pool with dynamic config activation point")
+	if err != nil {
+		return nil, solanago.PublicKey{}, solanago.PublicKey{}, err
+	}
 	poolFees := dammv2gen.PoolFeeParameters{
 		BaseFee:    params.PoolFees.BaseFee,
 		DynamicFee: params.PoolFees.DynamicFee,
@@ -371,7 +401,7 @@ func (c *CpAmm) CreateCustomPoolWithDynamicConfig(ctx context.Context, params In
 		SqrtPrice:       u128FromBig(params.InitSqrtPrice),
 		ActivationType:  uint8(params.ActivationType),
 		CollectFeeMode:  uint8(params.CollectFeeMode),
-		ActivationPoint: toU64Ptr(params.ActivationPoint),
+		ActivationPoint: activationPoint,
 	}
 	initIx, err := dammv2gen.NewInitializePoolWithDynamicConfigInstruction(
 		initParams,
@@ -439,6 +469,14 @@ func (c *CpAmm) CreatePosition(ctx context.Context, params CreatePositionParams)
 
 // AddLiquidity builds a transaction to add liquidity.
 func (c *CpAmm) AddLiquidity(ctx context.Context, params AddLiquidityParams) (TxBuilder, error) {
+	maxAmountTokenA, err := toU64(params.MaxAmountTokenA, "add liquidity max token a amount")
+	if err != nil {
+		return nil, err
+	}
+	maxAmountTokenB, err := toU64(params.MaxAmountTokenB, "add liquidity max token b amount")
+	if err != nil {
+		return nil, err
+	}
 	tokenAProgram := helpers.GetTokenProgram(params.PoolState.TokenAFlag)
 	tokenBProgram := helpers.GetTokenProgram(params.PoolState.TokenBFlag)
 
@@ -455,11 +493,11 @@ func (c *CpAmm) AddLiquidity(ctx context.Context, params AddLiquidityParams) (Tx
 		return nil, err
 	}
 	if params.PoolState.TokenAMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenAAccount, toU64(params.MaxAmountTokenA))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenAAccount, maxAmountTokenA)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	if params.PoolState.TokenBMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenBAccount, toU64(params.MaxAmountTokenB))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenBAccount, maxAmountTokenB)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	var postIxs []solanago.Instruction
@@ -502,6 +540,14 @@ func (c *CpAmm) AddLiquidity(ctx context.Context, params AddLiquidityParams) (Tx
 
 // CreatePositionAndAddLiquidity builds a transaction to create position and add liquidity.
 func (c *CpAmm) CreatePositionAndAddLiquidity(ctx context.Context, params CreatePositionAndAddLiquidity) (TxBuilder, error) {
+	maxAmountTokenA, err := toU64(params.MaxAmountTokenA, "add liquidity max token a amount")
+	if err != nil {
+		return nil, err
+	}
+	maxAmountTokenB, err := toU64(params.MaxAmountTokenB, "add liquidity max token b amount")
+	if err != nil {
+		return nil, err
+	}
 	tokenAAccount, tokenBAccount, preIxs, err := c.prepareTokenAccounts(ctx, PrepareTokenAccountParams{
 		Payer:         params.Owner,
 		TokenAOwner:   params.Owner,
@@ -517,11 +563,11 @@ func (c *CpAmm) CreatePositionAndAddLiquidity(ctx context.Context, params Create
 	tokenAVault := DeriveTokenVaultAddress(params.TokenAMint, params.Pool)
 	tokenBVault := DeriveTokenVaultAddress(params.TokenBMint, params.Pool)
 	if params.TokenAMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenAAccount, toU64(params.MaxAmountTokenA))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenAAccount, maxAmountTokenA)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	if params.TokenBMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenBAccount, toU64(params.MaxAmountTokenB))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Owner, tokenBAccount, maxAmountTokenB)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	var postIxs []solanago.Instruction
@@ -574,6 +620,17 @@ func (c *CpAmm) CreatePositionAndAddLiquidity(ctx context.Context, params Create
 
 // RemoveLiquidity builds a transaction to remove liquidity.
 func (c *CpAmm) RemoveLiquidity(ctx context.Context, params RemoveLiquidityParams) (TxBuilder, error) {
+	if err := checked.AllU128("remove liquidity delta", params.LiquidityDelta); err != nil {
+		return nil, err
+	}
+	tokenAAmountThreshold, err := toU64(params.TokenAAmountThreshold, "remove liquidity token a threshold")
+	if err != nil {
+		return nil, err
+	}
+	tokenBAmountThreshold, err := toU64(params.TokenBAmountThreshold, "remove liquidity token b threshold")
+	if err != nil {
+		return nil, err
+	}
 	tokenAProgram := helpers.GetTokenProgram(params.PoolState.TokenAFlag)
 	tokenBProgram := helpers.GetTokenProgram(params.PoolState.TokenBFlag)
 	tokenAAccount, tokenBAccount, preIxs, err := c.prepareTokenAccounts(ctx, PrepareTokenAccountParams{
@@ -614,8 +671,8 @@ func (c *CpAmm) RemoveLiquidity(ctx context.Context, params RemoveLiquidityParam
 	}
 	ixParams := dammv2gen.RemoveLiquidityParameters{
 		LiquidityDelta:        u128FromBig(params.LiquidityDelta),
-		TokenAAmountThreshold: toU64(params.TokenAAmountThreshold),
-		TokenBAmountThreshold: toU64(params.TokenBAmountThreshold),
+		TokenAAmountThreshold: tokenAAmountThreshold,
+		TokenBAmountThreshold: tokenBAmountThreshold,
 	}
 	removeIx, err := dammv2gen.NewRemoveLiquidityInstruction(
 		ixParams,
@@ -723,6 +780,14 @@ func (c *CpAmm) RemoveAllLiquidity(ctx context.Context, params RemoveAllLiquidit
 
 // Swap builds a swap transaction (exact in).
 func (c *CpAmm) Swap(ctx context.Context, params SwapParams) (TxBuilder, error) {
+	amountIn, err := toU64(params.AmountIn, "swap amount in")
+	if err != nil {
+		return nil, err
+	}
+	minimumAmountOut, err := toU64(params.MinimumAmountOut, "swap minimum amount out")
+	if err != nil {
+		return nil, err
+	}
 	tokenAProgram := helpers.GetTokenProgram(params.PoolState.TokenAFlag)
 	tokenBProgram := helpers.GetTokenProgram(params.PoolState.TokenBFlag)
 
@@ -752,7 +817,7 @@ func (c *CpAmm) Swap(ctx context.Context, params SwapParams) (TxBuilder, error)
 		return nil, err
 	}
 	if params.InputTokenMint.Equals(helpers.NativeMint) {
-		wrapIxs, _ := helpers.WrapSOLInstruction(receiver, inputTokenAccount, toU64(params.AmountIn))
+		wrapIxs, _ := helpers.WrapSOLInstruction(receiver, inputTokenAccount, amountIn)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	var postIxs []solanago.Instruction
@@ -770,32 +835,14 @@ func (c *CpAmm) Swap(ctx context.Context, params SwapParams) (TxBuilder, error)
 		}
 		poolState = fetched
 	}
-	data := poolState.PoolFees.BaseFee.BaseFeeInfo.Data[:]
-	baseFeeMode := BaseFeeMode(data[8])
-	rateLimiterApplied := false
-	if baseFeeMode == BaseFeeModeRateLimiter {
-		currentPoint, err := helpers.GetCurrentPoint(ctx, c.Client, shared.ActivationType(poolState.ActivationType))
-		if err != nil {
-			return nil, err
-		}
-		rateLimiterPoolFees, err := helpers.DecodePodAlignedFeeRateLimiter(data)
-		if err != nil {
-			return nil, err
-		}
-		rateLimiterApplied = pool_fees.IsRateLimiterApplied(
-			new(big.Int).SetUint64(rateLimiterPoolFees.ReferenceAmount),
-			rateLimiterPoolFees.MaxLimiterDuration,
-			uint16(rateLimiterPoolFees.MaxFeeBps),
-			rateLimiterPoolFees.FeeIncrementBps,
-			currentPoint,
-			big.NewInt(int64(poolState.ActivationPoint)),
-			tradeDirection,
-		)
+	rateLimiterApplied, err := c.isRateLimiterApplied(ctx, poolState, tradeDirection)
+	if err != nil {
+		return nil, err
 	}
 	swapIx, err := dammv2gen.NewSwapInstruction(
 		dammv2gen.SwapParameters{
-			AmountIn:         toU64(params.AmountIn),
-			MinimumAmountOut: toU64(params.MinimumAmountOut),
+			AmountIn:         amountIn,
+			MinimumAmountOut: minimumAmountOut,
 		},
 		c.PoolAuthority,
 		params.Pool,
@@ -863,26 +910,34 @@ func (c *CpAmm) Swap2(ctx context.Context, params Swap2Params) (TxBuilder, error
 		return nil, err
 	}
 
-	var amount0, amount1 *big.Int
+	var amount0, amount1 uint64
 	if params.SwapMode == SwapModeExactOut {
 		if params.AmountOut == nil || params.MaximumAmountIn == nil {
 			return nil, errors.New("amountOut and maximumAmountIn are required for ExactOut")
 		}
-		amount0 = params.AmountOut
-		amount1 = params.MaximumAmountIn
+		if amount0, err = toU64(params.AmountOut, "swap2 amount out"); err != nil {
+			return nil, err
+		}
+		if amount1, err = toU64(params.MaximumAmountIn, "swap2 maximum amount in"); err != nil {
+			return nil, err
+		}
 	} else {
 		if params.AmountIn == nil || params.MinimumAmountOut == nil {
 			return nil, errors.New("amountIn and minimumAmountOut are required for ExactIn/PartialFill")
 		}
-		amount0 = params.AmountIn
-		amount1 = params.MinimumAmountOut
+		if amount0, err = toU64(params.AmountIn, "swap2 amount in"); err != nil {
+			return nil, err
+		}
+		if amount1, err = toU64(params.MinimumAmountOut, "swap2 minimum amount out"); err != nil {
+			return nil, err
+		}
 	}
 	if params.InputTokenMint.Equals(helpers.NativeMint) {
 		wrapAmount := amount0
 		if params.SwapMode == SwapModeExactOut {
 			wrapAmount = amount1
 		}
-		wrapIxs, _ := helpers.WrapSOLInstruction(receiver, inputTokenAccount, toU64(wrapAmount))
+		wrapIxs, _ := helpers.WrapSOLInstruction(receiver, inputTokenAccount, wrapAmount)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	var postIxs []solanago.Instruction
@@ -901,33 +956,15 @@ func (c *CpAmm) Swap2(ctx context.Context, params Swap2Params) (TxBuilder, error
 		}
 		poolState = fetched
 	}
-	data := poolState.PoolFees.BaseFee.BaseFeeInfo.Data[:]
-	baseFeeMode := BaseFeeMode(data[8])
-	rateLimiterApplied := false
-	if baseFeeMode == BaseFeeModeRateLimiter {
-		currentPoint, err := helpers.GetCurrentPoint(ctx, c.Client, shared.ActivationType(poolState.ActivationType))
-		if err != nil {
-			return nil, err
-		}
-		rateLimiterPoolFees, err := helpers.DecodePodAlignedFeeRateLimiter(data)
-		if err != nil {
-			return nil, err
-		}
-		rateLimiterApplied = pool_fees.IsRateLimiterApplied(
-			new(big.Int).SetUint64(rateLimiterPoolFees.ReferenceAmount),
-			rateLimiterPoolFees.MaxLimiterDuration,
-			uint16(rateLimiterPoolFees.MaxFeeBps),
-			rateLimiterPoolFees.FeeIncrementBps,
-			currentPoint,
-			big.NewInt(int64(poolState.ActivationPoint)),
-			tradeDirection,
-		)
+	rateLimiterApplied, err := c.isRateLimiterApplied(ctx, poolState, tradeDirection)
+	if err != nil {
+		return nil, err
 	}
 
 	swapIx, err := dammv2gen.NewSwap2Instruction(
 		dammv2gen.SwapParameters2{
-			Amount0:  toU64(amount0),
-			Amount1:  toU64(amount1),
+			Amount0:  amount0,
+			Amount1:  amount1,
 			SwapMode: uint8(params.SwapMode),
 		},
 		c.PoolAuthority,
@@ -966,9 +1003,20 @@ func (c *CpAmm) Swap2(ctx context.Context, params Swap2Params) (TxBuilder, error
 
 // LockPosition builds a transaction to lock a position.
 func (c *CpAmm) LockPosition(ctx context.Context, params LockPositionParams) (TxBuilder, error) {
+	cliffPoint, err := toU64Ptr(params.CliffPoint, "lock position cliff point")
+	if err != nil {
+		return nil, err
+	}
+	periodFrequency, err := toU64(params.PeriodFrequency, "lock position period frequency")
+	if err != nil {
+		return nil, err
+	}
+	if err := checked.AllU128("lock position vesting liquidity", params.CliffUnlockLiquidity, params.LiquidityPerPeriod); err != nil {
+		return nil, err
+	}
 	vestingParams := dammv2gen.VestingParameters{
-		CliffPoint:           toU64Ptr(params.CliffPoint),
-		PeriodFrequency:      toU64(params.PeriodFrequency),
+		CliffPoint:           cliffPoint,
+		PeriodFrequency:      periodFrequency,
 		CliffUnlockLiquidity: u128FromBig(params.CliffUnlockLiquidity),
 		LiquidityPerPeriod:   u128FromBig(params.LiquidityPerPeriod),
 		NumberOfPeriod:       uint16(params.NumberOfPeriod),
@@ -1015,6 +1063,9 @@ func (c *CpAmm) LockPosition(ctx context.Context, params LockPositionParams) (Tx
 
 // PermanentLockPosition builds a transaction to permanently lock a position.
 func (c *CpAmm) PermanentLockPosition(ctx context.Context, params PermanentLockParams) (TxBuilder, error) {
+	if err := checked.AllU128("permanent lock liquidity", params.UnlockedLiquidity); err != nil {
+		return nil, err
+	}
 	ix, err := dammv2gen.NewPermanentLockPositionInstruction(
 		u128FromBig(params.UnlockedLiquidity),
 		params.Pool,
@@ -1181,8 +1232,14 @@ func (c *CpAmm) MergePosition(ctx context.Context, params MergePositionParams) (
 		preIxs = append(preIxs, refreshIx)
 	}
 
-	tokenAWithdraw := math.GetAmountAFromLiquidityDelta(params.PoolState.SqrtPrice.BigInt(), params.PoolState.SqrtMaxPrice.BigInt(), positionBLiquidityDelta, RoundingDown)
-	tokenBWithdraw := math.GetAmountBFromLiquidityDelta(params.PoolState.SqrtMinPrice.BigInt(), params.PoolState.SqrtPrice.BigInt(), positionBLiquidityDelta, RoundingDown)
+	tokenAWithdraw, err := math.GetAmountAFromLiquidityDelta(params.PoolState.SqrtPrice.BigInt(), params.PoolState.SqrtMaxPrice.BigInt(), positionBLiquidityDelta, RoundingDown)
+	if err != nil {
+		return nil, err
+	}
+	tokenBWithdraw, err := math.GetAmountBFromLiquidityDelta(params.PoolState.SqrtMinPrice.BigInt(), params.PoolState.SqrtPrice.BigInt(), positionBLiquidityDelta, RoundingDown)
+	if err != nil {
+		return nil, err
+	}
 	newLiquidityDelta := c.GetLiquidityDelta(LiquidityDeltaParams{
 		MaxAmountTokenA: tokenAWithdraw,
 		MaxAmountTokenB: tokenBWithdraw,
@@ -1243,6 +1300,10 @@ func (c *CpAmm) MergePosition(ctx context.Context, params MergePositionParams) (
 
 // InitializeReward builds a transaction to initialize reward.
 func (c *CpAmm) InitializeReward(ctx context.Context, params InitializeRewardParams) (TxBuilder, error) {
+	rewardDuration, err := toU64(params.RewardDuration, "initialize reward duration")
+	if err != nil {
+		return nil, err
+	}
 	rewardVault := DeriveRewardVaultAddress(params.Pool, params.RewardIndex)
 	tokenBadge := DeriveTokenBadgeAddress(params.RewardMint)
 	operator := DeriveOperatorAddress(params.Creator)
@@ -1259,7 +1320,7 @@ func (c *CpAmm) InitializeReward(ctx context.Context, params InitializeRewardPar
 	}
 	ix, err := dammv2gen.NewInitializeRewardInstruction(
 		params.RewardIndex,
-		toU64(params.RewardDuration),
+		rewardDuration,
 		params.Funder,
 		c.PoolAuthority,
 		params.Pool,
@@ -1285,6 +1346,14 @@ func (c *CpAmm) InitializeReward(ctx context.Context, params InitializeRewardPar
 
 // InitializeAndFundReward builds a transaction to initialize and fund reward.
 func (c *CpAmm) InitializeAndFundReward(ctx context.Context, params InitializeAndFundReward) (TxBuilder, error) {
+	rewardDuration, err := toU64(params.RewardDuration, "initialize reward duration")
+	if err != nil {
+		return nil, err
+	}
+	amount, err := toU64(params.Amount, "fund reward amount")
+	if err != nil {
+		return nil, err
+	}
 	builder := solanago.NewTransactionBuilder()
 
 	// Initialize reward.
@@ -1304,7 +1373,7 @@ func (c *CpAmm) InitializeAndFundReward(ctx context.Context, params InitializeAn
 	}
 	initIx, err := dammv2gen.NewInitializeRewardInstruction(
 		params.RewardIndex,
-		toU64(params.RewardDuration),
+		rewardDuration,
 		params.Payer,
 		c.PoolAuthority,
 		params.Pool,
@@ -1335,12 +1404,12 @@ func (c *CpAmm) InitializeAndFundReward(ctx context.Context, params InitializeAn
 		preIxs = append(preIxs, createIx)
 	}
 	if params.RewardMint.Equals(helpers.NativeMint) && params.Amount.Sign() > 0 {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, funderTokenAccount, toU64(params.Amount))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Payer, funderTokenAccount, amount)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	fundIx, err := dammv2gen.NewFundRewardInstruction(
 		params.RewardIndex,
-		toU64(params.Amount),
+		amount,
 		params.CarryForward,
 		params.Pool,
 		rewardVault,
@@ -1363,9 +1432,13 @@ func (c *CpAmm) InitializeAndFundReward(ctx context.Context, params InitializeAn
 
 // UpdateRewardDuration builds a transaction to update reward duration.
 func (c *CpAmm) UpdateRewardDuration(ctx context.Context, params UpdateRewardDurationParams) (TxBuilder, error) {
+	newDuration, err := toU64(params.NewDuration, "update reward duration")
+	if err != nil {
+		return nil, err
+	}
 	ix, err := dammv2gen.NewUpdateRewardDurationInstruction(
 		params.RewardIndex,
-		toU64(params.NewDuration),
+		newDuration,
 		params.Pool,
 		params.Signer,
 		c.EventAuthority,
@@ -1399,6 +1472,10 @@ func (c *CpAmm) UpdateRewardFunder(ctx context.Context, params UpdateRewardFunde
 
 // FundReward builds a transaction to fund reward.
 func (c *CpAmm) FundReward(ctx context.Context, params FundRewardParams) (TxBuilder, error) {
+	amount, err := toU64(params.Amount, "fund reward amount")
+	if err != nil {
+		return nil, err
+	}
 	preIxs := []solanago.Instruction{}
 	funderTokenAccount, createIx, err := helpers.GetOrCreateATAInstruction(ctx, c.Client, params.RewardMint, params.Funder, params.Funder, helpers.GetTokenProgram(params.RewardIndex))
 	if err != nil {
@@ -1408,12 +1485,12 @@ func (c *CpAmm) FundReward(ctx context.Context, params FundRewardParams) (TxBuil
 		preIxs = append(preIxs, createIx)
 	}
 	if params.RewardMint.Equals(helpers.NativeMint) && params.Amount.Sign() > 0 {
-		wrapIxs, _ := helpers.WrapSOLInstruction(params.Funder, funderTokenAccount, toU64(params.Amount))
+		wrapIxs, _ := helpers.WrapSOLInstruction(params.Funder, funderTokenAccount, amount)
 		preIxs = append(preIxs, wrapIxs...)
 	}
 	ix, err := dammv2gen.NewFundRewardInstruction(
 		params.RewardIndex,
-		toU64(params.Amount),
+		amount,
 		params.CarryForward,
 		params.Pool,
 		params.RewardVault,
@@ -1486,6 +1563,14 @@ func (c *CpAmm) WithdrawIneligibleReward(ctx context.Context, params WithdrawIne
 
 // ClaimPartnerFee builds a transaction to claim partner fee.
 func (c *CpAmm) ClaimPartnerFee(ctx context.Context, params ClaimPartnerFeeParams) (TxBuilder, error) {
+	maxAmountA, err := toU64(params.MaxAmountA, "claim partner fee max amount a")
+	if err != nil {
+		return nil, err
+	}
+	maxAmountB, err := toU64(params.MaxAmountB, "claim partner fee max amount b")
+	if err != nil {
+		return nil, err
+	}
 	poolState, err := c.FetchPoolState(ctx, params.Pool)
 	if err != nil {
 		return nil, err
@@ -1510,8 +1595,8 @@ func (c *CpAmm) ClaimPartnerFee(ctx context.Context, params ClaimPartnerFeeParam
 		return nil, err
 	}
 	ix, err := dammv2gen.NewClaimPartnerFeeInstruction(
-		toU64(params.MaxAmountA),
-		toU64(params.MaxAmountB),
+		maxAmountA,
+		maxAmountB,
 		c.PoolAuthority,
 		params.Pool,
 		tokenAAccount,
@@ -1766,12 +1851,16 @@ func appendRemainingAccounts(ix solanago.Instruction, metas []*solanago.AccountM
 	return nil
 }
 
-func toU64Ptr(v *big.Int) *uint64 {
+// toU64Ptr narrows an optional instruction value to u64 like toU64; a nil v stays nil.
+func toU64Ptr(v *big.Int, op string) (*uint64, error) {
 	if v == nil {
-		return nil
+		return nil, nil
+	}
+	val, err := toU64(v, op)
+	if err != nil {
+		return nil, err
 	}
-	val := v.Uint64()
-	return &val
+	return &val, nil
 }
 
 func minBig(a, b *big.Int) *big.Int {
@@ -0,0 +1,149 @@
+package dammv2
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+)
+
+// GetDepthLadder calculates the cumulative amounts tradable between the current price and each price level or band.
+// Levels beyond the pool price range are clamped to SqrtMinPrice or SqrtMaxPrice.
+func (c *CpAmm) GetDepthLadder(params GetDepthLadderParams) (DepthLadder, error) {
+	poolState := params.PoolState
+	sqrtPrice := poolState.SqrtPrice.BigInt()
+	ladder := DepthLadder{
+		Price:     helpers.GetPriceFromSqrtPrice(sqrtPrice, params.TokenADecimal, params.TokenBDecimal),
+		SqrtPrice: sqrtPrice,
+		Bids:      make([]DepthLevel, 0),
+		Asks:      make([]DepthLevel, 0),
+	}
+
+	priceX128 := new(big.Int).Mul(sqrtPrice, sqrtPrice)
+	for _, bps := range params.BandsBps {
+		if bps == 0 {
+			continue
+		}
+		up := new(big.Int).Mul(priceX128, big.NewInt(int64(shared.BasisPointMax)+int64(bps)))
+		up.Div(up, big.NewInt(shared.BasisPointMax))
+		ask, err := depthLevel(poolState, params.CurrentPoint, math.Sqrt(up), TradeDirectionBtoA)
+		if err != nil {
+			return DepthLadder{}, err
+		}
+		ask.BandBps = bps
+		ladder.Asks = append(ladder.Asks, ask)
+
+		targetSqrtPrice := big.NewInt(0)
+		if int64(bps) < shared.BasisPointMax {
+			down := new(big.Int).Mul(priceX128, big.NewInt(int64(shared.BasisPointMax)-int64(bps)))
+			down.Div(down, big.NewInt(shared.BasisPointMax))
+			targetSqrtPrice = math.Sqrt(down)
+		}
+		bid, err := depthLevel(poolState, params.CurrentPoint, targetSqrtPrice, TradeDirectionAtoB)
+		if err != nil {
+			return DepthLadder{}, err
+		}
+		bid.BandBps = bps
+		ladder.Bids = append(ladder.Bids, bid)
+	}
+
+	for _, price := range params.PriceLevels {
+		targetSqrtPrice := helpers.GetSqrtPriceFromPrice(price, params.TokenADecimal, params.TokenBDecimal)
+		switch targetSqrtPrice.Cmp(sqrtPrice) {
+		case 1:
+			ask, err := depthLevel(poolState, params.CurrentPoint, targetSqrtPrice, TradeDirectionBtoA)
+			if err != nil {
+				return DepthLadder{}, err
+			}
+			ladder.Asks = append(ladder.Asks, ask)
+		case -1:
+			bid, err := depthLevel(poolState, params.CurrentPoint, targetSqrtPrice, TradeDirectionAtoB)
+			if err != nil {
+				return DepthLadder{}, err
+			}
+			ladder.Bids = append(ladder.Bids, bid)
+		}
+	}
+
+	for i := range ladder.Asks {
+		ladder.Asks[i].Price = helpers.GetPriceFromSqrtPrice(ladder.Asks[i].SqrtPrice, params.TokenADecimal, params.TokenBDecimal)
+	}
+	for i := range ladder.Bids {
+		ladder.Bids[i].Price = helpers.GetPriceFromSqrtPrice(ladder.Bids[i].SqrtPrice, params.TokenADecimal, params.TokenBDecimal)
+	}
+	sort.SliceStable(ladder.Asks, func(i, j int) bool {
+		return ladder.Asks[i].SqrtPrice.Cmp(ladder.Asks[j].SqrtPrice) < 0
+	})
+	sort.SliceStable(ladder.Bids, func(i, j int) bool {
+		return ladder.Bids[i].SqrtPrice.Cmp(ladder.Bids[j].SqrtPrice) > 0
+	})
+	return ladder, nil
+}
+
+// depthLevel calculates the fee-adjusted trade that moves the pool price to targetSqrtPrice.
+func depthLevel(poolState *PoolState, currentPoint, targetSqrtPrice *big.Int, tradeDirection TradeDirection) (DepthLevel, error) {
+	sqrtPrice := poolState.SqrtPrice.BigInt()
+	minSqrtPrice := poolState.SqrtMinPrice.BigInt()
+	maxSqrtPrice := poolState.SqrtMaxPrice.BigInt()
+	liquidity := poolState.Liquidity.BigInt()
+
+	level := DepthLevel{SqrtPrice: targetSqrtPrice}
+	if tradeDirection == TradeDirectionBtoA && targetSqrtPrice.Cmp(maxSqrtPrice) > 0 {
+		level.SqrtPrice, level.Clamped = maxSqrtPrice, true
+	}
+	if tradeDirection == TradeDirectionAtoB && targetSqrtPrice.Cmp(minSqrtPrice) < 0 {
+		level.SqrtPrice, level.Clamped = minSqrtPrice, true
+	}
+
+	var amountIn, amountOut *big.Int
+	var err error
+	if tradeDirection == TradeDirectionBtoA {
+		if amountIn, err = math.GetAmountBFromLiquidityDelta(sqrtPrice, level.SqrtPrice, liquidity, RoundingUp); err != nil {
+			return DepthLevel{}, err
+		}
+		if amountOut, err = math.GetAmountAFromLiquidityDelta(sqrtPrice, level.SqrtPrice, liquidity, RoundingDown); err != nil {
+			return DepthLevel{}, err
+		}
+	} else {
+		if amountIn, err = math.GetAmountAFromLiquidityDelta(level.SqrtPrice, sqrtPrice, liquidity, RoundingUp); err != nil {
+			return DepthLevel{}, err
+		}
+		if amountOut, err = math.GetAmountBFromLiquidityDelta(level.SqrtPrice, sqrtPrice, liquidity, RoundingDown); err != nil {
+			return DepthLevel{}, err
+		}
+	}
+
+	maxFeeNumerator := math.GetMaxFeeNumerator(PoolVersion(poolState.Version))
+	activationPoint := new(big.Int).SetUint64(poolState.ActivationPoint)
+	initSqrtPrice := poolState.PoolFees.InitSqrtPrice.BigInt()
+	feeMode := math.GetFeeMode(poolState.CollectFeeMode, tradeDirection, false)
+	if feeMode.FeesOnInput {
+		feeNumerator, err := math.GetTotalTradingFeeFromExcludedFeeAmount(poolState.PoolFees, currentPoint, activationPoint, amountIn, tradeDirection, maxFeeNumerator, initSqrtPrice, sqrtPrice)
+		if err != nil {
+			return DepthLevel{}, err
+		}
+		includedAmountIn, feeAmount, err := math.GetIncludedFeeAmount(feeNumerator, amountIn)
+		if err != nil {
+			return DepthLevel{}, err
+		}
+		level.AmountIn, level.AmountOut, level.FeeAmount, level.FeeNumerator = includedAmountIn, amountOut, feeAmount, feeNumerator
+	} else {
+		feeNumerator, err := math.GetTotalTradingFeeFromIncludedFeeAmount(poolState.PoolFees, currentPoint, activationPoint, amountIn, tradeDirection, maxFeeNumerator, initSqrtPrice, sqrtPrice)
+		if err != nil {
+			return DepthLevel{}, err
+		}
+		excludedAmountOut, feeAmount, err := math.GetExcludedFeeAmount(feeNumerator, amountOut)
+		if err != nil {
+			return DepthLevel{}, err
+		}
+		level.AmountIn, level.AmountOut, level.FeeAmount, level.FeeNumerator = amountIn, excludedAmountOut, feeAmount, feeNumerator
+	}
+	if tradeDirection == TradeDirectionBtoA {
+		level.BaseAmount, level.QuoteAmount = level.AmountOut, level.AmountIn
+	} else {
+		level.BaseAmount, level.QuoteAmount = level.AmountIn, level.AmountOut
+	}
+	return level, nil
+}
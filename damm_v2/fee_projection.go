@@ -0,0 +1,151 @@
+package dammv2
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/math/pool_fees"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+)
+
+// maxProjectionPoints bounds the number of points produced by ProjectBaseFee.
+const maxProjectionPoints = 10_000
+
+// ProjectBaseFee returns the base fee as a series over activation points and the point after which it is static.
+// The rate limiter fee is projected for a token B input of Amount, which defaults to its reference amount.
+func (c *CpAmm) ProjectBaseFee(params ProjectBaseFeeParams) (BaseFeeProjection, error) {
+	var (
+		handler         shared.BaseFeeHandler
+		baseFeeMode     BaseFeeMode
+		activationPoint = params.ActivationPoint
+		initSqrtPrice   = params.InitSqrtPrice
+		err             error
+	)
+	switch {
+	case params.PoolState != nil:
+		data := params.PoolState.PoolFees.BaseFee.BaseFeeInfo.Data[:]
+		handler, err = pool_fees.GetBaseFeeHandler(data)
+		if err != nil {
+			return BaseFeeProjection{}, err
+		}
+		baseFeeMode = BaseFeeMode(data[8])
+		activationPoint = new(big.Int).SetUint64(params.PoolState.ActivationPoint)
+		initSqrtPrice = params.PoolState.PoolFees.InitSqrtPrice.BigInt()
+	case params.BaseFee != nil:
+		handler, err = pool_fees.GetBaseFeeHandlerFromParams(params.BaseFee.Data[:])
+		if err != nil {
+			return BaseFeeProjection{}, err
+		}
+		baseFeeMode = BaseFeeMode(params.BaseFee.Data[26])
+	default:
+		return BaseFeeProjection{}, errors.New("pool state or base fee is required")
+	}
+	if activationPoint == nil {
+		activationPoint = big.NewInt(0)
+	}
+	if initSqrtPrice == nil {
+		initSqrtPrice = big.NewInt(0)
+	}
+
+	maxFeeNumerator, err := handler.GetMaxFeeNumerator()
+	if err != nil {
+		return BaseFeeProjection{}, err
+	}
+	projection := BaseFeeProjection{
+		BaseFeeMode:     baseFeeMode,
+		MinFeeNumerator: handler.GetMinFeeNumerator(),
+		MaxFeeNumerator: maxFeeNumerator,
+		StaticPoint:     staticBaseFeePoint(handler, activationPoint),
+		Points:          make([]BaseFeeProjectionPoint, 0),
+		PricePath:       make([]BaseFeeProjectionPoint, 0),
+	}
+
+	amount := params.Amount
+	if rateLimiter, ok := handler.(pool_fees.FeeRateLimiter); ok && amount == nil {
+		amount = rateLimiter.ReferenceAmount
+	}
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+
+	fromPoint := params.FromPoint
+	if fromPoint == nil {
+		fromPoint = activationPoint
+	}
+	toPoint := params.ToPoint
+	if toPoint == nil {
+		toPoint = new(big.Int).Add(projection.StaticPoint, big.NewInt(1))
+	}
+	if toPoint.Cmp(fromPoint) < 0 {
+		return BaseFeeProjection{}, errors.New("to point must not be before from point")
+	}
+	step := params.Step
+	if step == nil {
+		step = new(big.Int).Div(new(big.Int).Sub(toPoint, fromPoint), big.NewInt(100))
+		if timeScheduler, ok := handler.(pool_fees.FeeTimeScheduler); ok && timeScheduler.PeriodFrequency.Sign() > 0 {
+			step = timeScheduler.PeriodFrequency
+		}
+	}
+	if step.Sign() <= 0 {
+		step = big.NewInt(1)
+	}
+	count := new(big.Int).Sub(toPoint, fromPoint)
+	count.Div(count, step)
+	if count.Cmp(big.NewInt(maxProjectionPoints)) >= 0 {
+		return BaseFeeProjection{}, errors.New("too many projection points")
+	}
+
+	for point := new(big.Int).Set(fromPoint); point.Cmp(toPoint) <= 0; point = new(big.Int).Add(point, step) {
+		feeNumerator, err := handler.GetBaseFeeNumeratorFromIncludedFeeAmount(point, activationPoint, TradeDirectionBtoA, amount, initSqrtPrice, initSqrtPrice)
+		if err != nil {
+			return BaseFeeProjection{}, err
+		}
+		projection.Points = append(projection.Points, baseFeeProjectionPoint(point, initSqrtPrice, feeNumerator))
+	}
+	for _, sqrtPrice := range params.SqrtPricePath {
+		feeNumerator, err := handler.GetBaseFeeNumeratorFromIncludedFeeAmount(fromPoint, activationPoint, TradeDirectionBtoA, amount, initSqrtPrice, sqrtPrice)
+		if err != nil {
+			return BaseFeeProjection{}, err
+		}
+		projection.PricePath = append(projection.PricePath, baseFeeProjectionPoint(fromPoint, sqrtPrice, feeNumerator))
+	}
+	return projection, nil
+}
+
+func baseFeeProjectionPoint(point, sqrtPrice, feeNumerator *big.Int) BaseFeeProjectionPoint {
+	return BaseFeeProjectionPoint{
+		Point:        point,
+		SqrtPrice:    sqrtPrice,
+		FeeNumerator: feeNumerator,
+		FeeBps:       decimal.NewFromBigInt(feeNumerator, 0).Mul(decimal.NewFromInt(shared.BasisPointMax)).Div(decimal.NewFromInt(shared.FeeDenominator)),
+	}
+}
+
+// staticBaseFeePoint returns the first point at which the base fee no longer changes.
+func staticBaseFeePoint(handler shared.BaseFeeHandler, activationPoint *big.Int) *big.Int {
+	if handler.ValidateBaseFeeIsStatic(activationPoint, activationPoint) {
+		return new(big.Int).Set(activationPoint)
+	}
+	span := big.NewInt(1)
+	limit := new(big.Int).Lsh(big.NewInt(1), 64)
+	for !handler.ValidateBaseFeeIsStatic(new(big.Int).Add(activationPoint, span), activationPoint) {
+		if span.Cmp(limit) >= 0 {
+			return new(big.Int).Add(activationPoint, limit)
+		}
+		span.Lsh(span, 1)
+	}
+	lo := new(big.Int).Rsh(span, 1)
+	hi := span
+	for new(big.Int).Sub(hi, lo).Cmp(big.NewInt(1)) > 0 {
+		mid := new(big.Int).Add(lo, hi)
+		mid.Rsh(mid, 1)
+		if handler.ValidateBaseFeeIsStatic(new(big.Int).Add(activationPoint, mid), activationPoint) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return new(big.Int).Add(activationPoint, hi)
+}
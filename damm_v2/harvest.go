@@ -0,0 +1,320 @@
+package dammv2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+)
+
+// maxMultipleAccounts is the getMultipleAccounts limit per request.
+const maxMultipleAccounts = 100
+
+type harvestTokenAccount struct {
+	Mint         solanago.PublicKey
+	TokenProgram solanago.PublicKey
+	Account      solanago.PublicKey
+}
+
+// harvestUnit holds the claim instructions of one position, which are never split across transactions.
+type harvestUnit struct {
+	Summary       HarvestPositionSummary
+	Instructions  []solanago.Instruction
+	TokenAccounts []harvestTokenAccount
+}
+
+// HarvestAll builds transactions that claim pending fees and rewards of every position owned by the user.
+// Token accounts are created once, in the first transaction that needs them, so transactions must be sent in order.
+func (c *CpAmm) HarvestAll(ctx context.Context, params HarvestAllParams) ([]HarvestTransaction, error) {
+	positions, err := c.GetPositionsByUser(ctx, params.User)
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) == 0 {
+		return nil, nil
+	}
+	payer := params.User
+	if params.FeePayer != nil {
+		payer = *params.FeePayer
+	}
+	maxTransactionSize := params.MaxTransactionSize
+	if maxTransactionSize <= 0 {
+		maxTransactionSize = helpers.MaxTransactionSize
+	}
+	currentTime := params.CurrentTime
+	if currentTime == nil {
+		currentTime, err = helpers.GetCurrentPoint(ctx, c.Client, ActivationTypeTimestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	poolKeys := make([]solanago.PublicKey, 0, len(positions))
+	seenPools := make(map[solanago.PublicKey]bool)
+	for _, position := range positions {
+		if !seenPools[position.PositionState.Pool] {
+			seenPools[position.PositionState.Pool] = true
+			poolKeys = append(poolKeys, position.PositionState.Pool)
+		}
+	}
+	poolStates := make(map[solanago.PublicKey]*PoolState, len(poolKeys))
+	for start := 0; start < len(poolKeys); start += maxMultipleAccounts {
+		end := min(start+maxMultipleAccounts, len(poolKeys))
+		pools, err := c.GetMultiplePools(ctx, poolKeys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i, pool := range pools {
+			poolStates[poolKeys[start+i]] = pool
+		}
+	}
+
+	units := make([]*harvestUnit, 0, len(positions))
+	tokenAccountKeys := make([]solanago.PublicKey, 0)
+	for _, position := range positions {
+		unit, err := c.buildHarvestUnit(params, position, poolStates[position.PositionState.Pool], currentTime)
+		if err != nil {
+			return nil, err
+		}
+		if len(unit.Instructions) == 0 {
+			continue
+		}
+		units = append(units, unit)
+		for _, account := range unit.TokenAccounts {
+			tokenAccountKeys = append(tokenAccountKeys, account.Account)
+		}
+	}
+	if len(units) == 0 {
+		return nil, nil
+	}
+
+	existing, err := c.fetchExistingAccounts(ctx, tokenAccountKeys)
+	if err != nil {
+		return nil, err
+	}
+	return c.packHarvestUnits(units, params.User, payer, existing, maxTransactionSize)
+}
+
+// buildHarvestUnit builds the fee and reward claims of a position that are above the dust thresholds.
+func (c *CpAmm) buildHarvestUnit(params HarvestAllParams, position *UserPosition, poolState *PoolState, currentTime *big.Int) (*harvestUnit, error) {
+	unit := &harvestUnit{
+		Summary: HarvestPositionSummary{
+			Pool:               position.PositionState.Pool,
+			Position:           position.Position,
+			PositionNftAccount: position.PositionNftAccount,
+			FeeAmountA:         big.NewInt(0),
+			FeeAmountB:         big.NewInt(0),
+		},
+	}
+
+	feeA, feeB, _, err := helpers.GetUnClaimLpFee(poolState, position.PositionState)
+	if err != nil {
+		return nil, err
+	}
+	if aboveThreshold(feeA, params.MinFeeAmount) || aboveThreshold(feeB, params.MinFeeAmount) {
+		tokenAProgram := helpers.GetTokenProgram(poolState.TokenAFlag)
+		tokenBProgram := helpers.GetTokenProgram(poolState.TokenBFlag)
+		tokenAAccount, err := helpers.FindAssociatedTokenAddress(params.User, poolState.TokenAMint, tokenAProgram)
+		if err != nil {
+			return nil, err
+		}
+		tokenBAccount, err := helpers.FindAssociatedTokenAddress(params.User, poolState.TokenBMint, tokenBProgram)
+		if err != nil {
+			return nil, err
+		}
+		ix, err := c.buildClaimPositionFeeInstruction(ClaimPositionFeeInstructionParams{
+			Owner:              params.User,
+			PoolAuthority:      c.PoolAuthority,
+			Pool:               position.PositionState.Pool,
+			Position:           position.Position,
+			PositionNftAccount: position.PositionNftAccount,
+			TokenAAccount:      tokenAAccount,
+			TokenBAccount:      tokenBAccount,
+			PoolState:          poolState,
+		})
+		if err != nil {
+			return nil, err
+		}
+		unit.Instructions = append(unit.Instructions, ix)
+		unit.TokenAccounts = append(unit.TokenAccounts,
+			harvestTokenAccount{Mint: poolState.TokenAMint, TokenProgram: tokenAProgram, Account: tokenAAccount},
+			harvestTokenAccount{Mint: poolState.TokenBMint, TokenProgram: tokenBProgram, Account: tokenBAccount},
+		)
+		unit.Summary.ClaimFee = true
+		unit.Summary.FeeAmountA = feeA
+		unit.Summary.FeeAmountB = feeB
+	}
+
+	for i, rewardInfo := range poolState.RewardInfos {
+		if rewardInfo.Initialized == 0 {
+			continue
+		}
+		_, pending, err := helpers.GetUserRewardPending(poolState, position.PositionState, i, currentTime, big.NewInt(0))
+		if err != nil {
+			return nil, err
+		}
+		if !aboveThreshold(pending, params.MinRewardAmount) {
+			continue
+		}
+		tokenProgram := helpers.GetTokenProgram(rewardInfo.RewardTokenFlag)
+		userTokenAccount, err := helpers.FindAssociatedTokenAddress(params.User, rewardInfo.Mint, tokenProgram)
+		if err != nil {
+			return nil, err
+		}
+		ix, err := dammv2gen.NewClaimRewardInstruction(
+			uint8(i),
+			0,
+			c.PoolAuthority,
+			position.PositionState.Pool,
+			position.Position,
+			rewardInfo.Vault,
+			rewardInfo.Mint,
+			userTokenAccount,
+			position.PositionNftAccount,
+			params.User,
+			tokenProgram,
+			c.EventAuthority,
+			dammv2gen.ProgramID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		unit.Instructions = append(unit.Instructions, ix)
+		unit.TokenAccounts = append(unit.TokenAccounts, harvestTokenAccount{Mint: rewardInfo.Mint, TokenProgram: tokenProgram, Account: userTokenAccount})
+		unit.Summary.RewardIndexes = append(unit.Summary.RewardIndexes, uint8(i))
+		unit.Summary.RewardAmounts = append(unit.Summary.RewardAmounts, pending)
+	}
+	return unit, nil
+}
+
+// packHarvestUnits greedily packs harvest units into the fewest transactions under maxTransactionSize.
+func (c *CpAmm) packHarvestUnits(units []*harvestUnit, user, payer solanago.PublicKey, existing map[solanago.PublicKey]bool, maxTransactionSize int) ([]HarvestTransaction, error) {
+	out := make([]HarvestTransaction, 0)
+	var current []*harvestUnit
+	var currentIxs []solanago.Instruction
+	var currentCreated []solanago.PublicKey
+
+	flush := func() {
+		builder := solanago.NewTransactionBuilder()
+		summaries := make([]HarvestPositionSummary, 0, len(current))
+		for _, ix := range currentIxs {
+			builder.AddInstruction(ix)
+		}
+		for _, unit := range current {
+			summaries = append(summaries, unit.Summary)
+		}
+		for _, account := range currentCreated {
+			existing[account] = true
+		}
+		// the wrapped SOL account is closed at the end of every transaction that used it.
+		for _, unit := range current {
+			for _, account := range unit.TokenAccounts {
+				if account.Mint.Equals(helpers.NativeMint) {
+					existing[account.Account] = false
+				}
+			}
+		}
+		out = append(out, HarvestTransaction{Builder: builder, Positions: summaries})
+		current, currentIxs, currentCreated = nil, nil, nil
+	}
+
+	for _, unit := range units {
+		candidate := append(current[:len(current):len(current)], unit)
+		ixs, created := assembleHarvestInstructions(candidate, user, payer, existing)
+		size, err := helpers.GetTransactionSize(ixs, payer)
+		if err != nil {
+			return nil, err
+		}
+		if size <= maxTransactionSize {
+			current, currentIxs, currentCreated = candidate, ixs, created
+			continue
+		}
+		if len(current) == 0 {
+			return nil, fmt.Errorf("harvest of position %s exceeds transaction size", unit.Summary.Position.String())
+		}
+		flush()
+		ixs, created = assembleHarvestInstructions([]*harvestUnit{unit}, user, payer, existing)
+		size, err = helpers.GetTransactionSize(ixs, payer)
+		if err != nil {
+			return nil, err
+		}
+		if size > maxTransactionSize {
+			return nil, fmt.Errorf("harvest of position %s exceeds transaction size", unit.Summary.Position.String())
+		}
+		current, currentIxs, currentCreated = []*harvestUnit{unit}, ixs, created
+	}
+	if len(current) > 0 {
+		flush()
+	}
+	return out, nil
+}
+
+// assembleHarvestInstructions returns the instructions of a transaction claiming units and the token accounts it creates.
+func assembleHarvestInstructions(units []*harvestUnit, user, payer solanago.PublicKey, existing map[solanago.PublicKey]bool) ([]solanago.Instruction, []solanago.PublicKey) {
+	ixs := make([]solanago.Instruction, 0)
+	created := make([]solanago.PublicKey, 0)
+	seen := make(map[solanago.PublicKey]bool)
+	hasNativeMint := false
+	for _, unit := range units {
+		for _, account := range unit.TokenAccounts {
+			if account.Mint.Equals(helpers.NativeMint) {
+				hasNativeMint = true
+			}
+			if seen[account.Account] {
+				continue
+			}
+			seen[account.Account] = true
+			if !existing[account.Account] {
+				ixs = append(ixs, helpers.CreateAssociatedTokenAccountInstruction(payer, account.Account, user, account.Mint, account.TokenProgram))
+				created = append(created, account.Account)
+			}
+		}
+	}
+	for _, unit := range units {
+		ixs = append(ixs, unit.Instructions...)
+	}
+	if hasNativeMint {
+		closeIx, _ := helpers.UnwrapSOLInstruction(user, user, true)
+		if closeIx != nil {
+			ixs = append(ixs, closeIx)
+		}
+	}
+	return ixs, created
+}
+
+// fetchExistingAccounts reports which of the accounts exist on chain.
+func (c *CpAmm) fetchExistingAccounts(ctx context.Context, accounts []solanago.PublicKey) (map[solanago.PublicKey]bool, error) {
+	unique := make([]solanago.PublicKey, 0, len(accounts))
+	seen := make(map[solanago.PublicKey]bool)
+	for _, account := range accounts {
+		if !seen[account] {
+			seen[account] = true
+			unique = append(unique, account)
+		}
+	}
+	existing := make(map[solanago.PublicKey]bool, len(unique))
+	for start := 0; start < len(unique); start += maxMultipleAccounts {
+		end := min(start+maxMultipleAccounts, len(unique))
+		accs, err := c.Client.GetMultipleAccountsWithOpts(ctx, unique[start:end], &rpc.GetMultipleAccountsOpts{Commitment: c.Commitment})
+		if err != nil {
+			return nil, err
+		}
+		for i, acc := range accs.Value {
+			existing[unique[start+i]] = acc != nil
+		}
+	}
+	return existing, nil
+}
+
+// aboveThreshold reports whether amount is positive and at least threshold.
+func aboveThreshold(amount, threshold *big.Int) bool {
+	if amount == nil || amount.Sign() <= 0 {
+		return false
+	}
+	return threshold == nil || amount.Cmp(threshold) >= 0
+}
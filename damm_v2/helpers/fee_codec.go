@@ -7,14 +7,27 @@ import (
 
 	"github.com/krazyTry/meteora-go/damm_v2/shared"
 	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	"github.com/krazyTry/meteora-go/internal/checked"
 )
 
 func EncodeFeeTimeSchedulerParams(maxBaseFeeNumerator *big.Int, numberOfPeriod uint16, periodFrequency *big.Int, reductionFactor *big.Int, baseFeeMode shared.BaseFeeMode) ([]byte, error) {
+	cliffFeeNumerator, err := toU64(maxBaseFeeNumerator, "fee time scheduler cliff fee numerator")
+	if err != nil {
+		return nil, err
+	}
+	periodFrequencyU64, err := toU64(periodFrequency, "fee time scheduler period frequency")
+	if err != nil {
+		return nil, err
+	}
+	reductionFactorU64, err := toU64(reductionFactor, "fee time scheduler reduction factor")
+	if err != nil {
+		return nil, err
+	}
 	params := dammv2gen.BorshFeeTimeScheduler{
-		CliffFeeNumerator: toU64(maxBaseFeeNumerator),
+		CliffFeeNumerator: cliffFeeNumerator,
 		NumberOfPeriod:    numberOfPeriod,
-		PeriodFrequency:   toU64(periodFrequency),
-		ReductionFactor:   toU64(reductionFactor),
+		PeriodFrequency:   periodFrequencyU64,
+		ReductionFactor:   reductionFactorU64,
 		BaseFeeMode:       uint8(baseFeeMode),
 	}
 	return params.Marshal()
@@ -37,12 +50,20 @@ func DecodePodAlignedFeeTimeScheduler(data []byte) (dammv2gen.PodAlignedFeeTimeS
 }
 
 func EncodeFeeMarketCapSchedulerParams(cliffFeeNumerator *big.Int, numberOfPeriod uint16, sqrtPriceStepBps uint16, schedulerExpirationDuration uint32, reductionFactor *big.Int, baseFeeMode shared.BaseFeeMode) ([]byte, error) {
+	cliffFeeNumeratorU64, err := toU64(cliffFeeNumerator, "fee market cap scheduler cliff fee numerator")
+	if err != nil {
+		return nil, err
+	}
+	reductionFactorU64, err := toU64(reductionFactor, "fee market cap scheduler reduction factor")
+	if err != nil {
+		return nil, err
+	}
 	params := dammv2gen.BorshFeeMarketCapScheduler{
-		CliffFeeNumerator:           toU64(cliffFeeNumerator),
+		CliffFeeNumerator:           cliffFeeNumeratorU64,
 		NumberOfPeriod:              numberOfPeriod,
 		SqrtPriceStepBps:            uint32(sqrtPriceStepBps),
 		SchedulerExpirationDuration: schedulerExpirationDuration,
-		ReductionFactor:             toU64(reductionFactor),
+		ReductionFactor:             reductionFactorU64,
 		BaseFeeMode:                 uint8(baseFeeMode),
 	}
 	return params.Marshal()
@@ -65,12 +86,20 @@ func DecodePodAlignedFeeMarketCapScheduler(data []byte) (dammv2gen.PodAlignedFee
 }
 
 func EncodeFeeRateLimiterParams(cliffFeeNumerator *big.Int, feeIncrementBps uint16, maxLimiterDuration uint32, maxFeeBps uint16, referenceAmount *big.Int) ([]byte, error) {
+	cliffFeeNumeratorU64, err := toU64(cliffFeeNumerator, "fee rate limiter cliff fee numerator")
+	if err != nil {
+		return nil, err
+	}
+	referenceAmountU64, err := toU64(referenceAmount, "fee rate limiter reference amount")
+	if err != nil {
+		return nil, err
+	}
 	params := dammv2gen.BorshFeeRateLimiter{
-		CliffFeeNumerator:  toU64(cliffFeeNumerator),
+		CliffFeeNumerator:  cliffFeeNumeratorU64,
 		FeeIncrementBps:    feeIncrementBps,
 		MaxLimiterDuration: maxLimiterDuration,
 		MaxFeeBps:          uint32(maxFeeBps),
-		ReferenceAmount:    toU64(referenceAmount),
+		ReferenceAmount:    referenceAmountU64,
 		BaseFeeMode:        uint8(shared.BaseFeeModeRateLimiter),
 	}
 	return params.Marshal()
@@ -92,9 +121,7 @@ func DecodePodAlignedFeeRateLimiter(data []byte) (dammv2gen.PodAlignedFeeRateLim
 	return out, nil
 }
 
-func toU64(v *big.Int) uint64 {
-	if v == nil {
-		return 0
-	}
-	return v.Uint64()
+// toU64 narrows an encoded fee parameter to u64, failing with a MathOverflowError naming op. A nil v is zero.
+func toU64(v *big.Int, op string) (uint64, error) {
+	return checked.U64(v, op)
 }
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 
@@ -65,6 +66,31 @@ func CreateAssociatedTokenAccountInstruction(payer, ata, owner, mint, tokenProgr
 	return solanago.NewInstruction(solanago.SPLAssociatedTokenAccountProgramID, accounts, nil)
 }
 
+// TransferCheckedInstruction builds a transfer checked instruction that supports custom token programs (SPL/Token2022).
+func TransferCheckedInstruction(source, mint, destination, owner solanago.PublicKey, amount uint64, decimals uint8, tokenProgram solanago.PublicKey) solanago.Instruction {
+	accounts := solanago.AccountMetaSlice{
+		solanago.NewAccountMeta(source, true, false),
+		solanago.NewAccountMeta(mint, false, false),
+		solanago.NewAccountMeta(destination, true, false),
+		solanago.NewAccountMeta(owner, false, true),
+	}
+	data := make([]byte, 10)
+	data[0] = token.Instruction_TransferChecked
+	binary.LittleEndian.PutUint64(data[1:9], amount)
+	data[9] = decimals
+	return solanago.NewInstruction(tokenProgram, accounts, data)
+}
+
+// CloseAccountInstruction builds a close account instruction that supports custom token programs (SPL/Token2022).
+func CloseAccountInstruction(account, destination, owner solanago.PublicKey, tokenProgram solanago.PublicKey) solanago.Instruction {
+	accounts := solanago.AccountMetaSlice{
+		solanago.NewAccountMeta(account, true, false),
+		solanago.NewAccountMeta(destination, true, false),
+		solanago.NewAccountMeta(owner, false, true),
+	}
+	return solanago.NewInstruction(tokenProgram, accounts, []byte{token.Instruction_CloseAccount})
+}
+
 func UnwrapSOLInstruction(owner, receiver solanago.PublicKey, allowOwnerOffCurve bool) (solanago.Instruction, error) {
 	ata, err := FindAssociatedTokenAddress(owner, NativeMint, token.ProgramID)
 	if err != nil {
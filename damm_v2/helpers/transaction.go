@@ -0,0 +1,15 @@
+package helpers
+
+import (
+	solanago "github.com/gagliardetto/solana-go"
+
+	"github.com/krazyTry/meteora-go/internal/txsize"
+)
+
+// MaxTransactionSize is the maximum serialized size of a legacy transaction in bytes.
+const MaxTransactionSize = txsize.MaxTransactionSize
+
+// GetTransactionSize returns the serialized size of a signed legacy transaction built from instructions.
+func GetTransactionSize(instructions []solanago.Instruction, payer solanago.PublicKey) (int, error) {
+	return txsize.OfInstructions(instructions, payer)
+}
@@ -0,0 +1,247 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/math/pool_fees"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+)
+
+// PreviewLaunchPool converts human launch parameters into the raw pool creation values and the resulting deposit.
+func (c *CpAmm) PreviewLaunchPool(ctx context.Context, params LaunchPoolParams) (LaunchPoolPreview, error) {
+	preview, _, _, err := c.prepareLaunchPool(ctx, params)
+	return preview, err
+}
+
+// LaunchPool builds a transaction that creates a customizable pool from human launch parameters.
+func (c *CpAmm) LaunchPool(ctx context.Context, params LaunchPoolParams) (TxBuilder, LaunchPoolPreview, error) {
+	preview, tokenAInfo, tokenBInfo, err := c.prepareLaunchPool(ctx, params)
+	if err != nil {
+		return nil, LaunchPoolPreview{}, err
+	}
+
+	builder, _, _, _, err := c.CreateCustomPool(ctx, InitializeCustomizeablePoolParams{
+		Payer:           params.Payer,
+		Creator:         params.Creator,
+		PositionNft:     params.PositionNft,
+		TokenAMint:      params.TokenAMint,
+		TokenBMint:      params.TokenBMint,
+		TokenAAmount:    preview.TokenAAmount,
+		TokenBAmount:    preview.TokenBAmount,
+		SqrtMinPrice:    preview.SqrtMinPrice,
+		SqrtMaxPrice:    preview.SqrtMaxPrice,
+		LiquidityDelta:  preview.LiquidityDelta,
+		InitSqrtPrice:   preview.InitSqrtPrice,
+		PoolFees:        preview.PoolFees,
+		HasAlphaVault:   params.HasAlphaVault,
+		ActivationType:  params.ActivationType,
+		CollectFeeMode:  params.CollectFeeMode,
+		ActivationPoint: preview.ActivationPoint,
+		TokenAProgram:   tokenAInfo.Owner,
+		TokenBProgram:   tokenBInfo.Owner,
+		IsLockLiquidity: params.IsLockLiquidity,
+	})
+	if err != nil {
+		return nil, LaunchPoolPreview{}, err
+	}
+	return builder, preview, nil
+}
+
+// prepareLaunchPool fetches the token infos and previews a launch.
+func (c *CpAmm) prepareLaunchPool(ctx context.Context, params LaunchPoolParams) (LaunchPoolPreview, *TokenInfo, *TokenInfo, error) {
+	tokenAInfo, err := helpers.GetTokenInfo(ctx, c.Client, params.TokenAMint)
+	if err != nil {
+		return LaunchPoolPreview{}, nil, nil, err
+	}
+	tokenBInfo, err := helpers.GetTokenInfo(ctx, c.Client, params.TokenBMint)
+	if err != nil {
+		return LaunchPoolPreview{}, nil, nil, err
+	}
+	preview, err := c.previewLaunchPool(params, tokenAInfo, tokenBInfo)
+	if err != nil {
+		return LaunchPoolPreview{}, nil, nil, err
+	}
+	preview.ActivationPoint, err = c.launchActivationPoint(ctx, params.ActivationType, params.ActivationTime)
+	if err != nil {
+		return LaunchPoolPreview{}, nil, nil, err
+	}
+	return preview, tokenAInfo, tokenBInfo, nil
+}
+
+// previewLaunchPool derives the Q64 prices, liquidity, deposit split and fees of a launch.
+func (c *CpAmm) previewLaunchPool(params LaunchPoolParams, tokenAInfo, tokenBInfo *TokenInfo) (LaunchPoolPreview, error) {
+	if !params.InitialPrice.IsPositive() {
+		return LaunchPoolPreview{}, errors.New("initial price must be greater than 0")
+	}
+	if params.TokenAAmount.IsNegative() || params.TokenBAmount.IsNegative() {
+		return LaunchPoolPreview{}, errors.New("token amounts must not be negative")
+	}
+	tokenADecimal, tokenBDecimal := tokenAInfo.Decimals, tokenBInfo.Decimals
+
+	initSqrtPrice := helpers.GetSqrtPriceFromPrice(params.InitialPrice, tokenADecimal, tokenBDecimal)
+	var sqrtMinPrice, sqrtMaxPrice *big.Int
+	switch params.PriceRange {
+	case PriceRangeCustom:
+		if !params.MinPrice.IsPositive() || !params.MaxPrice.IsPositive() {
+			return LaunchPoolPreview{}, errors.New("min and max price are required for a custom price range")
+		}
+		sqrtMinPrice = helpers.GetSqrtPriceFromPrice(params.MinPrice, tokenADecimal, tokenBDecimal)
+		sqrtMaxPrice = helpers.GetSqrtPriceFromPrice(params.MaxPrice, tokenADecimal, tokenBDecimal)
+	case PriceRangeFull:
+		sqrtMinPrice, sqrtMaxPrice = new(big.Int).Set(shared.MinSqrtPrice), new(big.Int).Set(shared.MaxSqrtPrice)
+	case PriceRangeHalf:
+		sqrtMinPrice = helpers.GetSqrtPriceFromPrice(params.InitialPrice.Mul(decimal.RequireFromString("0.5")), tokenADecimal, tokenBDecimal)
+		sqrtMaxPrice = helpers.GetSqrtPriceFromPrice(params.InitialPrice.Mul(decimal.RequireFromString("1.5")), tokenADecimal, tokenBDecimal)
+	case PriceRangeStable:
+		sqrtMinPrice = helpers.GetSqrtPriceFromPrice(params.InitialPrice.Mul(decimal.RequireFromString("0.98")), tokenADecimal, tokenBDecimal)
+		sqrtMaxPrice = helpers.GetSqrtPriceFromPrice(params.InitialPrice.Mul(decimal.RequireFromString("1.02")), tokenADecimal, tokenBDecimal)
+	default:
+		return LaunchPoolPreview{}, errors.New("invalid price range preset")
+	}
+
+	minPrice := helpers.GetPriceFromSqrtPrice(shared.MinSqrtPrice, tokenADecimal, tokenBDecimal)
+	maxPrice := helpers.GetPriceFromSqrtPrice(shared.MaxSqrtPrice, tokenADecimal, tokenBDecimal)
+	if sqrtMinPrice.Cmp(shared.MinSqrtPrice) < 0 {
+		return LaunchPoolPreview{}, fmt.Errorf("min price is below the lowest supported price %s", minPrice.String())
+	}
+	if sqrtMaxPrice.Cmp(shared.MaxSqrtPrice) > 0 {
+		return LaunchPoolPreview{}, fmt.Errorf("max price is above the highest supported price %s", maxPrice.String())
+	}
+	if sqrtMinPrice.Cmp(sqrtMaxPrice) >= 0 {
+		return LaunchPoolPreview{}, errors.New("min price must be below max price")
+	}
+	if initSqrtPrice.Cmp(sqrtMinPrice) < 0 || initSqrtPrice.Cmp(sqrtMaxPrice) > 0 {
+		return LaunchPoolPreview{}, errors.New("initial price is outside the price range")
+	}
+
+	// amounts are what the payer sends, so the transfer fee is removed before sizing the liquidity.
+	maxAmountA := helpers.ConvertToLamports(params.TokenAAmount, tokenADecimal)
+	maxAmountB := helpers.ConvertToLamports(params.TokenBAmount, tokenBDecimal)
+	actualAmountA := helpers.CalculateTransferFeeExcludedAmount(maxAmountA, tokenAInfo).Amount
+	actualAmountB := helpers.CalculateTransferFeeExcludedAmount(maxAmountB, tokenBInfo).Amount
+
+	var liquidityDelta *big.Int
+	switch {
+	case initSqrtPrice.Cmp(sqrtMinPrice) == 0:
+		liquidityDelta = math.GetLiquidityDeltaFromAmountA(actualAmountA, initSqrtPrice, sqrtMaxPrice)
+	case initSqrtPrice.Cmp(sqrtMaxPrice) == 0:
+		liquidityDelta = math.GetLiquidityDeltaFromAmountB(actualAmountB, sqrtMinPrice, initSqrtPrice)
+	default:
+		liquidityDelta = c.GetLiquidityDelta(LiquidityDeltaParams{
+			MaxAmountTokenA: actualAmountA,
+			MaxAmountTokenB: actualAmountB,
+			SqrtPrice:       initSqrtPrice,
+			SqrtMinPrice:    sqrtMinPrice,
+			SqrtMaxPrice:    sqrtMaxPrice,
+		})
+	}
+	if liquidityDelta.Sign() <= 0 {
+		return LaunchPoolPreview{}, errors.New("token amounts are too small for the price range")
+	}
+
+	depositA, err := math.GetAmountAFromLiquidityDelta(initSqrtPrice, sqrtMaxPrice, liquidityDelta, RoundingUp)
+	if err != nil {
+		return LaunchPoolPreview{}, err
+	}
+	depositB, err := math.GetAmountBFromLiquidityDelta(sqrtMinPrice, initSqrtPrice, liquidityDelta, RoundingUp)
+	if err != nil {
+		return LaunchPoolPreview{}, err
+	}
+	tokenAAmount := helpers.CalculateTransferFeeIncludedAmount(depositA, tokenAInfo).Amount
+	tokenBAmount := helpers.CalculateTransferFeeIncludedAmount(depositB, tokenBInfo).Amount
+
+	poolFees, err := launchPoolFees(params.Fee, params.CollectFeeMode, params.ActivationType)
+	if err != nil {
+		return LaunchPoolPreview{}, err
+	}
+
+	valueA := valueInQuote(depositA, big.NewInt(0), initSqrtPrice, false)
+	totalValue := new(big.Int).Add(valueA, depositB)
+	valueShareA := decimal.Zero
+	if totalValue.Sign() > 0 {
+		valueShareA = decimal.NewFromBigInt(valueA, 0).Div(decimal.NewFromBigInt(totalValue, 0))
+	}
+
+	return LaunchPoolPreview{
+		Pool:               DeriveCustomizablePoolAddress(params.TokenAMint, params.TokenBMint),
+		InitSqrtPrice:      initSqrtPrice,
+		SqrtMinPrice:       sqrtMinPrice,
+		SqrtMaxPrice:       sqrtMaxPrice,
+		MinPrice:           helpers.GetPriceFromSqrtPrice(sqrtMinPrice, tokenADecimal, tokenBDecimal),
+		MaxPrice:           helpers.GetPriceFromSqrtPrice(sqrtMaxPrice, tokenADecimal, tokenBDecimal),
+		LiquidityDelta:     liquidityDelta,
+		TokenAAmount:       tokenAAmount,
+		TokenBAmount:       tokenBAmount,
+		TokenAUiAmount:     decimal.NewFromBigInt(tokenAAmount, -int32(tokenADecimal)),
+		TokenBUiAmount:     decimal.NewFromBigInt(tokenBAmount, -int32(tokenBDecimal)),
+		UnusedTokenAAmount: new(big.Int).Sub(maxAmountA, minBig(maxAmountA, tokenAAmount)),
+		UnusedTokenBAmount: new(big.Int).Sub(maxAmountB, minBig(maxAmountB, tokenBAmount)),
+		TokenAValueShare:   valueShareA,
+		PoolFees:           poolFees,
+	}, nil
+}
+
+// launchPoolFees encodes and validates the fee preset of a launch.
+func launchPoolFees(fee LaunchPoolFee, collectFeeMode CollectFeeMode, activationType ActivationType) (PoolFeesParams, error) {
+	endingFeeBps, maxFeeBps := fee.EndingFeeBps, fee.StartingFeeBps
+	if fee.BaseFeeMode == BaseFeeModeRateLimiter {
+		endingFeeBps, maxFeeBps = fee.StartingFeeBps, fee.MaxFeeBps
+	}
+	if err := helpers.ValidatePoolFeeBps(endingFeeBps, maxFeeBps, fee.PoolVersion); err != nil {
+		return PoolFeesParams{}, err
+	}
+	baseFee, err := helpers.GetBaseFeeParams(
+		fee.BaseFeeMode,
+		fee.StartingFeeBps,
+		endingFeeBps,
+		fee.NumberOfPeriod,
+		fee.TotalDuration,
+		fee.SqrtPriceStepBps,
+		fee.SchedulerExpirationDuration,
+		fee.MaxLimiterDuration,
+		fee.MaxFeeBps,
+		fee.ReferenceAmount,
+	)
+	if err != nil {
+		return PoolFeesParams{}, err
+	}
+	handler, err := pool_fees.GetBaseFeeHandlerFromParams(baseFee.Data[:])
+	if err != nil {
+		return PoolFeesParams{}, err
+	}
+	if !handler.Validate(collectFeeMode, activationType, fee.PoolVersion) {
+		return PoolFeesParams{}, errors.New("invalid fee preset")
+	}
+
+	poolFees := PoolFeesParams{BaseFee: baseFee, Padding: []uint8{}}
+	if fee.DynamicFee {
+		poolFees.DynamicFee, err = helpers.GetDynamicFeeParams(endingFeeBps, shared.MaxPriceChangeBpsDefault)
+		if err != nil {
+			return PoolFeesParams{}, err
+		}
+	}
+	return poolFees, nil
+}
+
+// launchActivationPoint converts an activation time into a slot or timestamp; a zero time activates immediately.
+func (c *CpAmm) launchActivationPoint(ctx context.Context, activationType ActivationType, activationTime time.Time) (*big.Int, error) {
+	if activationTime.IsZero() {
+		return nil, nil
+	}
+	if activationType == ActivationTypeTimestamp {
+		return big.NewInt(activationTime.Unix()), nil
+	}
+	currentSlot, err := helpers.GetCurrentPoint(ctx, c.Client, ActivationTypeSlot)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(currentSlot, durationToPoints(time.Until(activationTime), ActivationTypeSlot)), nil
+}
@@ -0,0 +1,501 @@
+package dammv2
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	sendandconfirmtransaction "github.com/gagliardetto/solana-go/rpc/sendAndConfirmTransaction"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+)
+
+// LimitOrderStore persists limit orders.
+type LimitOrderStore interface {
+	Save(order LimitOrder) error
+	Load(id string) (LimitOrder, bool, error)
+	List() ([]LimitOrder, error)
+}
+
+// MemoryLimitOrderStore keeps limit orders in memory.
+type MemoryLimitOrderStore struct {
+	mu     sync.RWMutex
+	orders map[string]LimitOrder
+}
+
+func NewMemoryLimitOrderStore() *MemoryLimitOrderStore {
+	return &MemoryLimitOrderStore{orders: make(map[string]LimitOrder)}
+}
+
+func (s *MemoryLimitOrderStore) Save(order LimitOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[order.ID] = order
+	return nil
+}
+
+func (s *MemoryLimitOrderStore) Load(id string) (LimitOrder, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	order, ok := s.orders[id]
+	return order, ok, nil
+}
+
+func (s *MemoryLimitOrderStore) List() ([]LimitOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]LimitOrder, 0, len(s.orders))
+	for _, order := range s.orders {
+		out = append(out, order)
+	}
+	return out, nil
+}
+
+// LimitOrderKeeper watches pool prices and executes limit orders with Swap2 when the price crosses their limit.
+type LimitOrderKeeper struct {
+	cpAmm    *CpAmm
+	wsClient *ws.Client
+	config   LimitOrderKeeperConfig
+
+	mu         sync.Mutex
+	execMu     sync.Mutex
+	runCtx     context.Context
+	errCh      chan error
+	subscribed map[solanago.PublicKey]bool
+	tokenInfos map[solanago.PublicKey]*TokenInfo
+}
+
+func NewLimitOrderKeeper(cpAmm *CpAmm, wsClient *ws.Client, config LimitOrderKeeperConfig) *LimitOrderKeeper {
+	if config.Store == nil {
+		config.Store = NewMemoryLimitOrderStore()
+	}
+	return &LimitOrderKeeper{
+		cpAmm:      cpAmm,
+		wsClient:   wsClient,
+		config:     config,
+		subscribed: make(map[solanago.PublicKey]bool),
+		tokenInfos: make(map[solanago.PublicKey]*TokenInfo),
+	}
+}
+
+// Submit validates and stores a new order; the pool is watched immediately when the keeper is running.
+func (k *LimitOrderKeeper) Submit(order LimitOrder) (LimitOrder, error) {
+	if order.Amount == nil || order.Amount.Sign() <= 0 {
+		return LimitOrder{}, errors.New("amount must be greater than 0")
+	}
+	if !order.LimitPrice.IsPositive() {
+		return LimitOrder{}, errors.New("limit price must be greater than 0")
+	}
+	if order.Side != LimitOrderSideBuy && order.Side != LimitOrderSideSell {
+		return LimitOrder{}, errors.New("invalid limit order side")
+	}
+	if order.ID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return LimitOrder{}, err
+		}
+		order.ID = hex.EncodeToString(id)
+	} else if _, ok, err := k.config.Store.Load(order.ID); err != nil {
+		return LimitOrder{}, err
+	} else if ok {
+		return LimitOrder{}, fmt.Errorf("limit order %s already exists", order.ID)
+	}
+	order.Status = LimitOrderStatusOpen
+	order.FilledAmount = big.NewInt(0)
+	order.ReceivedAmount = big.NewInt(0)
+	order.Signatures = nil
+	order.LastError = ""
+	if err := k.save(order); err != nil {
+		return LimitOrder{}, err
+	}
+
+	k.mu.Lock()
+	running := k.runCtx != nil
+	k.mu.Unlock()
+	if running {
+		if err := k.watch(order.Pool); err != nil {
+			return LimitOrder{}, err
+		}
+	}
+	return order, nil
+}
+
+// Cancel cancels an open or partially filled order.
+func (k *LimitOrderKeeper) Cancel(id string) error {
+	k.execMu.Lock()
+	defer k.execMu.Unlock()
+	order, ok, err := k.config.Store.Load(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("limit order %s not found", id)
+	}
+	if !isLimitOrderActive(order) {
+		return fmt.Errorf("limit order %s is not active", id)
+	}
+	order.Status = LimitOrderStatusCancelled
+	return k.save(order)
+}
+
+// Run watches the pools of every active order until ctx is done or a subscription fails.
+func (k *LimitOrderKeeper) Run(ctx context.Context) error {
+	k.mu.Lock()
+	if k.runCtx != nil {
+		k.mu.Unlock()
+		return errors.New("keeper is already running")
+	}
+	k.runCtx = ctx
+	k.errCh = make(chan error, 1)
+	k.mu.Unlock()
+	defer func() {
+		k.mu.Lock()
+		k.runCtx = nil
+		k.subscribed = make(map[solanago.PublicKey]bool)
+		k.mu.Unlock()
+	}()
+
+	orders, err := k.config.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if !isLimitOrderActive(order) {
+			continue
+		}
+		if err := k.watch(order.Pool); err != nil {
+			return err
+		}
+	}
+	interval := k.config.ExpiryInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-k.errCh:
+			return err
+		case now := <-ticker.C:
+			if err := k.Expire(now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Expire marks every active order whose expiry is before now as expired, whether or not its pool changed.
+func (k *LimitOrderKeeper) Expire(now time.Time) error {
+	k.execMu.Lock()
+	defer k.execMu.Unlock()
+	orders, err := k.config.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if !isLimitOrderActive(order) || !isLimitOrderExpired(order, now) {
+			continue
+		}
+		order.Status = LimitOrderStatusExpired
+		if err := k.save(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watch subscribes to pool once and evaluates its orders on every update.
+func (k *LimitOrderKeeper) watch(pool solanago.PublicKey) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.subscribed[pool] {
+		return nil
+	}
+	sub, err := k.wsClient.AccountSubscribeWithOpts(pool, k.cpAmm.Commitment, solanago.EncodingBase64)
+	if err != nil {
+		return err
+	}
+	k.subscribed[pool] = true
+
+	ctx, errCh := k.runCtx, k.errCh
+	go func() {
+		defer sub.Unsubscribe()
+		// the price may already be past the limit before the pool changes again.
+		if poolState, err := k.cpAmm.FetchPoolState(ctx, pool); err == nil {
+			if err := k.Evaluate(ctx, pool, poolState); err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
+			}
+		}
+		for {
+			got, err := sub.Recv(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+				return
+			}
+			if got.Value == nil {
+				continue
+			}
+			parsed, err := dammv2gen.ParseAnyAccount(got.Value.Data.GetBinary())
+			if err != nil {
+				continue
+			}
+			poolState, ok := parsed.(*dammv2gen.Pool)
+			if !ok {
+				continue
+			}
+			if err := k.Evaluate(ctx, pool, poolState); err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Evaluate executes the active orders of pool whose limit price is crossed by poolState.
+// Swap failures are recorded on the order, which stays active; only store errors are returned.
+func (k *LimitOrderKeeper) Evaluate(ctx context.Context, pool solanago.PublicKey, poolState *PoolState) error {
+	k.execMu.Lock()
+	defer k.execMu.Unlock()
+
+	orders, err := k.config.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if !order.Pool.Equals(pool) || !isLimitOrderActive(order) {
+			continue
+		}
+		if isLimitOrderExpired(order, time.Now()) {
+			order.Status = LimitOrderStatusExpired
+			if err := k.save(order); err != nil {
+				return err
+			}
+			continue
+		}
+		order, err = k.execute(ctx, order, poolState)
+		if err != nil {
+			order.LastError = err.Error()
+		}
+		if err := k.save(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute swaps as much of the remaining order as the pool allows at the limit price.
+func (k *LimitOrderKeeper) execute(ctx context.Context, order LimitOrder, poolState *PoolState) (LimitOrder, error) {
+	tokenAInfo, err := k.tokenInfo(ctx, poolState.TokenAMint)
+	if err != nil {
+		return order, err
+	}
+	tokenBInfo, err := k.tokenInfo(ctx, poolState.TokenBMint)
+	if err != nil {
+		return order, err
+	}
+	price := helpers.GetPriceFromSqrtPrice(poolState.SqrtPrice.BigInt(), tokenAInfo.Decimals, tokenBInfo.Decimals)
+	sell := order.Side == LimitOrderSideSell
+	if (sell && price.LessThanOrEqual(order.LimitPrice)) || (!sell && price.GreaterThanOrEqual(order.LimitPrice)) {
+		return order, nil
+	}
+
+	inputMint, outputMint := poolState.TokenBMint, poolState.TokenAMint
+	inputInfo, outputInfo := tokenBInfo, tokenAInfo
+	if sell {
+		inputMint, outputMint = poolState.TokenAMint, poolState.TokenBMint
+		inputInfo, outputInfo = tokenAInfo, tokenBInfo
+	}
+	currentPoint, err := helpers.GetCurrentPoint(ctx, k.cpAmm.Client, ActivationType(poolState.ActivationType))
+	if err != nil {
+		return order, err
+	}
+
+	amountIn := new(big.Int).Sub(order.Amount, order.FilledAmount)
+	swapMode := SwapModeExactIn
+	if order.AllowPartialFill {
+		// beyond the limit price the marginal price is worse than the limit, so stop there.
+		toLimit, err := k.cpAmm.GetSwapToPriceQuote(GetSwapToPriceQuoteParams{
+			PoolState:       poolState,
+			CurrentPoint:    currentPoint,
+			TargetPrice:     order.LimitPrice,
+			TokenATokenInfo: tokenAInfo,
+			TokenBTokenInfo: tokenBInfo,
+			TokenADecimal:   tokenAInfo.Decimals,
+			TokenBDecimal:   tokenBInfo.Decimals,
+		})
+		if err != nil {
+			return order, err
+		}
+		if toLimit.AmountIn.Cmp(amountIn) < 0 {
+			amountIn = toLimit.AmountIn
+		}
+		swapMode = SwapModePartialFill
+	}
+	if amountIn.Sign() <= 0 {
+		return order, nil
+	}
+
+	quote, err := k.cpAmm.GetQuote2(GetQuote2Params{
+		InputTokenMint:  inputMint,
+		CurrentPoint:    currentPoint,
+		PoolState:       poolState,
+		InputTokenInfo:  inputInfo,
+		OutputTokenInfo: outputInfo,
+		TokenADecimal:   tokenAInfo.Decimals,
+		TokenBDecimal:   tokenBInfo.Decimals,
+		SwapMode:        swapMode,
+		AmountIn:        amountIn,
+	})
+	if err != nil {
+		return order, err
+	}
+	consumed := new(big.Int).Sub(amountIn, new(big.Int).SetUint64(quote.AmountLeft))
+	minimumAmountOut := limitOrderMinimumAmountOut(order, consumed, tokenAInfo.Decimals, tokenBInfo.Decimals)
+	if minimumAmountOut.Sign() <= 0 || quote.MinimumAmountOut.Cmp(minimumAmountOut) < 0 {
+		return order, nil
+	}
+
+	builder, err := k.cpAmm.Swap2(ctx, Swap2Params{
+		Payer:            order.Owner,
+		Pool:             order.Pool,
+		InputTokenMint:   inputMint,
+		OutputTokenMint:  outputMint,
+		PoolState:        poolState,
+		SwapMode:         swapMode,
+		AmountIn:         amountIn,
+		MinimumAmountOut: minimumAmountOut,
+	})
+	if err != nil {
+		return order, err
+	}
+	sig, err := k.send(ctx, builder.SetFeePayer(order.Owner))
+	if err != nil {
+		return order, err
+	}
+	order.Signatures = append(order.Signatures, sig)
+	order.LastError = ""
+
+	// the swap landed; without its event the quoted amounts are the best estimate left.
+	filled, received, fillErr := k.swapFill(ctx, sig, order.Pool)
+	if fillErr != nil {
+		filled, received = consumed, quote.MinimumAmountOut
+	}
+	order.FilledAmount = new(big.Int).Add(order.FilledAmount, filled)
+	order.ReceivedAmount = new(big.Int).Add(order.ReceivedAmount, received)
+	order.Status = LimitOrderStatusPartiallyFilled
+	if order.FilledAmount.Cmp(order.Amount) >= 0 {
+		order.Status = LimitOrderStatusFilled
+	}
+	return order, fillErr
+}
+
+// swapFill reads the input paid and output received from the swap event of a confirmed transaction.
+func (k *LimitOrderKeeper) swapFill(ctx context.Context, sig solanago.Signature, pool solanago.PublicKey) (*big.Int, *big.Int, error) {
+	events, err := k.cpAmm.getTransactionEvents(ctx, sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, event := range events {
+		if evt, ok := event.(*dammv2gen.EvtSwap2); ok && evt.Pool.Equals(pool) {
+			return new(big.Int).SetUint64(evt.IncludedTransferFeeAmountIn), new(big.Int).SetUint64(evt.ExcludedTransferFeeAmountOut), nil
+		}
+	}
+	return nil, nil, fmt.Errorf("swap event not found in transaction %s", sig)
+}
+
+// send signs, submits and confirms a swap transaction.
+func (k *LimitOrderKeeper) send(ctx context.Context, builder TxBuilder) (solanago.Signature, error) {
+	if k.config.Signer == nil {
+		return solanago.Signature{}, errors.New("keeper signer is required")
+	}
+	latestBlockhash, err := k.cpAmm.Client.GetLatestBlockhash(ctx, k.cpAmm.Commitment)
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	tx, err := builder.SetRecentBlockHash(latestBlockhash.Value.Blockhash).Build()
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	if _, err := tx.Sign(k.config.Signer); err != nil {
+		return solanago.Signature{}, err
+	}
+	sig, err := k.cpAmm.Client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{PreflightCommitment: k.cpAmm.Commitment})
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	if _, err := sendandconfirmtransaction.WaitForConfirmation(ctx, k.wsClient, sig, nil); err != nil {
+		return solanago.Signature{}, err
+	}
+	return sig, nil
+}
+
+func (k *LimitOrderKeeper) save(order LimitOrder) error {
+	if err := k.config.Store.Save(order); err != nil {
+		return err
+	}
+	if k.config.OnStatus != nil {
+		k.config.OnStatus(order)
+	}
+	return nil
+}
+
+func (k *LimitOrderKeeper) tokenInfo(ctx context.Context, mint solanago.PublicKey) (*TokenInfo, error) {
+	k.mu.Lock()
+	info, ok := k.tokenInfos[mint]
+	k.mu.Unlock()
+	if ok {
+		return info, nil
+	}
+	info, err := helpers.GetTokenInfo(ctx, k.cpAmm.Client, mint)
+	if err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	k.tokenInfos[mint] = info
+	k.mu.Unlock()
+	return info, nil
+}
+
+// limitOrderMinimumAmountOut returns the output of amountIn at the order limit price, rounded down.
+func limitOrderMinimumAmountOut(order LimitOrder, amountIn *big.Int, tokenADecimal, tokenBDecimal uint8) *big.Int {
+	rawPrice := order.LimitPrice.Shift(int32(tokenBDecimal) - int32(tokenADecimal))
+	in := decimal.NewFromBigInt(amountIn, 0)
+	if order.Side == LimitOrderSideSell {
+		return in.Mul(rawPrice).Floor().BigInt()
+	}
+	return in.Div(rawPrice).Floor().BigInt()
+}
+
+func isLimitOrderActive(order LimitOrder) bool {
+	return order.Status == LimitOrderStatusOpen || order.Status == LimitOrderStatusPartiallyFilled
+}
+
+func isLimitOrderExpired(order LimitOrder, now time.Time) bool {
+	return !order.Expiry.IsZero() && now.After(order.Expiry)
+}
@@ -25,9 +25,9 @@ func GetNextSqrtPriceFromAmountOutBRoundingDown(sqrtPrice, liquidity, amount *bi
 	return result, nil
 }
 
-func GetNextSqrtPriceFromAmountInARoundingUp(sqrtPrice, liquidity, amount *big.Int) *big.Int {
+func GetNextSqrtPriceFromAmountInARoundingUp(sqrtPrice, liquidity, amount *big.Int) (*big.Int, error) {
 	if amount.Sign() == 0 {
-		return new(big.Int).Set(sqrtPrice)
+		return new(big.Int).Set(sqrtPrice), nil
 	}
 	product := new(big.Int).Mul(amount, sqrtPrice)
 	denominator := new(big.Int).Add(liquidity, product)
@@ -41,9 +41,9 @@ func GetNextSqrtPriceFromAmountOutARoundingUp(sqrtPrice, liquidity, amount *big.
 	product := new(big.Int).Mul(amount, sqrtPrice)
 	denominator := new(big.Int).Sub(liquidity, product)
 	if denominator.Sign() <= 0 {
-		return nil, errors.New("math overflow: denominator is zero or negative")
+		return nil, shared.NewMathOverflowError("next sqrt price from amount out A: denominator is zero or negative")
 	}
-	return MulDiv(liquidity, sqrtPrice, denominator, shared.RoundingUp), nil
+	return MulDiv(liquidity, sqrtPrice, denominator, shared.RoundingUp)
 }
 
 func GetNextSqrtPriceFromOutput(sqrtPrice, liquidity, amountOut *big.Int, aForB bool) (*big.Int, error) {
@@ -66,40 +66,62 @@ func GetNextSqrtPriceFromInput(sqrtPrice, liquidity, amountIn *big.Int, aForB bo
 	if liquidity.Sign() <= 0 {
 		return nil, errors.New("liquidity must be greater than 0")
 	}
+	var next *big.Int
 	if aForB {
-		return GetNextSqrtPriceFromAmountInARoundingUp(sqrtPrice, liquidity, amountIn), nil
+		var err error
+		if next, err = GetNextSqrtPriceFromAmountInARoundingUp(sqrtPrice, liquidity, amountIn); err != nil {
+			return nil, err
+		}
+	} else {
+		next = GetNextSqrtPriceFromAmountInBRoundingDown(sqrtPrice, liquidity, amountIn)
 	}
-	return GetNextSqrtPriceFromAmountInBRoundingDown(sqrtPrice, liquidity, amountIn), nil
+	if err := shared.CheckedU128(next, "next sqrt price from input"); err != nil {
+		return nil, err
+	}
+	return next, nil
 }
 
-func GetAmountBFromLiquidityDelta(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) *big.Int {
+func GetAmountBFromLiquidityDelta(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	return getDeltaAmountBUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity, rounding)
 }
 
-func getDeltaAmountBUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) *big.Int {
+func getDeltaAmountBUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	deltaSqrtPrice := new(big.Int).Sub(upperSqrtPrice, lowerSqrtPrice)
 	prod := new(big.Int).Mul(liquidity, deltaSqrtPrice)
 	shift := uint(shared.ScaleOffset * 2)
+	var result *big.Int
 	if rounding == shared.RoundingUp {
 		denominator := new(big.Int).Lsh(big.NewInt(1), shift)
-		result := new(big.Int).Add(prod, new(big.Int).Sub(denominator, big.NewInt(1)))
-		return result.Div(result, denominator)
+		result = new(big.Int).Add(prod, new(big.Int).Sub(denominator, big.NewInt(1)))
+		result.Div(result, denominator)
+	} else {
+		result = prod.Rsh(prod, shift)
+	}
+	if _, err := shared.CheckedU64(result, "delta amount B"); err != nil {
+		return nil, err
 	}
-	return prod.Rsh(prod, shift)
+	return result, nil
 }
 
-func GetAmountAFromLiquidityDelta(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) *big.Int {
+func GetAmountAFromLiquidityDelta(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	return getDeltaAmountAUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity, rounding)
 }
 
-func getDeltaAmountAUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) *big.Int {
+func getDeltaAmountAUnsignedUnchecked(lowerSqrtPrice, upperSqrtPrice, liquidity *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	numerator1 := liquidity
 	numerator2 := new(big.Int).Sub(upperSqrtPrice, lowerSqrtPrice)
 	denominator := new(big.Int).Mul(lowerSqrtPrice, upperSqrtPrice)
 	if denominator.Sign() <= 0 {
 		panic("denominator must be greater than zero")
 	}
-	return MulDiv(numerator1, numerator2, denominator, rounding)
+	result, err := MulDiv(numerator1, numerator2, denominator, rounding)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := shared.CheckedU64(result, "delta amount A"); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func GetLiquidityDeltaFromAmountA(amountA, lowerSqrtPrice, upperSqrtPrice *big.Int) *big.Int {
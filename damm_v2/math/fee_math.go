@@ -9,7 +9,7 @@ import (
 	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
 )
 
-func ToNumerator(bps, feeDenominator *big.Int) *big.Int {
+func ToNumerator(bps, feeDenominator *big.Int) (*big.Int, error) {
 	return MulDiv(bps, feeDenominator, big.NewInt(shared.BasisPointMax), shared.RoundingDown)
 }
 
@@ -112,15 +112,21 @@ func SplitFees(poolFees dammv2gen.PoolFeesStruct, feeAmount *big.Int, hasReferra
 }
 
 func GetFeeOnAmount(poolFees dammv2gen.PoolFeesStruct, amount, tradeFeeNumerator *big.Int, hasReferral, hasPartner bool) (shared.FeeOnAmountResult, error) {
-	excludedFeeAmount, tradingFee := GetExcludedFeeAmount(tradeFeeNumerator, amount)
+	excludedFeeAmount, tradingFee, err := GetExcludedFeeAmount(tradeFeeNumerator, amount)
+	if err != nil {
+		return shared.FeeOnAmountResult{}, err
+	}
 	split := SplitFees(poolFees, tradingFee, hasReferral, hasPartner)
 	return shared.FeeOnAmountResult{FeeNumerator: tradeFeeNumerator, FeeAmount: tradingFee, AmountAfterFee: excludedFeeAmount, TradingFee: split.TradingFee, ProtocolFee: split.ProtocolFee, ReferralFee: split.ReferralFee, PartnerFee: split.PartnerFee}, nil
 }
 
-func GetExcludedFeeAmount(tradeFeeNumerator, includedFeeAmount *big.Int) (*big.Int, *big.Int) {
-	tradingFee := MulDiv(includedFeeAmount, tradeFeeNumerator, big.NewInt(shared.FeeDenominator), shared.RoundingUp)
+func GetExcludedFeeAmount(tradeFeeNumerator, includedFeeAmount *big.Int) (*big.Int, *big.Int, error) {
+	tradingFee, err := MulDiv(includedFeeAmount, tradeFeeNumerator, big.NewInt(shared.FeeDenominator), shared.RoundingUp)
+	if err != nil {
+		return nil, nil, err
+	}
 	excluded := new(big.Int).Sub(includedFeeAmount, tradingFee)
-	return excluded, tradingFee
+	return excluded, tradingFee, nil
 }
 
 func GetIncludedFeeAmount(tradeFeeNumerator, excludedFeeAmount *big.Int) (*big.Int, *big.Int, error) {
@@ -128,7 +134,10 @@ func GetIncludedFeeAmount(tradeFeeNumerator, excludedFeeAmount *big.Int) (*big.I
 	if denominator.Sign() <= 0 {
 		return nil, nil, errors.New("invalid fee numerator")
 	}
-	included := MulDiv(excludedFeeAmount, big.NewInt(shared.FeeDenominator), denominator, shared.RoundingUp)
+	included, err := MulDiv(excludedFeeAmount, big.NewInt(shared.FeeDenominator), denominator, shared.RoundingUp)
+	if err != nil {
+		return nil, nil, err
+	}
 	feeAmount := new(big.Int).Sub(included, excludedFeeAmount)
 	return included, feeAmount, nil
 }
@@ -165,3 +165,52 @@ func GetBaseFeeHandler(rawData []byte) (shared.BaseFeeHandler, error) {
 		return nil, errors.New("invalid base fee mode")
 	}
 }
+
+// GetBaseFeeHandlerFromParams selects handler based on base fee parameters as passed at pool creation.
+func GetBaseFeeHandlerFromParams(rawData []byte) (shared.BaseFeeHandler, error) {
+	if len(rawData) < 27 {
+		return nil, errors.New("invalid base fee data")
+	}
+	baseFeeMode := shared.BaseFeeMode(rawData[26])
+	switch baseFeeMode {
+	case shared.BaseFeeModeFeeTimeSchedulerLinear, shared.BaseFeeModeFeeTimeSchedulerExponential:
+		params, err := helpers.DecodeFeeTimeSchedulerParams(rawData)
+		if err != nil {
+			return nil, err
+		}
+		return FeeTimeScheduler{
+			CliffFeeNumerator:    new(big.Int).SetUint64(params.CliffFeeNumerator),
+			NumberOfPeriod:       params.NumberOfPeriod,
+			PeriodFrequency:      new(big.Int).SetUint64(params.PeriodFrequency),
+			ReductionFactor:      new(big.Int).SetUint64(params.ReductionFactor),
+			FeeTimeSchedulerMode: shared.BaseFeeMode(params.BaseFeeMode),
+		}, nil
+	case shared.BaseFeeModeRateLimiter:
+		params, err := helpers.DecodeFeeRateLimiterParams(rawData)
+		if err != nil {
+			return nil, err
+		}
+		return FeeRateLimiter{
+			CliffFeeNumerator:  new(big.Int).SetUint64(params.CliffFeeNumerator),
+			FeeIncrementBps:    params.FeeIncrementBps,
+			MaxFeeBps:          uint16(params.MaxFeeBps),
+			MaxLimiterDuration: params.MaxLimiterDuration,
+			ReferenceAmount:    new(big.Int).SetUint64(params.ReferenceAmount),
+		}, nil
+	case shared.BaseFeeModeFeeMarketCapSchedulerLinear, shared.BaseFeeModeFeeMarketCapSchedulerExp:
+		params, err := helpers.DecodeFeeMarketCapSchedulerParams(rawData)
+		if err != nil {
+			return nil, err
+		}
+		return FeeMarketCapScheduler{
+			CliffFeeNumerator:           new(big.Int).SetUint64(params.CliffFeeNumerator),
+			NumberOfPeriod:              params.NumberOfPeriod,
+			SqrtPriceStepBps:            uint16(params.SqrtPriceStepBps),
+			SchedulerExpirationDuration: params.SchedulerExpirationDuration,
+			ReductionFactor:             new(big.Int).SetUint64(params.ReductionFactor),
+			FeeMarketCapSchedulerMode:   shared.BaseFeeMode(params.BaseFeeMode),
+		}, nil
+	default:
+		return nil, errors.New("invalid base fee mode")
+	}
+}
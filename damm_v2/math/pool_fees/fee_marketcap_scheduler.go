@@ -13,12 +13,18 @@ func GetFeeMarketCapBaseFeeNumeratorByPeriod(cliffFeeNumerator *big.Int, numberO
 	if periodValue.Cmp(maxPeriod) > 0 {
 		periodValue = maxPeriod
 	}
-	periodNumber := uint16(periodValue.Uint64())
+	periodNumber, err := shared.CheckedU64(periodValue, "fee market cap scheduler period")
+	if err != nil {
+		return nil, err
+	}
+	if periodNumber > shared.U16Max {
+		return nil, shared.NewMathOverflowError("fee market cap scheduler period")
+	}
 	switch feeMarketCapSchedulerMode {
 	case shared.BaseFeeModeFeeMarketCapSchedulerLinear:
-		return GetFeeNumeratorOnLinearFeeScheduler(cliffFeeNumerator, reductionFactor, periodNumber), nil
+		return GetFeeNumeratorOnLinearFeeScheduler(cliffFeeNumerator, reductionFactor, uint16(periodNumber)), nil
 	case shared.BaseFeeModeFeeMarketCapSchedulerExp:
-		return GetFeeNumeratorOnExponentialFeeScheduler(cliffFeeNumerator, reductionFactor, periodNumber), nil
+		return GetFeeNumeratorOnExponentialFeeScheduler(cliffFeeNumerator, reductionFactor, uint16(periodNumber)), nil
 	default:
 		return nil, errors.New("invalid fee market cap scheduler mode")
 	}
@@ -15,7 +15,7 @@ func GetFeeTimeBaseFeeNumeratorByPeriod(cliffFeeNumerator *big.Int, numberOfPeri
 	}
 	periodNumber := periodValue.Uint64()
 	if periodNumber > shared.U16Max {
-		return nil, errors.New("math overflow")
+		return nil, shared.NewMathOverflowError("fee time scheduler period")
 	}
 	switch feeTimeSchedulerMode {
 	case shared.BaseFeeModeFeeTimeSchedulerLinear:
@@ -83,7 +83,10 @@ func GetFeeNumeratorFromIncludedFeeAmount(inputAmount, referenceAmount, cliffFee
 		tradingFeeNumerator = new(big.Int).Add(firstFee, secondFee)
 	}
 
-	feeNumerator := mulDiv(tradingFeeNumerator, big.NewInt(shared.FeeDenominator), inputAmount, shared.RoundingUp)
+	feeNumerator, err := mulDiv(tradingFeeNumerator, big.NewInt(shared.FeeDenominator), inputAmount, shared.RoundingUp)
+	if err != nil {
+		return nil, err
+	}
 	if feeNumerator.Cmp(maxFeeNumerator) > 0 {
 		return maxFeeNumerator, nil
 	}
@@ -7,20 +7,25 @@ import (
 	"github.com/krazyTry/meteora-go/damm_v2/shared"
 )
 
-func mulDiv(x, y, denominator *big.Int, rounding shared.Rounding) *big.Int {
+func mulDiv(x, y, denominator *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	if denominator.Sign() == 0 {
-		return big.NewInt(0)
+		return big.NewInt(0), nil
 	}
 	mul := new(big.Int).Mul(x, y)
+	if err := shared.CheckedU256(mul, "mulDiv product"); err != nil {
+		return nil, err
+	}
 	div, mod := new(big.Int).QuoRem(mul, denominator, new(big.Int))
 	if rounding == shared.RoundingUp && mod.Sign() != 0 {
-		return div.Add(div, big.NewInt(1))
+		return div.Add(div, big.NewInt(1)), nil
 	}
-	return div
+	return div, nil
 }
 
+// toNumerator converts a u16 bps value, whose product with the fee denominator always fits.
 func toNumerator(bps *big.Int) *big.Int {
-	return mulDiv(bps, big.NewInt(shared.FeeDenominator), big.NewInt(shared.BasisPointMax), shared.RoundingDown)
+	numerator := new(big.Int).Mul(bps, big.NewInt(shared.FeeDenominator))
+	return numerator.Div(numerator, big.NewInt(shared.BasisPointMax))
 }
 
 func pow(base, exp *big.Int) *big.Int {
@@ -60,7 +65,10 @@ func getIncludedFeeAmount(tradeFeeNumerator, excludedFeeAmount *big.Int) (*big.I
 	if denominator.Sign() <= 0 {
 		return nil, nil, errors.New("invalid fee numerator")
 	}
-	included := mulDiv(excludedFeeAmount, big.NewInt(shared.FeeDenominator), denominator, shared.RoundingUp)
+	included, err := mulDiv(excludedFeeAmount, big.NewInt(shared.FeeDenominator), denominator, shared.RoundingUp)
+	if err != nil {
+		return nil, nil, err
+	}
 	feeAmount := new(big.Int).Sub(included, excludedFeeAmount)
 	return included, feeAmount, nil
 }
@@ -76,17 +76,17 @@ func GetSwapResultFromExactInput(poolState *dammv2gen.Pool, amountIn *big.Int, f
 		actualAmountOut = feeResult.AmountAfterFee
 	}
 
-	return shared.SwapResult2{
-		IncludedFeeInputAmount: amountIn.Uint64(),
-		ExcludedFeeInputAmount: actualAmountIn.Uint64(),
-		AmountLeft:             amountLeft.Uint64(),
-		OutputAmount:           actualAmountOut.Uint64(),
-		NextSqrtPrice:          u128FromBig(nextSqrtPrice),
-		TradingFee:             actualTradingFee.Uint64(),
-		ProtocolFee:            actualProtocolFee.Uint64(),
-		PartnerFee:             actualPartnerFee.Uint64(),
-		ReferralFee:            actualReferralFee.Uint64(),
-	}, nil
+	return swapAmounts{
+		includedFeeInput: amountIn,
+		excludedFeeInput: actualAmountIn,
+		amountLeft:       amountLeft,
+		output:           actualAmountOut,
+		nextSqrtPrice:    nextSqrtPrice,
+		tradingFee:       actualTradingFee,
+		protocolFee:      actualProtocolFee,
+		partnerFee:       actualPartnerFee,
+		referralFee:      actualReferralFee,
+	}.toSwapResult()
 }
 
 func calculateAtoBFromAmountIn(poolState *dammv2gen.Pool, amountIn *big.Int) (*big.Int, *big.Int, *big.Int, error) {
@@ -98,7 +98,10 @@ func calculateAtoBFromAmountIn(poolState *dammv2gen.Pool, amountIn *big.Int) (*b
 	if nextSqrtPrice.Cmp(sqrtMin) < 0 {
 		return nil, nil, nil, errors.New("price range is violated")
 	}
-	outputAmount := GetAmountBFromLiquidityDelta(nextSqrtPrice, sqrtPrice, liquidity, shared.RoundingDown)
+	outputAmount, err := GetAmountBFromLiquidityDelta(nextSqrtPrice, sqrtPrice, liquidity, shared.RoundingDown)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	return outputAmount, nextSqrtPrice, big.NewInt(0), nil
 }
 
@@ -111,7 +114,10 @@ func calculateBtoAFromAmountIn(poolState *dammv2gen.Pool, amountIn *big.Int) (*b
 	if nextSqrtPrice.Cmp(sqrtMax) > 0 {
 		return nil, nil, nil, errors.New("price range is violated")
 	}
-	outputAmount := GetAmountAFromLiquidityDelta(sqrtPrice, nextSqrtPrice, liquidity, shared.RoundingDown)
+	outputAmount, err := GetAmountAFromLiquidityDelta(sqrtPrice, nextSqrtPrice, liquidity, shared.RoundingDown)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	return outputAmount, nextSqrtPrice, big.NewInt(0), nil
 }
 
@@ -193,25 +199,29 @@ func GetSwapResultFromPartialInput(poolState *dammv2gen.Pool, amountIn *big.Int,
 		actualAmountOut = feeResult.AmountAfterFee
 	}
 
-	return shared.SwapResult2{
-		IncludedFeeInputAmount: includedFeeInputAmount.Uint64(),
-		ExcludedFeeInputAmount: actualAmountIn.Uint64(),
-		AmountLeft:             amountLeft.Uint64(),
-		OutputAmount:           actualAmountOut.Uint64(),
-		NextSqrtPrice:          u128FromBig(nextSqrtPrice),
-		TradingFee:             actualTradingFee.Uint64(),
-		ProtocolFee:            actualProtocolFee.Uint64(),
-		PartnerFee:             actualPartnerFee.Uint64(),
-		ReferralFee:            actualReferralFee.Uint64(),
-	}, nil
+	return swapAmounts{
+		includedFeeInput: includedFeeInputAmount,
+		excludedFeeInput: actualAmountIn,
+		amountLeft:       amountLeft,
+		output:           actualAmountOut,
+		nextSqrtPrice:    nextSqrtPrice,
+		tradingFee:       actualTradingFee,
+		protocolFee:      actualProtocolFee,
+		partnerFee:       actualPartnerFee,
+		referralFee:      actualReferralFee,
+	}.toSwapResult()
 }
 
 func calculateAtoBFromPartialAmountIn(poolState *dammv2gen.Pool, amountIn *big.Int) (*big.Int, *big.Int, *big.Int, error) {
 	sqrtPrice, sqrtMin, _, liquidity, _, _ := getPoolBig(poolState)
-	maxAmountIn := GetAmountAFromLiquidityDelta(sqrtMin, sqrtPrice, liquidity, shared.RoundingUp)
+	// A range holding more than a u64 of token A cannot be exhausted by a u64 input.
+	maxAmountIn, err := GetAmountAFromLiquidityDelta(sqrtMin, sqrtPrice, liquidity, shared.RoundingUp)
+	if err != nil && !errors.Is(err, shared.ErrMathOverflow) {
+		return nil, nil, nil, err
+	}
 	consumedIn := new(big.Int)
 	nextSqrt := new(big.Int)
-	if amountIn.Cmp(maxAmountIn) >= 0 {
+	if err == nil && amountIn.Cmp(maxAmountIn) >= 0 {
 		consumedIn.Set(maxAmountIn)
 		nextSqrt.Set(sqrtMin)
 	} else {
@@ -222,17 +232,24 @@ func calculateAtoBFromPartialAmountIn(poolState *dammv2gen.Pool, amountIn *big.I
 		nextSqrt.Set(next)
 		consumedIn.Set(amountIn)
 	}
-	outputAmount := GetAmountBFromLiquidityDelta(nextSqrt, sqrtPrice, liquidity, shared.RoundingDown)
+	outputAmount, err := GetAmountBFromLiquidityDelta(nextSqrt, sqrtPrice, liquidity, shared.RoundingDown)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	amountLeft := new(big.Int).Sub(amountIn, consumedIn)
 	return outputAmount, nextSqrt, amountLeft, nil
 }
 
 func calculateBtoAFromPartialAmountIn(poolState *dammv2gen.Pool, amountIn *big.Int) (*big.Int, *big.Int, *big.Int, error) {
 	sqrtPrice, _, sqrtMax, liquidity, _, _ := getPoolBig(poolState)
-	maxAmountIn := GetAmountBFromLiquidityDelta(sqrtPrice, sqrtMax, liquidity, shared.RoundingUp)
+	// A range holding more than a u64 of token B cannot be exhausted by a u64 input.
+	maxAmountIn, err := GetAmountBFromLiquidityDelta(sqrtPrice, sqrtMax, liquidity, shared.RoundingUp)
+	if err != nil && !errors.Is(err, shared.ErrMathOverflow) {
+		return nil, nil, nil, err
+	}
 	consumedIn := new(big.Int)
 	nextSqrt := new(big.Int)
-	if amountIn.Cmp(maxAmountIn) >= 0 {
+	if err == nil && amountIn.Cmp(maxAmountIn) >= 0 {
 		consumedIn.Set(maxAmountIn)
 		nextSqrt.Set(sqrtMax)
 	} else {
@@ -243,7 +260,10 @@ func calculateBtoAFromPartialAmountIn(poolState *dammv2gen.Pool, amountIn *big.I
 		nextSqrt.Set(next)
 		consumedIn.Set(amountIn)
 	}
-	outputAmount := GetAmountAFromLiquidityDelta(sqrtPrice, nextSqrt, liquidity, shared.RoundingDown)
+	outputAmount, err := GetAmountAFromLiquidityDelta(sqrtPrice, nextSqrt, liquidity, shared.RoundingDown)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	amountLeft := new(big.Int).Sub(amountIn, consumedIn)
 	return outputAmount, nextSqrt, amountLeft, nil
 }
@@ -308,17 +328,17 @@ func GetSwapResultFromExactOutput(poolState *dammv2gen.Pool, amountOut *big.Int,
 		includedFeeInputAmount = includedFeeAmount
 	}
 
-	return shared.SwapResult2{
-		AmountLeft:             0,
-		IncludedFeeInputAmount: includedFeeInputAmount.Uint64(),
-		ExcludedFeeInputAmount: inputAmount.Uint64(),
-		OutputAmount:           amountOut.Uint64(),
-		NextSqrtPrice:          u128FromBig(nextSqrtPrice),
-		TradingFee:             actualTradingFee.Uint64(),
-		ProtocolFee:            actualProtocolFee.Uint64(),
-		PartnerFee:             actualPartnerFee.Uint64(),
-		ReferralFee:            actualReferralFee.Uint64(),
-	}, nil
+	return swapAmounts{
+		includedFeeInput: includedFeeInputAmount,
+		excludedFeeInput: inputAmount,
+		amountLeft:       nil,
+		output:           amountOut,
+		nextSqrtPrice:    nextSqrtPrice,
+		tradingFee:       actualTradingFee,
+		protocolFee:      actualProtocolFee,
+		partnerFee:       actualPartnerFee,
+		referralFee:      actualReferralFee,
+	}.toSwapResult()
 }
 
 func calculateAtoBFromAmountOut(poolState *dammv2gen.Pool, amountOut *big.Int) (*big.Int, *big.Int, error) {
@@ -330,7 +350,10 @@ func calculateAtoBFromAmountOut(poolState *dammv2gen.Pool, amountOut *big.Int) (
 	if nextSqrt.Cmp(sqrtMin) < 0 {
 		return nil, nil, errors.New("price range violation")
 	}
-	inputAmount := GetAmountAFromLiquidityDelta(nextSqrt, sqrtPrice, liquidity, shared.RoundingUp)
+	inputAmount, err := GetAmountAFromLiquidityDelta(nextSqrt, sqrtPrice, liquidity, shared.RoundingUp)
+	if err != nil {
+		return nil, nil, err
+	}
 	return inputAmount, nextSqrt, nil
 }
 
@@ -343,7 +366,10 @@ func calculateBtoAFromAmountOut(poolState *dammv2gen.Pool, amountOut *big.Int) (
 	if nextSqrt.Cmp(sqrtMax) > 0 {
 		return nil, nil, errors.New("price range violation")
 	}
-	inputAmount := GetAmountBFromLiquidityDelta(sqrtPrice, nextSqrt, liquidity, shared.RoundingUp)
+	inputAmount, err := GetAmountBFromLiquidityDelta(sqrtPrice, nextSqrt, liquidity, shared.RoundingUp)
+	if err != nil {
+		return nil, nil, err
+	}
 	return inputAmount, nextSqrt, nil
 }
 
@@ -441,3 +467,52 @@ func hasPartner(poolState *dammv2gen.Pool) bool {
 func isSwapEnabled(pool *dammv2gen.Pool, currentPoint *big.Int) bool {
 	return pool.PoolStatus == uint8(shared.PoolStatusEnable) && currentPoint.Cmp(big.NewInt(int64(pool.ActivationPoint))) >= 0
 }
+
+// swapAmounts holds the results of a swap before they are narrowed to the on-chain integer types.
+type swapAmounts struct {
+	includedFeeInput *big.Int
+	excludedFeeInput *big.Int
+	amountLeft       *big.Int
+	output           *big.Int
+	nextSqrtPrice    *big.Int
+	tradingFee       *big.Int
+	protocolFee      *big.Int
+	partnerFee       *big.Int
+	referralFee      *big.Int
+}
+
+// toSwapResult range-checks every field the way the program does before building the result.
+func (s swapAmounts) toSwapResult() (shared.SwapResult2, error) {
+	var (
+		r   shared.SwapResult2
+		err error
+	)
+	if r.IncludedFeeInputAmount, err = shared.CheckedU64(s.includedFeeInput, "swap included fee input amount"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.ExcludedFeeInputAmount, err = shared.CheckedU64(s.excludedFeeInput, "swap excluded fee input amount"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.AmountLeft, err = shared.CheckedU64(s.amountLeft, "swap amount left"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.OutputAmount, err = shared.CheckedU64(s.output, "swap output amount"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.NextSqrtPrice, err = u128FromBig(s.nextSqrtPrice, "swap next sqrt price"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.TradingFee, err = shared.CheckedU64(s.tradingFee, "swap trading fee"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.ProtocolFee, err = shared.CheckedU64(s.protocolFee, "swap protocol fee"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.PartnerFee, err = shared.CheckedU64(s.partnerFee, "swap partner fee"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	if r.ReferralFee, err = shared.CheckedU64(s.referralFee, "swap referral fee"); err != nil {
+		return shared.SwapResult2{}, err
+	}
+	return r, nil
+}
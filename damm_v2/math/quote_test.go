@@ -0,0 +1,57 @@
+package math
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+)
+
+func TestToSwapResultBoundaries(t *testing.T) {
+	u64Max := new(big.Int).SetUint64(^uint64(0))
+	u64Over := new(big.Int).Lsh(big.NewInt(1), 64)
+	u128Max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	u128Over := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	fields := []struct {
+		name string
+		set  func(s *swapAmounts, v *big.Int)
+		max  *big.Int
+		over *big.Int
+	}{
+		{"included fee input", func(s *swapAmounts, v *big.Int) { s.includedFeeInput = v }, u64Max, u64Over},
+		{"excluded fee input", func(s *swapAmounts, v *big.Int) { s.excludedFeeInput = v }, u64Max, u64Over},
+		{"amount left", func(s *swapAmounts, v *big.Int) { s.amountLeft = v }, u64Max, u64Over},
+		{"output", func(s *swapAmounts, v *big.Int) { s.output = v }, u64Max, u64Over},
+		{"next sqrt price", func(s *swapAmounts, v *big.Int) { s.nextSqrtPrice = v }, u128Max, u128Over},
+		{"trading fee", func(s *swapAmounts, v *big.Int) { s.tradingFee = v }, u64Max, u64Over},
+		{"protocol fee", func(s *swapAmounts, v *big.Int) { s.protocolFee = v }, u64Max, u64Over},
+		{"partner fee", func(s *swapAmounts, v *big.Int) { s.partnerFee = v }, u64Max, u64Over},
+		{"referral fee", func(s *swapAmounts, v *big.Int) { s.referralFee = v }, u64Max, u64Over},
+	}
+	for _, f := range fields {
+		t.Run(f.name, func(t *testing.T) {
+			var s swapAmounts
+			f.set(&s, f.max)
+			if _, err := s.toSwapResult(); err != nil {
+				t.Fatalf("toSwapResult() at max: %v", err)
+			}
+			f.set(&s, f.over)
+			if _, err := s.toSwapResult(); !errors.Is(err, shared.ErrMathOverflow) {
+				t.Fatalf("toSwapResult() at max+1 = %v, want ErrMathOverflow", err)
+			}
+		})
+	}
+}
+
+func TestToSwapResultNextSqrtPrice(t *testing.T) {
+	sqrtPrice := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(7), 64), big.NewInt(3))
+	r, err := swapAmounts{nextSqrtPrice: sqrtPrice}.toSwapResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.NextSqrtPrice.BigInt().Cmp(sqrtPrice) != 0 {
+		t.Fatalf("NextSqrtPrice = %s, want %s", r.NextSqrtPrice.BigInt(), sqrtPrice)
+	}
+}
@@ -4,13 +4,18 @@ import (
 	"math/big"
 
 	binary "github.com/gagliardetto/binary"
+
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
 )
 
-func u128FromBig(v *big.Int) binary.Uint128 {
+func u128FromBig(v *big.Int, op string) (binary.Uint128, error) {
 	if v == nil {
-		return binary.Uint128{}
+		return binary.Uint128{}, nil
+	}
+	if err := shared.CheckedU128(v, op); err != nil {
+		return binary.Uint128{}, err
 	}
 	lo := new(big.Int).And(v, new(big.Int).SetUint64(^uint64(0))).Uint64()
 	hi := new(big.Int).Rsh(new(big.Int).Set(v), 64).Uint64()
-	return binary.Uint128{Lo: lo, Hi: hi}
+	return binary.Uint128{Lo: lo, Hi: hi}, nil
 }
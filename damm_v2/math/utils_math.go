@@ -8,16 +8,19 @@ import (
 	"github.com/krazyTry/meteora-go/damm_v2/shared"
 )
 
-func MulDiv(x, y, denominator *big.Int, rounding shared.Rounding) *big.Int {
+func MulDiv(x, y, denominator *big.Int, rounding shared.Rounding) (*big.Int, error) {
 	if denominator.Sign() == 0 {
-		return big.NewInt(0)
+		return big.NewInt(0), nil
 	}
 	mul := new(big.Int).Mul(x, y)
+	if err := shared.CheckedU256(mul, "MulDiv product"); err != nil {
+		return nil, err
+	}
 	div, mod := new(big.Int).QuoRem(mul, denominator, new(big.Int))
 	if rounding == shared.RoundingUp && mod.Sign() != 0 {
-		return div.Add(div, big.NewInt(1))
+		return div.Add(div, big.NewInt(1)), nil
 	}
-	return div
+	return div, nil
 }
 
 func Q64ToDecimal(num *big.Int, decimalPlaces int32) decimal.Decimal {
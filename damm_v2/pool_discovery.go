@@ -0,0 +1,364 @@
+package dammv2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+)
+
+// FetchPoolStatesByTokenBMint returns every pool whose token B is tokenBMint.
+// The FetchPoolStatesBy queries download the full matching pool accounts; a PoolIndex answers
+// repeated lookups that only need pool addresses without touching the RPC.
+func (c *CpAmm) FetchPoolStatesByTokenBMint(ctx context.Context, tokenBMint solanago.PublicKey) ([]*AccountWithPool, error) {
+	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, &helpers.Filter{
+		Owner:  tokenBMint,
+		Offset: helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenBMint"),
+	})
+	return c.fetchPools(ctx, filters)
+}
+
+// FetchPoolStatesByCreator returns every pool created by creator.
+func (c *CpAmm) FetchPoolStatesByCreator(ctx context.Context, creator solanago.PublicKey) ([]*AccountWithPool, error) {
+	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, &helpers.Filter{
+		Owner:  creator,
+		Offset: helpers.ComputeStructOffset(new(dammv2gen.Pool), "Creator"),
+	})
+	return c.fetchPools(ctx, filters)
+}
+
+// FetchPoolStatesByStatus returns every pool with the given status.
+func (c *CpAmm) FetchPoolStatesByStatus(ctx context.Context, status PoolStatus) ([]*AccountWithPool, error) {
+	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, nil)
+	filters = append(filters, rpc.RPCFilter{
+		Memcmp: &rpc.RPCFilterMemcmp{
+			Offset: helpers.ComputeStructOffset(new(dammv2gen.Pool), "PoolStatus"),
+			Bytes:  []byte{uint8(status)},
+		},
+	})
+	return c.fetchPools(ctx, filters)
+}
+
+// FetchPoolStatesByPair returns every pool of the two mints, in either token order.
+func (c *CpAmm) FetchPoolStatesByPair(ctx context.Context, mintX, mintY solanago.PublicKey) ([]*AccountWithPool, error) {
+	tokenAOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenAMint")
+	tokenBOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenBMint")
+
+	out := []*AccountWithPool{}
+	orderings := [][2]solanago.PublicKey{{mintX, mintY}, {mintY, mintX}}
+	if mintX.Equals(mintY) {
+		orderings = orderings[:1]
+	}
+	for _, pair := range orderings {
+		filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, &helpers.Filter{
+			Owner:  pair[0],
+			Offset: tokenAOffset,
+		})
+		filters = append(filters, rpc.RPCFilter{
+			Memcmp: &rpc.RPCFilterMemcmp{
+				Offset: tokenBOffset,
+				Bytes:  pair[1][:],
+			},
+		})
+		pools, err := c.fetchPools(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pools...)
+	}
+	return out, nil
+}
+
+// FetchPoolStatesByConfig returns every pool created from config.
+// The pool account does not store its config, so only the token mints of every pool are downloaded
+// and matched against the pool address derived from config.
+func (c *CpAmm) FetchPoolStatesByConfig(ctx context.Context, config solanago.PublicKey) ([]*AccountWithPool, error) {
+	tokenAOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenAMint")
+	length := uint64(2 * solanago.PublicKeyLength)
+	accs, err := c.Client.GetProgramAccountsWithOpts(ctx, dammv2gen.ProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: c.Commitment,
+		Filters:    helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, nil),
+		DataSlice:  &rpc.DataSlice{Offset: &tokenAOffset, Length: &length},
+	})
+	if err != nil {
+		return nil, err
+	}
+	poolKeys := make([]solanago.PublicKey, 0)
+	for _, acc := range accs {
+		data := acc.Account.Data.GetBinary()
+		if len(data) < int(length) {
+			continue
+		}
+		tokenAMint := solanago.PublicKeyFromBytes(data[:solanago.PublicKeyLength])
+		tokenBMint := solanago.PublicKeyFromBytes(data[solanago.PublicKeyLength:length])
+		if DerivePoolAddress(config, tokenAMint, tokenBMint).Equals(acc.Pubkey) {
+			poolKeys = append(poolKeys, acc.Pubkey)
+		}
+	}
+
+	out := make([]*AccountWithPool, 0, len(poolKeys))
+	for start := 0; start < len(poolKeys); start += maxMultipleAccounts {
+		end := min(start+maxMultipleAccounts, len(poolKeys))
+		pools, err := c.GetMultiplePools(ctx, poolKeys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i, pool := range pools {
+			out = append(out, &AccountWithPool{PublicKey: poolKeys[start+i], Account: pool})
+		}
+	}
+	return out, nil
+}
+
+// fetchPools returns the pool accounts matching filters.
+func (c *CpAmm) fetchPools(ctx context.Context, filters []rpc.RPCFilter) ([]*AccountWithPool, error) {
+	accs, err := c.Client.GetProgramAccountsWithOpts(ctx, dammv2gen.ProgramID, &rpc.GetProgramAccountsOpts{Commitment: c.Commitment, Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	out := []*AccountWithPool{}
+	for _, acc := range accs {
+		parsed, err := dammv2gen.ParseAnyAccount(acc.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		if pl, ok := parsed.(*dammv2gen.Pool); ok {
+			out = append(out, &AccountWithPool{PublicKey: acc.Pubkey, Account: pl})
+		}
+	}
+	return out, nil
+}
+
+// pairKey identifies a token pair regardless of token order.
+type pairKey [2 * solanago.PublicKeyLength]byte
+
+func newPairKey(mintX, mintY solanago.PublicKey) pairKey {
+	var key pairKey
+	if bytes.Compare(mintX[:], mintY[:]) > 0 {
+		mintX, mintY = mintY, mintX
+	}
+	copy(key[:solanago.PublicKeyLength], mintX[:])
+	copy(key[solanago.PublicKeyLength:], mintY[:])
+	return key
+}
+
+// PoolIndex is an in-memory index of pools by pair, mint and creator. It is safe for concurrent use.
+type PoolIndex struct {
+	mu        sync.RWMutex
+	pools     map[solanago.PublicKey]PoolIndexEntry
+	byPair    map[pairKey][]solanago.PublicKey
+	byMint    map[solanago.PublicKey][]solanago.PublicKey
+	byCreator map[solanago.PublicKey][]solanago.PublicKey
+}
+
+// NewPoolIndex returns an empty pool index.
+func NewPoolIndex() *PoolIndex {
+	return &PoolIndex{
+		pools:     make(map[solanago.PublicKey]PoolIndexEntry),
+		byPair:    make(map[pairKey][]solanago.PublicKey),
+		byMint:    make(map[solanago.PublicKey][]solanago.PublicKey),
+		byCreator: make(map[solanago.PublicKey][]solanago.PublicKey),
+	}
+}
+
+// BuildPoolIndex indexes every pool of the program. Only the slice of each pool account holding the
+// indexed fields is downloaded; WatchPoolIndex keeps the index current afterwards.
+func (c *CpAmm) BuildPoolIndex(ctx context.Context) (*PoolIndex, error) {
+	tokenAOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenAMint")
+	tokenBOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "TokenBMint") - tokenAOffset
+	statusOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "PoolStatus") - tokenAOffset
+	creatorOffset := helpers.ComputeStructOffset(new(dammv2gen.Pool), "Creator") - tokenAOffset
+	length := creatorOffset + solanago.PublicKeyLength
+	accs, err := c.Client.GetProgramAccountsWithOpts(ctx, dammv2gen.ProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: c.Commitment,
+		Filters:    helpers.CreateProgramAccountFilter(helpers.AccountKeyPool, nil),
+		DataSlice:  &rpc.DataSlice{Offset: &tokenAOffset, Length: &length},
+	})
+	if err != nil {
+		return nil, err
+	}
+	index := NewPoolIndex()
+	for _, acc := range accs {
+		data := acc.Account.Data.GetBinary()
+		if len(data) < int(length) {
+			continue
+		}
+		index.Add(PoolIndexEntry{
+			Pool:       acc.Pubkey,
+			TokenAMint: solanago.PublicKeyFromBytes(data[:solanago.PublicKeyLength]),
+			TokenBMint: solanago.PublicKeyFromBytes(data[tokenBOffset : tokenBOffset+solanago.PublicKeyLength]),
+			Creator:    solanago.PublicKeyFromBytes(data[creatorOffset:length]),
+			PoolStatus: PoolStatus(data[statusOffset]),
+		})
+	}
+	return index, nil
+}
+
+// UpdatePoolIndex applies the pool creation and status events of a transaction to index.
+func (c *CpAmm) UpdatePoolIndex(ctx context.Context, index *PoolIndex, signature solanago.Signature) error {
+	events, err := c.getTransactionEvents(ctx, signature)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		index.ApplyEvent(event)
+	}
+	return nil
+}
+
+// poolIndexInstructionLogs are the instruction logs of the transactions that change a PoolIndex.
+var poolIndexInstructionLogs = []string{
+	"Program log: Instruction: InitializePool",
+	"Program log: Instruction: InitializeCustomizablePool",
+	"Program log: Instruction: InitializePoolWithDynamicConfig",
+	"Program log: Instruction: SetPoolStatus",
+}
+
+// WatchPoolIndex subscribes to the program logs and applies the EvtInitializePool and EvtSetPoolStatus
+// events of every successful pool creation or status change to index. The events are emitted through
+// self CPI rather than logged, so each matching transaction is fetched. It blocks until ctx is done or
+// the subscription fails; transactions landing before the subscription starts are not replayed.
+func (c *CpAmm) WatchPoolIndex(ctx context.Context, wsClient *ws.Client, index *PoolIndex) error {
+	sub, err := wsClient.LogsSubscribeMentions(dammv2gen.ProgramID, c.Commitment)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		got, err := sub.Recv(ctx)
+		if err != nil {
+			return err
+		}
+		if got == nil || got.Value.Err != nil || !hasPoolIndexInstruction(got.Value.Logs) {
+			continue
+		}
+		if err := c.UpdatePoolIndex(ctx, index, got.Value.Signature); err != nil {
+			return err
+		}
+	}
+}
+
+func hasPoolIndexInstruction(logs []string) bool {
+	for _, log := range logs {
+		if slices.Contains(poolIndexInstructionLogs, log) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add indexes a pool, replacing any previous entry of the same pool.
+func (idx *PoolIndex) Add(entry PoolIndexEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.pools[entry.Pool]; ok {
+		idx.pools[entry.Pool] = entry
+		return
+	}
+	idx.pools[entry.Pool] = entry
+	key := newPairKey(entry.TokenAMint, entry.TokenBMint)
+	idx.byPair[key] = append(idx.byPair[key], entry.Pool)
+	idx.byMint[entry.TokenAMint] = append(idx.byMint[entry.TokenAMint], entry.Pool)
+	if !entry.TokenBMint.Equals(entry.TokenAMint) {
+		idx.byMint[entry.TokenBMint] = append(idx.byMint[entry.TokenBMint], entry.Pool)
+	}
+	idx.byCreator[entry.Creator] = append(idx.byCreator[entry.Creator], entry.Pool)
+}
+
+// ApplyEvent updates the index from an EvtInitializePool or EvtSetPoolStatus event; other events are ignored.
+func (idx *PoolIndex) ApplyEvent(event any) {
+	switch evt := event.(type) {
+	case *dammv2gen.EvtInitializePool:
+		idx.Add(PoolIndexEntry{
+			Pool:       evt.Pool,
+			TokenAMint: evt.TokenAMint,
+			TokenBMint: evt.TokenBMint,
+			Creator:    evt.Creator,
+			PoolStatus: PoolStatusEnable,
+		})
+	case *dammv2gen.EvtSetPoolStatus:
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		if entry, ok := idx.pools[evt.Pool]; ok {
+			entry.PoolStatus = PoolStatus(evt.Status)
+			idx.pools[evt.Pool] = entry
+		}
+	}
+}
+
+// Get returns the entry of pool.
+func (idx *PoolIndex) Get(pool solanago.PublicKey) (PoolIndexEntry, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	entry, ok := idx.pools[pool]
+	return entry, ok
+}
+
+// Len returns the number of indexed pools.
+func (idx *PoolIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.pools)
+}
+
+// PoolsByPair returns the pools of the two mints, in either token order.
+func (idx *PoolIndex) PoolsByPair(mintX, mintY solanago.PublicKey) []PoolIndexEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.entries(idx.byPair[newPairKey(mintX, mintY)])
+}
+
+// PoolsByMint returns the pools with mint as token A or token B.
+func (idx *PoolIndex) PoolsByMint(mint solanago.PublicKey) []PoolIndexEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.entries(idx.byMint[mint])
+}
+
+// PoolsByCreator returns the pools created by creator.
+func (idx *PoolIndex) PoolsByCreator(creator solanago.PublicKey) []PoolIndexEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.entries(idx.byCreator[creator])
+}
+
+func (idx *PoolIndex) entries(pools []solanago.PublicKey) []PoolIndexEntry {
+	out := make([]PoolIndexEntry, 0, len(pools))
+	for _, pool := range pools {
+		out = append(out, idx.pools[pool])
+	}
+	return out
+}
+
+// Save writes the index to w as JSON.
+func (idx *PoolIndex) Save(w io.Writer) error {
+	idx.mu.RLock()
+	entries := make([]PoolIndexEntry, 0, len(idx.pools))
+	for _, entry := range idx.pools {
+		entries = append(entries, entry)
+	}
+	idx.mu.RUnlock()
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// LoadPoolIndex reads an index written by PoolIndex.Save.
+func LoadPoolIndex(r io.Reader) (*PoolIndex, error) {
+	var entries []PoolIndexEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	index := NewPoolIndex()
+	for _, entry := range entries {
+		index.Add(entry)
+	}
+	return index, nil
+}
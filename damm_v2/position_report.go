@@ -0,0 +1,253 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+)
+
+// GetPositionReport values the liquidity, fees and rewards of a position at the current pool price.
+// Realized fees are valued at the current price as well.
+func (c *CpAmm) GetPositionReport(params GetPositionReportParams) (PositionReport, error) {
+	poolState := params.PoolState
+	positionState := params.PositionState
+	quoteIsA := poolState.TokenAMint.Equals(params.QuoteMint)
+	if !quoteIsA && !poolState.TokenBMint.Equals(params.QuoteMint) {
+		return PositionReport{}, errors.New("quote mint does not belong to pool")
+	}
+
+	sqrtPrice := poolState.SqrtPrice.BigInt()
+	totalLiquidity := totalPositionLiquidity(positionState)
+	withdrawQuote, err := c.GetWithdrawQuote(GetWithdrawQuoteParams{
+		LiquidityDelta:  totalLiquidity,
+		MinSqrtPrice:    poolState.SqrtMinPrice.BigInt(),
+		MaxSqrtPrice:    poolState.SqrtMaxPrice.BigInt(),
+		SqrtPrice:       sqrtPrice,
+		TokenATokenInfo: params.TokenATokenInfo,
+		TokenBTokenInfo: params.TokenBTokenInfo,
+	})
+	if err != nil {
+		return PositionReport{}, err
+	}
+
+	feeA, feeB, pendingRewards, err := helpers.GetUnClaimLpFee(poolState, positionState)
+	if err != nil {
+		return PositionReport{}, err
+	}
+	realizedFeeA := new(big.Int).SetUint64(positionState.Metrics.TotalClaimedAFee)
+	realizedFeeB := new(big.Int).SetUint64(positionState.Metrics.TotalClaimedBFee)
+
+	rewards := make([]PositionRewardReport, 0, len(poolState.RewardInfos))
+	for i, rewardInfo := range poolState.RewardInfos {
+		if rewardInfo.Initialized == 0 {
+			continue
+		}
+		pending := pendingRewards[i]
+		if params.CurrentTime != nil {
+			_, pending, err = helpers.GetUserRewardPending(poolState, positionState, i, params.CurrentTime, big.NewInt(0))
+			if err != nil {
+				return PositionReport{}, err
+			}
+		}
+		rewards = append(rewards, PositionRewardReport{
+			Index:         uint8(i),
+			Mint:          rewardInfo.Mint,
+			PendingAmount: pending,
+			ClaimedAmount: new(big.Int).SetUint64(positionState.RewardInfos[i].TotalClaimedRewards),
+		})
+	}
+
+	liquidityValue := valueInQuote(withdrawQuote.OutAmountA, withdrawQuote.OutAmountB, sqrtPrice, quoteIsA)
+	unclaimedFeeValue := valueInQuote(feeA, feeB, sqrtPrice, quoteIsA)
+	price := helpers.GetPriceFromSqrtPrice(sqrtPrice, params.TokenADecimal, params.TokenBDecimal)
+	if quoteIsA && !price.IsZero() {
+		price = decimal.NewFromInt(1).Div(price)
+	}
+
+	report := PositionReport{
+		QuoteMint:                params.QuoteMint,
+		Price:                    price,
+		TotalLiquidity:           totalLiquidity,
+		UnlockedLiquidity:        positionState.UnlockedLiquidity.BigInt(),
+		VestingLiquidity:         positionState.VestedLiquidity.BigInt(),
+		PermanentLockedLiquidity: positionState.PermanentLockedLiquidity.BigInt(),
+		AmountA:                  withdrawQuote.OutAmountA,
+		AmountB:                  withdrawQuote.OutAmountB,
+		UnclaimedFeeA:            feeA,
+		UnclaimedFeeB:            feeB,
+		RealizedFeeA:             realizedFeeA,
+		RealizedFeeB:             realizedFeeB,
+		Rewards:                  rewards,
+		LiquidityValue:           liquidityValue,
+		UnclaimedFeeValue:        unclaimedFeeValue,
+		RealizedFeeValue:         valueInQuote(realizedFeeA, realizedFeeB, sqrtPrice, quoteIsA),
+		TotalValue:               new(big.Int).Add(liquidityValue, unclaimedFeeValue),
+	}
+
+	if params.Entry == nil {
+		return report, nil
+	}
+	entryA, entryB := params.Entry.AmountA, params.Entry.AmountB
+	if entryA == nil || entryB == nil {
+		if params.Entry.SqrtPrice == nil {
+			return PositionReport{}, errors.New("entry requires amounts or sqrt price")
+		}
+		entryA, entryB, err = positionAmountsAtSqrtPrice(poolState, totalLiquidity, params.Entry.SqrtPrice)
+		if err != nil {
+			return PositionReport{}, err
+		}
+	}
+	holdValue := valueInQuote(entryA, entryB, sqrtPrice, quoteIsA)
+	report.HoldValue = holdValue
+	report.ImpermanentLoss = new(big.Int).Sub(liquidityValue, holdValue)
+	report.ImpermanentLossPct = decimal.Zero
+	if holdValue.Sign() > 0 {
+		report.ImpermanentLossPct = decimal.NewFromBigInt(report.ImpermanentLoss, 0).Div(decimal.NewFromBigInt(holdValue, 0))
+	}
+	report.PnL = new(big.Int).Add(report.TotalValue, report.RealizedFeeValue)
+	report.PnL.Sub(report.PnL, holdValue)
+	return report, nil
+}
+
+// PositionEntryFromLiquidityChanges derives the cost basis of a position from its liquidity change events in chronological order.
+// Removals reduce the basis pro rata to the liquidity removed.
+func PositionEntryFromLiquidityChanges(events []*dammv2gen.EvtLiquidityChange) PositionEntry {
+	amountA := big.NewInt(0)
+	amountB := big.NewInt(0)
+	liquidity := big.NewInt(0)
+	for _, evt := range events {
+		delta := evt.LiquidityDelta.BigInt()
+		switch LiquidityChangeType(evt.ChangeType) {
+		case LiquidityChangeTypeAdd:
+			amountA.Add(amountA, new(big.Int).SetUint64(evt.TokenAAmount))
+			amountB.Add(amountB, new(big.Int).SetUint64(evt.TokenBAmount))
+			liquidity.Add(liquidity, delta)
+		case LiquidityChangeTypeRemove:
+			if liquidity.Sign() == 0 {
+				continue
+			}
+			if delta.Cmp(liquidity) >= 0 {
+				amountA.SetInt64(0)
+				amountB.SetInt64(0)
+				liquidity.SetInt64(0)
+				continue
+			}
+			amountA.Sub(amountA, new(big.Int).Div(new(big.Int).Mul(amountA, delta), liquidity))
+			amountB.Sub(amountB, new(big.Int).Div(new(big.Int).Mul(amountB, delta), liquidity))
+			liquidity.Sub(liquidity, delta)
+		}
+	}
+	return PositionEntry{AmountA: amountA, AmountB: amountB}
+}
+
+// GetPositionLiquidityChanges returns the liquidity change events of a position, oldest first.
+// Only the latest limit transactions of the position are scanned.
+func (c *CpAmm) GetPositionLiquidityChanges(ctx context.Context, position solanago.PublicKey, limit int) ([]*dammv2gen.EvtLiquidityChange, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	sigs, err := c.Client.GetSignaturesForAddressWithOpts(ctx, position, &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: c.Commitment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*dammv2gen.EvtLiquidityChange, 0)
+	for i := len(sigs) - 1; i >= 0; i-- {
+		if sigs[i].Err != nil {
+			continue
+		}
+		events, err := c.getTransactionEvents(ctx, sigs[i].Signature)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			evt, ok := event.(*dammv2gen.EvtLiquidityChange)
+			if ok && evt.Position.Equals(position) {
+				out = append(out, evt)
+			}
+		}
+	}
+	return out, nil
+}
+
+// getTransactionEvents decodes the program events emitted through self CPI in a transaction.
+func (c *CpAmm) getTransactionEvents(ctx context.Context, signature solanago.Signature) ([]any, error) {
+	maxSupportedTransactionVersion := uint64(0)
+	out, err := c.Client.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solanago.EncodingBase64,
+		Commitment:                     c.Commitment,
+		MaxSupportedTransactionVersion: &maxSupportedTransactionVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Meta == nil {
+		return nil, nil
+	}
+	tx, err := out.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]any, 0)
+	for _, inner := range out.Meta.InnerInstructions {
+		for _, inst := range inner.Instructions {
+			programID, err := tx.Message.ResolveProgramIDIndex(inst.ProgramIDIndex)
+			if err != nil || !dammv2gen.ProgramID.Equals(programID) || len(inst.Data) <= 8 {
+				continue
+			}
+			event, err := dammv2gen.ParseAnyEvent(inst.Data[8:])
+			if err != nil {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// positionAmountsAtSqrtPrice returns the token amounts backing liquidity at sqrtPrice clamped to the pool range.
+func positionAmountsAtSqrtPrice(poolState *PoolState, liquidity, sqrtPrice *big.Int) (*big.Int, *big.Int, error) {
+	minSqrtPrice := poolState.SqrtMinPrice.BigInt()
+	maxSqrtPrice := poolState.SqrtMaxPrice.BigInt()
+	if sqrtPrice.Cmp(minSqrtPrice) < 0 {
+		sqrtPrice = minSqrtPrice
+	}
+	if sqrtPrice.Cmp(maxSqrtPrice) > 0 {
+		sqrtPrice = maxSqrtPrice
+	}
+	amountA, err := math.GetAmountAFromLiquidityDelta(sqrtPrice, maxSqrtPrice, liquidity, RoundingDown)
+	if err != nil {
+		return nil, nil, err
+	}
+	amountB, err := math.GetAmountBFromLiquidityDelta(minSqrtPrice, sqrtPrice, liquidity, RoundingDown)
+	if err != nil {
+		return nil, nil, err
+	}
+	return amountA, amountB, nil
+}
+
+// valueInQuote converts raw token amounts into raw units of token A or token B at sqrtPrice.
+func valueInQuote(amountA, amountB, sqrtPrice *big.Int, quoteIsA bool) *big.Int {
+	priceX128 := new(big.Int).Mul(sqrtPrice, sqrtPrice)
+	if quoteIsA {
+		out := new(big.Int).Set(amountA)
+		if priceX128.Sign() > 0 {
+			out.Add(out, new(big.Int).Div(new(big.Int).Lsh(amountB, 2*shared.ScaleOffset), priceX128))
+		}
+		return out
+	}
+	out := new(big.Int).Mul(amountA, priceX128)
+	out.Rsh(out, 2*shared.ScaleOffset)
+	return out.Add(out, amountB)
+}
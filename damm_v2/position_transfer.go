@@ -0,0 +1,78 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+)
+
+// TransferPosition builds a transaction that moves a position NFT to the recipient's associated token account.
+// The recipient may be a PDA; its token account is created when missing.
+func (c *CpAmm) TransferPosition(ctx context.Context, params TransferPositionParams) (TxBuilder, error) {
+	if params.Recipient.Equals(params.Owner) {
+		return nil, errors.New("recipient must differ from owner")
+	}
+	recipientAccount, createIx, err := helpers.GetOrCreateATAInstruction(ctx, c.Client, params.PositionNftMint, params.Recipient, params.Payer, solanago.Token2022ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	if recipientAccount.Equals(params.PositionNftAccount) {
+		return nil, errors.New("position nft already held by recipient account")
+	}
+
+	builder := solanago.NewTransactionBuilder()
+	if createIx != nil {
+		builder.AddInstruction(createIx)
+	}
+	builder.AddInstruction(helpers.TransferCheckedInstruction(
+		params.PositionNftAccount,
+		params.PositionNftMint,
+		recipientAccount,
+		params.Owner,
+		1,
+		0,
+		solanago.Token2022ProgramID,
+	))
+	if params.CloseSourceAccount {
+		builder.AddInstruction(helpers.CloseAccountInstruction(params.PositionNftAccount, params.Owner, params.Owner, solanago.Token2022ProgramID))
+	}
+	return builder, nil
+}
+
+// GetPositionOwner resolves the wallet currently holding the NFT of position.
+func (c *CpAmm) GetPositionOwner(ctx context.Context, position solanago.PublicKey) (PositionOwner, error) {
+	positionState, err := c.FetchPositionState(ctx, position)
+	if err != nil {
+		return PositionOwner{}, err
+	}
+	largest, err := c.Client.GetTokenLargestAccounts(ctx, positionState.NftMint, c.Commitment)
+	if err != nil {
+		return PositionOwner{}, err
+	}
+	for _, acc := range largest.Value {
+		if acc.Amount != "1" {
+			continue
+		}
+		info, err := c.Client.GetAccountInfoWithOpts(ctx, acc.Address, &rpc.GetAccountInfoOpts{Commitment: c.Commitment})
+		if err != nil {
+			return PositionOwner{}, err
+		}
+		var tokenAcc token.Account
+		if err := tokenAcc.UnmarshalWithDecoder(bin.NewBinDecoder(info.GetBinary())); err != nil {
+			return PositionOwner{}, err
+		}
+		return PositionOwner{
+			Owner:              tokenAcc.Owner,
+			PositionNftMint:    positionState.NftMint,
+			PositionNftAccount: acc.Address,
+		}, nil
+	}
+	return PositionOwner{}, fmt.Errorf("position nft %s has no holder", positionState.NftMint.String())
+}
@@ -0,0 +1,129 @@
+package dammv2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+)
+
+// secondsPerYear is used to annualize reward emissions.
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// GetRewardAnalytics reports emission, remaining balance, ineligible reward and APR of every initialized reward of a pool.
+func (c *CpAmm) GetRewardAnalytics(params GetRewardAnalyticsParams) (PoolRewardAnalytics, error) {
+	poolState := params.PoolState
+	if params.CurrentTime == nil {
+		return PoolRewardAnalytics{}, errors.New("current time is required")
+	}
+
+	tvl := decimal.Zero
+	if params.Tvl != nil {
+		tvl = *params.Tvl
+	} else {
+		sqrtPrice := poolState.SqrtPrice.BigInt()
+		liquidity := poolState.Liquidity.BigInt()
+		amountA, err := math.GetAmountAFromLiquidityDelta(sqrtPrice, poolState.SqrtMaxPrice.BigInt(), liquidity, RoundingDown)
+		if err != nil {
+			return PoolRewardAnalytics{}, err
+		}
+		amountB, err := math.GetAmountBFromLiquidityDelta(poolState.SqrtMinPrice.BigInt(), sqrtPrice, liquidity, RoundingDown)
+		if err != nil {
+			return PoolRewardAnalytics{}, err
+		}
+		tvl = decimal.NewFromBigInt(amountA, -int32(params.TokenADecimal)).Mul(params.TokenAPrice).
+			Add(decimal.NewFromBigInt(amountB, -int32(params.TokenBDecimal)).Mul(params.TokenBPrice))
+	}
+
+	out := PoolRewardAnalytics{Tvl: tvl, Rewards: make([]RewardAnalytics, 0, len(poolState.RewardInfos))}
+	for i, rewardInfo := range poolState.RewardInfos {
+		if rewardInfo.Initialized == 0 {
+			continue
+		}
+		rewardRate := rewardInfo.RewardRate.BigInt()
+		rewardDurationEnd := new(big.Int).SetUint64(rewardInfo.RewardDurationEnd)
+		lastTimeRewardApplicable := minBig(params.CurrentTime, rewardDurationEnd)
+
+		remainingDuration := new(big.Int).Sub(rewardDurationEnd, params.CurrentTime)
+		if remainingDuration.Sign() < 0 {
+			remainingDuration.SetInt64(0)
+		}
+		remainingBalance := new(big.Int).Mul(rewardRate, remainingDuration)
+		remainingBalance.Rsh(remainingBalance, shared.ScaleOffset)
+
+		emptySeconds := new(big.Int).SetUint64(rewardInfo.CumulativeSecondsWithEmptyLiquidityReward)
+		if poolState.Liquidity.BigInt().Sign() == 0 {
+			elapsed := new(big.Int).Sub(lastTimeRewardApplicable, new(big.Int).SetUint64(rewardInfo.LastUpdateTime))
+			if elapsed.Sign() > 0 {
+				emptySeconds.Add(emptySeconds, elapsed)
+			}
+		}
+		ineligibleReward := new(big.Int).Mul(rewardRate, emptySeconds)
+		ineligibleReward.Rsh(ineligibleReward, shared.ScaleOffset)
+
+		rewardDecimal, ok := params.RewardDecimals[rewardInfo.Mint]
+		if !ok {
+			return PoolRewardAnalytics{}, fmt.Errorf("reward decimals are required for reward mint %s", rewardInfo.Mint)
+		}
+		rewardPerSecond := decimal.NewFromBigInt(rewardRate, -int32(rewardDecimal)).Div(decimal.NewFromBigInt(shared.OneQ64, 0))
+
+		analytics := RewardAnalytics{
+			Index:             uint8(i),
+			Mint:              rewardInfo.Mint,
+			Vault:             rewardInfo.Vault,
+			Funder:            rewardInfo.Funder,
+			RewardRate:        rewardRate,
+			RewardPerSecond:   rewardPerSecond,
+			RewardDuration:    new(big.Int).SetUint64(rewardInfo.RewardDuration),
+			RewardDurationEnd: rewardDurationEnd,
+			RemainingDuration: remainingDuration,
+			RemainingBalance:  remainingBalance,
+			IneligibleReward:  ineligibleReward,
+			Apr:               decimal.Zero,
+			Active:            remainingDuration.Sign() > 0,
+		}
+		if params.EndingSoonThreshold != nil {
+			analytics.EndingSoon = analytics.Active && remainingDuration.Cmp(params.EndingSoonThreshold) <= 0
+		}
+		if price, ok := params.RewardPrices[rewardInfo.Mint]; ok && analytics.Active && tvl.Sign() > 0 {
+			annualValue := rewardPerSecond.Mul(decimal.NewFromInt(secondsPerYear)).Mul(price)
+			analytics.Apr = annualValue.Div(tvl)
+		}
+		out.Rewards = append(out.Rewards, analytics)
+	}
+	return out, nil
+}
+
+// GetPositionRewardSchedule projects the pending rewards of a position at the end of each period, assuming constant pool liquidity.
+func (c *CpAmm) GetPositionRewardSchedule(params GetPositionRewardScheduleParams) ([]PositionRewardSchedulePoint, error) {
+	if params.CurrentTime == nil || params.Period == nil || params.Period.Sign() <= 0 {
+		return nil, errors.New("current time and period are required")
+	}
+	out := make([]PositionRewardSchedulePoint, 0, params.NumberOfPeriods)
+	for period := 1; period <= params.NumberOfPeriods; period++ {
+		time := new(big.Int).Mul(params.Period, big.NewInt(int64(period)))
+		time.Add(time, params.CurrentTime)
+		point := PositionRewardSchedulePoint{
+			Time:           time,
+			PendingRewards: make([]*big.Int, len(params.PoolState.RewardInfos)),
+		}
+		for i, rewardInfo := range params.PoolState.RewardInfos {
+			point.PendingRewards[i] = big.NewInt(0)
+			if rewardInfo.Initialized == 0 {
+				continue
+			}
+			_, pending, err := helpers.GetUserRewardPending(params.PoolState, params.PositionState, i, time, big.NewInt(0))
+			if err != nil {
+				return nil, err
+			}
+			point.PendingRewards[i] = pending
+		}
+		out = append(out, point)
+	}
+	return out, nil
+}
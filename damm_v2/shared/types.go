@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	"github.com/krazyTry/meteora-go/internal/checked"
 	"github.com/shopspring/decimal"
 )
 
@@ -173,3 +174,21 @@ func bigIntFromString(v string) *big.Int {
 	}
 	return out
 }
+
+// Checked narrowing of program integers, see internal/checked.
+var (
+	ErrMathOverflow = checked.ErrMathOverflow
+	U256Max         = checked.U256Max
+)
+
+// MathOverflowError names the operation that overflowed; it matches ErrMathOverflow with errors.Is.
+type MathOverflowError = checked.MathOverflowError
+
+func NewMathOverflowError(op string) error { return checked.NewMathOverflowError(op) }
+
+// CheckedU64 narrows v to u64, failing when it does not fit. A nil v is zero.
+func CheckedU64(v *big.Int, op string) (uint64, error) { return checked.U64(v, op) }
+
+func CheckedU128(v *big.Int, op string) error { return checked.U128(v, op) }
+
+func CheckedU256(v *big.Int, op string) error { return checked.U256(v, op) }
@@ -0,0 +1,232 @@
+package dammv2
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+	"github.com/krazyTry/meteora-go/internal/checked"
+	"github.com/krazyTry/meteora-go/internal/dynamicfee"
+)
+
+// PoolSimulator applies swaps and liquidity changes to a copy of a pool state the way the program does.
+type PoolSimulator struct {
+	state PoolState
+}
+
+// NewPoolSimulator returns a simulator starting from a copy of poolState.
+func NewPoolSimulator(poolState *PoolState) *PoolSimulator {
+	return &PoolSimulator{state: *poolState}
+}
+
+// State returns a copy of the simulated pool state.
+func (s *PoolSimulator) State() *PoolState {
+	state := s.state
+	return &state
+}
+
+// Swap simulates a swap, then advances the price, fee accumulators and dynamic fee volatility.
+func (s *PoolSimulator) Swap(params SimulateSwapParams) (SimulatedSwap, error) {
+	if params.Amount == nil || params.Amount.Sign() <= 0 {
+		return SimulatedSwap{}, errors.New("amount must be greater than 0")
+	}
+	if !helpers.IsSwapEnabled(s.state, params.CurrentPoint) {
+		return SimulatedSwap{}, errors.New("swap is disabled")
+	}
+	currentTime, err := s.currentTime(params.CurrentPoint, params.CurrentTime)
+	if err != nil {
+		return SimulatedSwap{}, err
+	}
+
+	tradeDirection := TradeDirectionBtoA
+	if params.AToB {
+		tradeDirection = TradeDirectionAtoB
+	}
+	feeMode := math.GetFeeMode(s.state.CollectFeeMode, tradeDirection, params.HasReferral)
+
+	s.updateReferences(currentTime)
+
+	var result SwapResult2
+	switch params.SwapMode {
+	case SwapModeExactIn:
+		result, err = math.GetSwapResultFromExactInput(&s.state, params.Amount, feeMode, tradeDirection, params.CurrentPoint)
+	case SwapModePartialFill:
+		result, err = math.GetSwapResultFromPartialInput(&s.state, params.Amount, feeMode, tradeDirection, params.CurrentPoint)
+	case SwapModeExactOut:
+		result, err = math.GetSwapResultFromExactOutput(&s.state, params.Amount, feeMode, tradeDirection, params.CurrentPoint)
+	default:
+		return SimulatedSwap{}, errors.New("invalid swap mode")
+	}
+	if err != nil {
+		return SimulatedSwap{}, err
+	}
+
+	oldSqrtPrice := s.state.SqrtPrice.BigInt()
+	s.applySwapResult(result, feeMode)
+	s.updatePostSwap(oldSqrtPrice, currentTime)
+	return SimulatedSwap{Result: result, PoolState: s.State()}, nil
+}
+
+// AddLiquidity simulates adding liquidity; token amounts are rounded up as the program charges them.
+func (s *PoolSimulator) AddLiquidity(params SimulateLiquidityParams) (SimulatedLiquidityChange, error) {
+	if params.LiquidityDelta == nil || params.LiquidityDelta.Sign() <= 0 {
+		return SimulatedLiquidityChange{}, errors.New("liquidity delta must be greater than 0")
+	}
+	liquidity := new(big.Int).Add(s.state.Liquidity.BigInt(), params.LiquidityDelta)
+	if err := checked.AllU128("add liquidity", liquidity); err != nil {
+		return SimulatedLiquidityChange{}, err
+	}
+	amountA, amountB, err := s.amountsForModifyLiquidity(params.LiquidityDelta, RoundingUp)
+	if err != nil {
+		return SimulatedLiquidityChange{}, err
+	}
+	s.updateRewards(params.CurrentTime)
+	s.state.Liquidity = u128FromBig(liquidity)
+	return SimulatedLiquidityChange{
+		LiquidityDelta: params.LiquidityDelta,
+		AmountA:        amountA,
+		AmountB:        amountB,
+		PoolState:      s.State(),
+	}, nil
+}
+
+// RemoveLiquidity simulates removing liquidity; token amounts are rounded down as the program pays them.
+func (s *PoolSimulator) RemoveLiquidity(params SimulateLiquidityParams) (SimulatedLiquidityChange, error) {
+	if params.LiquidityDelta == nil || params.LiquidityDelta.Sign() <= 0 {
+		return SimulatedLiquidityChange{}, errors.New("liquidity delta must be greater than 0")
+	}
+	liquidity := s.state.Liquidity.BigInt()
+	if params.LiquidityDelta.Cmp(liquidity) > 0 {
+		return SimulatedLiquidityChange{}, errors.New("liquidity delta exceeds pool liquidity")
+	}
+	amountA, amountB, err := s.amountsForModifyLiquidity(params.LiquidityDelta, RoundingDown)
+	if err != nil {
+		return SimulatedLiquidityChange{}, err
+	}
+	s.updateRewards(params.CurrentTime)
+	s.state.Liquidity = u128FromBig(new(big.Int).Sub(liquidity, params.LiquidityDelta))
+	return SimulatedLiquidityChange{
+		LiquidityDelta: params.LiquidityDelta,
+		AmountA:        amountA,
+		AmountB:        amountB,
+		PoolState:      s.State(),
+	}, nil
+}
+
+// currentTime resolves the timestamp used by the dynamic fee.
+func (s *PoolSimulator) currentTime(currentPoint, currentTime *big.Int) (*big.Int, error) {
+	if currentTime != nil {
+		return currentTime, nil
+	}
+	if ActivationType(s.state.ActivationType) == ActivationTypeTimestamp {
+		return currentPoint, nil
+	}
+	if s.state.PoolFees.DynamicFee.Initialized != 0 {
+		return nil, errors.New("current time is required for slot activated pools with dynamic fee")
+	}
+	return big.NewInt(0), nil
+}
+
+// updateReferences resets the reference price and decays the volatility reference once the filter period has passed.
+func (s *PoolSimulator) updateReferences(currentTime *big.Int) {
+	dynamicFee := &s.state.PoolFees.DynamicFee
+	if dynamicFee.Initialized == 0 {
+		return
+	}
+	elapsed := new(big.Int).Sub(currentTime, new(big.Int).SetUint64(dynamicFee.LastUpdateTimestamp))
+	if elapsed.Cmp(big.NewInt(int64(dynamicFee.FilterPeriod))) < 0 {
+		return
+	}
+	dynamicFee.SqrtPriceReference = s.state.SqrtPrice
+	dynamicFee.VolatilityReference = u128FromBig(dynamicfee.DecayedReference(
+		dynamicFee.VolatilityAccumulator.BigInt(), uint64(dynamicFee.ReductionFactor), elapsed.Uint64(), uint64(dynamicFee.DecayPeriod),
+	))
+}
+
+// updatePostSwap records the volatility of the simulated swap on the pool's dynamic fee state.
+func (s *PoolSimulator) updatePostSwap(oldSqrtPrice, currentTime *big.Int) {
+	dynamicFee := &s.state.PoolFees.DynamicFee
+	if dynamicFee.Initialized == 0 {
+		return
+	}
+	binStep := dynamicFee.BinStepU128.BigInt()
+	sqrtPrice := s.state.SqrtPrice.BigInt()
+	dynamicFee.VolatilityAccumulator = u128FromBig(dynamicfee.Accumulator(
+		binStep, sqrtPrice, dynamicFee.SqrtPriceReference.BigInt(), dynamicFee.VolatilityReference.BigInt(), uint64(dynamicFee.MaxVolatilityAccumulator),
+	))
+	if dynamicfee.DeltaBinID(binStep, oldSqrtPrice, sqrtPrice).Sign() > 0 {
+		dynamicFee.LastUpdateTimestamp = currentTime.Uint64()
+	}
+}
+
+// applySwapResult moves the price and credits the fees of a swap.
+func (s *PoolSimulator) applySwapResult(result SwapResult2, feeMode FeeMode) {
+	s.state.SqrtPrice = result.NextSqrtPrice
+
+	lpFee := new(big.Int).SetUint64(result.TradingFee)
+	feePerLiquidity := big.NewInt(0)
+	if liquidity := s.state.Liquidity.BigInt(); liquidity.Sign() > 0 {
+		feePerLiquidity = new(big.Int).Lsh(lpFee, shared.LiquidityScale)
+		feePerLiquidity.Div(feePerLiquidity, liquidity)
+	}
+	metrics := &s.state.Metrics
+	if feeMode.FeesOnTokenA {
+		s.state.PartnerAFee += result.PartnerFee
+		s.state.ProtocolAFee += result.ProtocolFee
+		s.state.FeeAPerLiquidity = u256ToLE(new(big.Int).Add(u256FromLE(s.state.FeeAPerLiquidity), feePerLiquidity))
+		metrics.TotalLpAFee = u128FromBig(new(big.Int).Add(metrics.TotalLpAFee.BigInt(), lpFee))
+		metrics.TotalProtocolAFee += result.ProtocolFee
+		metrics.TotalPartnerAFee += result.PartnerFee
+	} else {
+		s.state.PartnerBFee += result.PartnerFee
+		s.state.ProtocolBFee += result.ProtocolFee
+		s.state.FeeBPerLiquidity = u256ToLE(new(big.Int).Add(u256FromLE(s.state.FeeBPerLiquidity), feePerLiquidity))
+		metrics.TotalLpBFee = u128FromBig(new(big.Int).Add(metrics.TotalLpBFee.BigInt(), lpFee))
+		metrics.TotalProtocolBFee += result.ProtocolFee
+		metrics.TotalPartnerBFee += result.PartnerFee
+	}
+}
+
+// updateRewards accrues the reward per token of every initialized reward up to currentTime.
+func (s *PoolSimulator) updateRewards(currentTime *big.Int) {
+	if currentTime == nil {
+		return
+	}
+	liquidity := s.state.Liquidity.BigInt()
+	for i := range s.state.RewardInfos {
+		rewardInfo := &s.state.RewardInfos[i]
+		if rewardInfo.Initialized == 0 {
+			continue
+		}
+		lastTimeRewardApplicable := minBig(currentTime, new(big.Int).SetUint64(rewardInfo.RewardDurationEnd))
+		timePeriod := new(big.Int).Sub(lastTimeRewardApplicable, new(big.Int).SetUint64(rewardInfo.LastUpdateTime))
+		if timePeriod.Sign() <= 0 {
+			continue
+		}
+		if liquidity.Sign() == 0 {
+			rewardInfo.CumulativeSecondsWithEmptyLiquidityReward += timePeriod.Uint64()
+		} else {
+			rewardPerToken := new(big.Int).Mul(timePeriod, rewardInfo.RewardRate.BigInt())
+			rewardPerToken.Lsh(rewardPerToken, 128)
+			rewardPerToken.Div(rewardPerToken, liquidity)
+			rewardInfo.RewardPerTokenStored = u256ToLE(new(big.Int).Add(u256FromLE(rewardInfo.RewardPerTokenStored), rewardPerToken))
+		}
+		rewardInfo.LastUpdateTime = lastTimeRewardApplicable.Uint64()
+	}
+}
+
+// amountsForModifyLiquidity returns the token amounts backing liquidityDelta at the simulated price.
+func (s *PoolSimulator) amountsForModifyLiquidity(liquidityDelta *big.Int, rounding Rounding) (*big.Int, *big.Int, error) {
+	sqrtPrice := s.state.SqrtPrice.BigInt()
+	amountA, err := math.GetAmountAFromLiquidityDelta(sqrtPrice, s.state.SqrtMaxPrice.BigInt(), liquidityDelta, rounding)
+	if err != nil {
+		return nil, nil, err
+	}
+	amountB, err := math.GetAmountBFromLiquidityDelta(s.state.SqrtMinPrice.BigInt(), sqrtPrice, liquidityDelta, rounding)
+	if err != nil {
+		return nil, nil, err
+	}
+	return amountA, amountB, nil
+}
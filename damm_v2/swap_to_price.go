@@ -0,0 +1,111 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+)
+
+// GetSwapToPriceQuote calculates the input that moves the pool price to TargetPrice, clamped to the pool price range.
+func (c *CpAmm) GetSwapToPriceQuote(params GetSwapToPriceQuoteParams) (SwapToPriceQuote, error) {
+	poolState := params.PoolState
+	if !params.TargetPrice.IsPositive() {
+		return SwapToPriceQuote{}, errors.New("target price must be greater than 0")
+	}
+	targetSqrtPrice := helpers.GetSqrtPriceFromPrice(params.TargetPrice, params.TokenADecimal, params.TokenBDecimal)
+
+	var tradeDirection TradeDirection
+	switch targetSqrtPrice.Cmp(poolState.SqrtPrice.BigInt()) {
+	case 1:
+		tradeDirection = TradeDirectionBtoA
+	case -1:
+		tradeDirection = TradeDirectionAtoB
+	default:
+		return SwapToPriceQuote{}, errors.New("pool is already at target price")
+	}
+	aToB := tradeDirection == TradeDirectionAtoB
+
+	level, err := depthLevel(poolState, params.CurrentPoint, targetSqrtPrice, tradeDirection)
+	if err != nil {
+		return SwapToPriceQuote{}, err
+	}
+	if level.AmountIn.Sign() <= 0 {
+		return SwapToPriceQuote{}, errors.New("pool price cannot move toward target price")
+	}
+
+	inputTokenInfo, outputTokenInfo := params.TokenBTokenInfo, params.TokenATokenInfo
+	if aToB {
+		inputTokenInfo, outputTokenInfo = params.TokenATokenInfo, params.TokenBTokenInfo
+	}
+	amountIn := helpers.CalculateTransferFeeIncludedAmount(level.AmountIn, inputTokenInfo).Amount
+
+	// partial fill stops at the range bound instead of failing when rounding overshoots it.
+	quote, err := math.SwapQuotePartialInput(
+		poolState,
+		params.CurrentPoint,
+		amountIn,
+		params.Slippage,
+		aToB,
+		params.HasReferral,
+		params.TokenADecimal,
+		params.TokenBDecimal,
+		inputTokenInfo,
+		outputTokenInfo,
+	)
+	if err != nil {
+		return SwapToPriceQuote{}, err
+	}
+	return SwapToPriceQuote{
+		AToB:             aToB,
+		TargetSqrtPrice:  level.SqrtPrice,
+		Clamped:          level.Clamped,
+		AmountIn:         amountIn,
+		AmountOut:        helpers.CalculateTransferFeeExcludedAmount(new(big.Int).SetUint64(quote.OutputAmount), outputTokenInfo).Amount,
+		MinimumAmountOut: quote.MinimumAmountOut,
+		PriceImpact:      quote.PriceImpact,
+		Result:           quote.SwapResult2,
+	}, nil
+}
+
+// SwapToPrice builds a swap that moves the pool price to TargetPrice, using the quoted output as MinimumAmountOut.
+func (c *CpAmm) SwapToPrice(ctx context.Context, params SwapToPriceParams) (TxBuilder, SwapToPriceQuote, error) {
+	poolState := params.PoolState
+	quote, err := c.GetSwapToPriceQuote(GetSwapToPriceQuoteParams{
+		PoolState:       poolState,
+		CurrentPoint:    params.CurrentPoint,
+		TargetPrice:     params.TargetPrice,
+		Slippage:        params.Slippage,
+		HasReferral:     params.ReferralTokenAccount != nil,
+		TokenATokenInfo: params.TokenATokenInfo,
+		TokenBTokenInfo: params.TokenBTokenInfo,
+		TokenADecimal:   params.TokenADecimal,
+		TokenBDecimal:   params.TokenBDecimal,
+	})
+	if err != nil {
+		return nil, SwapToPriceQuote{}, err
+	}
+
+	inputTokenMint, outputTokenMint := poolState.TokenBMint, poolState.TokenAMint
+	if quote.AToB {
+		inputTokenMint, outputTokenMint = poolState.TokenAMint, poolState.TokenBMint
+	}
+	builder, err := c.Swap2(ctx, Swap2Params{
+		Payer:                params.Payer,
+		Pool:                 params.Pool,
+		InputTokenMint:       inputTokenMint,
+		OutputTokenMint:      outputTokenMint,
+		ReferralTokenAccount: params.ReferralTokenAccount,
+		Receiver:             params.Receiver,
+		PoolState:            poolState,
+		SwapMode:             SwapModePartialFill,
+		AmountIn:             quote.AmountIn,
+		MinimumAmountOut:     quote.MinimumAmountOut,
+	})
+	if err != nil {
+		return nil, SwapToPriceQuote{}, err
+	}
+	return builder, quote, nil
+}
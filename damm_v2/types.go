@@ -2,6 +2,7 @@ package dammv2
 
 import (
 	"math/big"
+	"time"
 
 	solanago "github.com/gagliardetto/solana-go"
 	"github.com/shopspring/decimal"
@@ -14,6 +15,12 @@ import (
 // TxBuilder mirrors the TS builder style using a transaction builder.
 type TxBuilder = *solanago.TransactionBuilder
 
+// ErrMathOverflow is matched by every MathOverflowError returned by checked arithmetic.
+var ErrMathOverflow = shared.ErrMathOverflow
+
+// MathOverflowError names the operation that overflowed.
+type MathOverflowError = shared.MathOverflowError
+
 // Enums.
 type Rounding = shared.Rounding
 
@@ -82,6 +89,45 @@ const (
 	SwapModeExactOut    = shared.SwapModeExactOut
 )
 
+type LiquidityChangeType uint8
+
+const (
+	LiquidityChangeTypeAdd    LiquidityChangeType = 0
+	LiquidityChangeTypeRemove LiquidityChangeType = 1
+)
+
+type LimitOrderSide uint8
+
+const (
+	// LimitOrderSideBuy spends token B to buy token A at or below the limit price.
+	LimitOrderSideBuy LimitOrderSide = 0
+	// LimitOrderSideSell sells token A for token B at or above the limit price.
+	LimitOrderSideSell LimitOrderSide = 1
+)
+
+type LimitOrderStatus uint8
+
+const (
+	LimitOrderStatusOpen LimitOrderStatus = iota
+	LimitOrderStatusPartiallyFilled
+	LimitOrderStatusFilled
+	LimitOrderStatusExpired
+	LimitOrderStatusCancelled
+)
+
+type PriceRangePreset uint8
+
+const (
+	// PriceRangeCustom uses MinPrice and MaxPrice as given.
+	PriceRangeCustom PriceRangePreset = iota
+	// PriceRangeFull spans every price the program supports.
+	PriceRangeFull
+	// PriceRangeHalf spans 50% below to 50% above the initial price.
+	PriceRangeHalf
+	// PriceRangeStable spans 2% below to 2% above the initial price.
+	PriceRangeStable
+)
+
 // Fee mode helpers.
 type FeeMode = shared.FeeMode
 
@@ -686,3 +732,512 @@ type FeeOnAmountResult = shared.FeeOnAmountResult
 type SplitFees = shared.SplitFees
 
 type PositionNftAccount = helpers.PositionNftAccount
+
+type GetZapOutQuoteParams struct {
+	PoolState       *PoolState
+	PositionState   *PositionState
+	OutputTokenMint solanago.PublicKey
+	LiquidityDelta  *big.Int
+	Slippage        uint16
+	CurrentPoint    *big.Int
+	Vestings        []*VestingWithAccount
+	TokenATokenInfo *TokenInfo
+	TokenBTokenInfo *TokenInfo
+	TokenADecimal   uint8
+	TokenBDecimal   uint8
+	HasReferral     bool
+}
+
+// ZapOutQuote describes the outcome of removing liquidity and swapping into a single token.
+type ZapOutQuote struct {
+	LiquidityDelta    *big.Int
+	WithdrawAmountA   *big.Int
+	WithdrawAmountB   *big.Int
+	SwapInAmount      *big.Int
+	SwapOutAmount     *big.Int
+	MinSwapOutAmount  *big.Int
+	TotalOutAmount    *big.Int
+	MinTotalOutAmount *big.Int
+	TotalFee          *big.Int
+	PriceImpact       decimal.Decimal
+}
+
+type ZapOutParams struct {
+	Owner                solanago.PublicKey
+	Pool                 solanago.PublicKey
+	PoolState            *PoolState
+	Position             solanago.PublicKey
+	PositionNftAccount   solanago.PublicKey
+	PositionState        *PositionState
+	OutputTokenMint      solanago.PublicKey
+	LiquidityDelta       *big.Int
+	Slippage             uint16
+	CurrentPoint         *big.Int
+	Vestings             []*VestingWithAccount
+	TokenATokenInfo      *TokenInfo
+	TokenBTokenInfo      *TokenInfo
+	TokenADecimal        uint8
+	TokenBDecimal        uint8
+	ReferralTokenAccount *solanago.PublicKey
+}
+
+// CompoundPolicy decides whether compounding position fees is worth the transaction cost.
+// MinFeeValue is in raw units of QuoteMint, which must be one of the pool tokens; an empty QuoteMint means token B.
+type CompoundPolicy struct {
+	QuoteMint   solanago.PublicKey
+	MinFeeValue *big.Int
+}
+
+type GetCompoundQuoteParams struct {
+	PoolState       *PoolState
+	PositionState   *PositionState
+	CurrentPoint    *big.Int
+	Slippage        uint16
+	TokenATokenInfo *TokenInfo
+	TokenBTokenInfo *TokenInfo
+	TokenADecimal   uint8
+	TokenBDecimal   uint8
+	Policy          *CompoundPolicy
+}
+
+// CompoundQuote describes how claimed fees are swapped and re-deposited into the position.
+type CompoundQuote struct {
+	FeeAmountA *big.Int
+	FeeAmountB *big.Int
+	// FeeValue is the claimable fee in raw units of QuoteMint, the policy quote mint or token B.
+	FeeValue         *big.Int
+	QuoteMint        solanago.PublicKey
+	ShouldCompound   bool
+	SwapAToB         bool
+	SwapInAmount     *big.Int
+	SwapOutAmount    *big.Int
+	MinSwapOutAmount *big.Int
+	PriceImpact      decimal.Decimal
+	LiquidityDelta   *big.Int
+	MaxAmountTokenA  *big.Int
+	MaxAmountTokenB  *big.Int
+}
+
+type CompoundPositionFeeParams struct {
+	Owner              solanago.PublicKey
+	Pool               solanago.PublicKey
+	PoolState          *PoolState
+	Position           solanago.PublicKey
+	PositionNftAccount solanago.PublicKey
+	PositionState      *PositionState
+	CurrentPoint       *big.Int
+	Slippage           uint16
+	TokenATokenInfo    *TokenInfo
+	TokenBTokenInfo    *TokenInfo
+	TokenADecimal      uint8
+	TokenBDecimal      uint8
+	Policy             *CompoundPolicy
+	FeePayer           *solanago.PublicKey
+}
+
+type HarvestAllParams struct {
+	User               solanago.PublicKey
+	FeePayer           *solanago.PublicKey
+	CurrentTime        *big.Int
+	MinFeeAmount       *big.Int
+	MinRewardAmount    *big.Int
+	MaxTransactionSize int
+}
+
+// HarvestPositionSummary lists what a harvest transaction claims for one position.
+type HarvestPositionSummary struct {
+	Pool               solanago.PublicKey
+	Position           solanago.PublicKey
+	PositionNftAccount solanago.PublicKey
+	ClaimFee           bool
+	FeeAmountA         *big.Int
+	FeeAmountB         *big.Int
+	RewardIndexes      []uint8
+	RewardAmounts      []*big.Int
+}
+
+// HarvestTransaction is one size-limited transaction produced by HarvestAll.
+type HarvestTransaction struct {
+	Builder   TxBuilder
+	Positions []HarvestPositionSummary
+}
+
+// PositionEntry is the cost basis of a position used for impermanent loss.
+type PositionEntry struct {
+	AmountA   *big.Int
+	AmountB   *big.Int
+	SqrtPrice *big.Int
+}
+
+type GetPositionReportParams struct {
+	PoolState       *PoolState
+	PositionState   *PositionState
+	QuoteMint       solanago.PublicKey
+	CurrentTime     *big.Int
+	TokenATokenInfo *TokenInfo
+	TokenBTokenInfo *TokenInfo
+	TokenADecimal   uint8
+	TokenBDecimal   uint8
+	Entry           *PositionEntry
+}
+
+type PositionRewardReport struct {
+	Index         uint8
+	Mint          solanago.PublicKey
+	PendingAmount *big.Int
+	ClaimedAmount *big.Int
+}
+
+// PositionReport values a position; all values are raw amounts of QuoteMint.
+type PositionReport struct {
+	QuoteMint                solanago.PublicKey
+	Price                    decimal.Decimal
+	TotalLiquidity           *big.Int
+	UnlockedLiquidity        *big.Int
+	VestingLiquidity         *big.Int
+	PermanentLockedLiquidity *big.Int
+	AmountA                  *big.Int
+	AmountB                  *big.Int
+	UnclaimedFeeA            *big.Int
+	UnclaimedFeeB            *big.Int
+	RealizedFeeA             *big.Int
+	RealizedFeeB             *big.Int
+	Rewards                  []PositionRewardReport
+	LiquidityValue           *big.Int
+	UnclaimedFeeValue        *big.Int
+	RealizedFeeValue         *big.Int
+	TotalValue               *big.Int
+	// The fields below are only set when an entry is supplied.
+	HoldValue          *big.Int
+	ImpermanentLoss    *big.Int
+	ImpermanentLossPct decimal.Decimal
+	PnL                *big.Int
+}
+
+type GetDepthLadderParams struct {
+	PoolState     *PoolState
+	CurrentPoint  *big.Int
+	PriceLevels   []decimal.Decimal
+	BandsBps      []uint16
+	TokenADecimal uint8
+	TokenBDecimal uint8
+}
+
+// DepthLevel is the cumulative size of a trade moving the pool price from the current price to a level.
+type DepthLevel struct {
+	Price        decimal.Decimal
+	SqrtPrice    *big.Int
+	BandBps      uint16
+	Clamped      bool
+	AmountIn     *big.Int
+	AmountOut    *big.Int
+	BaseAmount   *big.Int
+	QuoteAmount  *big.Int
+	FeeAmount    *big.Int
+	FeeNumerator *big.Int
+}
+
+// DepthLadder lists bid levels (token A sold, price down) and ask levels (token A bought, price up), nearest first.
+type DepthLadder struct {
+	Price     decimal.Decimal
+	SqrtPrice *big.Int
+	Bids      []DepthLevel
+	Asks      []DepthLevel
+}
+
+type SimulateSwapParams struct {
+	Amount       *big.Int
+	SwapMode     SwapMode
+	AToB         bool
+	HasReferral  bool
+	CurrentPoint *big.Int
+	CurrentTime  *big.Int
+}
+
+// SimulatedSwap is the result of a simulated swap and the pool state after it.
+type SimulatedSwap struct {
+	Result    SwapResult2
+	PoolState *PoolState
+}
+
+type SimulateLiquidityParams struct {
+	LiquidityDelta *big.Int
+	CurrentTime    *big.Int
+}
+
+// SimulatedLiquidityChange is the result of a simulated liquidity change and the pool state after it.
+type SimulatedLiquidityChange struct {
+	LiquidityDelta *big.Int
+	AmountA        *big.Int
+	AmountB        *big.Int
+	PoolState      *PoolState
+}
+
+// ProjectBaseFeeParams selects the base fee to project, either from PoolState or from BaseFee with ActivationPoint and InitSqrtPrice.
+type ProjectBaseFeeParams struct {
+	PoolState       *PoolState
+	BaseFee         *BaseFee
+	ActivationPoint *big.Int
+	InitSqrtPrice   *big.Int
+	FromPoint       *big.Int
+	ToPoint         *big.Int
+	Step            *big.Int
+	SqrtPricePath   []*big.Int
+	Amount          *big.Int
+}
+
+type BaseFeeProjectionPoint struct {
+	Point        *big.Int
+	SqrtPrice    *big.Int
+	FeeNumerator *big.Int
+	FeeBps       decimal.Decimal
+}
+
+// BaseFeeProjection is the base fee over activation points, and over SqrtPricePath at FromPoint.
+type BaseFeeProjection struct {
+	BaseFeeMode     BaseFeeMode
+	MinFeeNumerator *big.Int
+	MaxFeeNumerator *big.Int
+	StaticPoint     *big.Int
+	Points          []BaseFeeProjectionPoint
+	PricePath       []BaseFeeProjectionPoint
+}
+
+// GetRewardAnalyticsParams prices tokens per whole token in a common currency; Tvl overrides the TVL derived from pool liquidity.
+// RewardDecimals must hold the decimals of every initialized reward mint.
+type GetRewardAnalyticsParams struct {
+	PoolState           *PoolState
+	CurrentTime         *big.Int
+	TokenAPrice         decimal.Decimal
+	TokenBPrice         decimal.Decimal
+	TokenADecimal       uint8
+	TokenBDecimal       uint8
+	RewardPrices        map[solanago.PublicKey]decimal.Decimal
+	RewardDecimals      map[solanago.PublicKey]uint8
+	Tvl                 *decimal.Decimal
+	EndingSoonThreshold *big.Int
+}
+
+type RewardAnalytics struct {
+	Index             uint8
+	Mint              solanago.PublicKey
+	Vault             solanago.PublicKey
+	Funder            solanago.PublicKey
+	RewardRate        *big.Int
+	RewardPerSecond   decimal.Decimal
+	RewardDuration    *big.Int
+	RewardDurationEnd *big.Int
+	RemainingDuration *big.Int
+	RemainingBalance  *big.Int
+	IneligibleReward  *big.Int
+	Apr               decimal.Decimal
+	Active            bool
+	EndingSoon        bool
+}
+
+type PoolRewardAnalytics struct {
+	Tvl     decimal.Decimal
+	Rewards []RewardAnalytics
+}
+
+type GetPositionRewardScheduleParams struct {
+	PoolState       *PoolState
+	PositionState   *PositionState
+	CurrentTime     *big.Int
+	Period          *big.Int
+	NumberOfPeriods int
+}
+
+// PositionRewardSchedulePoint holds the pending reward of every reward index at Time.
+type PositionRewardSchedulePoint struct {
+	Time           *big.Int
+	PendingRewards []*big.Int
+}
+
+// DesignVestingParams describes a lock in human terms; Liquidity takes precedence over PercentageBps of UnlockedLiquidity.
+// VestDuration must be a whole number of Cadence periods.
+type DesignVestingParams struct {
+	UnlockedLiquidity *big.Int
+	Liquidity         *big.Int
+	PercentageBps     uint16
+	ActivationType    ActivationType
+	CurrentPoint      *big.Int
+	CurrentTime       time.Time
+	CliffTime         time.Time
+	CliffUnlockBps    uint16
+	VestDuration      time.Duration
+	Cadence           time.Duration
+}
+
+type VestingUnlock struct {
+	Point              *big.Int
+	Liquidity          *big.Int
+	CumulativeUnlocked *big.Int
+}
+
+// VestingDesign holds the exact lock parameters of a designed vesting and its unlock timeline.
+type VestingDesign struct {
+	CliffPoint           *big.Int
+	PeriodFrequency      *big.Int
+	CliffUnlockLiquidity *big.Int
+	LiquidityPerPeriod   *big.Int
+	NumberOfPeriod       uint16
+	TotalLiquidity       *big.Int
+	Remainder            *big.Int
+	Timeline             []VestingUnlock
+}
+
+// PoolIndexEntry holds the fields of a pool tracked by PoolIndex.
+type PoolIndexEntry struct {
+	Pool       solanago.PublicKey `json:"pool"`
+	TokenAMint solanago.PublicKey `json:"tokenAMint"`
+	TokenBMint solanago.PublicKey `json:"tokenBMint"`
+	Creator    solanago.PublicKey `json:"creator"`
+	PoolStatus PoolStatus         `json:"poolStatus"`
+}
+
+// GetSwapToPriceQuoteParams describes a swap that moves the pool to TargetPrice, expressed in token B per token A.
+type GetSwapToPriceQuoteParams struct {
+	PoolState       *PoolState
+	CurrentPoint    *big.Int
+	TargetPrice     decimal.Decimal
+	Slippage        uint16
+	HasReferral     bool
+	TokenATokenInfo *TokenInfo
+	TokenBTokenInfo *TokenInfo
+	TokenADecimal   uint8
+	TokenBDecimal   uint8
+}
+
+// SwapToPriceQuote holds the input needed to move the pool price to the target and the resulting swap.
+// AmountIn includes trading and transfer fees; AmountOut excludes the output transfer fee.
+type SwapToPriceQuote struct {
+	AToB             bool
+	TargetSqrtPrice  *big.Int
+	Clamped          bool
+	AmountIn         *big.Int
+	AmountOut        *big.Int
+	MinimumAmountOut *big.Int
+	PriceImpact      decimal.Decimal
+	Result           SwapResult2
+}
+
+type SwapToPriceParams struct {
+	Payer                solanago.PublicKey
+	Pool                 solanago.PublicKey
+	PoolState            *PoolState
+	CurrentPoint         *big.Int
+	TargetPrice          decimal.Decimal
+	Slippage             uint16
+	TokenATokenInfo      *TokenInfo
+	TokenBTokenInfo      *TokenInfo
+	TokenADecimal        uint8
+	TokenBDecimal        uint8
+	ReferralTokenAccount *solanago.PublicKey
+	Receiver             *solanago.PublicKey
+}
+
+type TransferPositionParams struct {
+	Owner              solanago.PublicKey
+	Payer              solanago.PublicKey
+	Recipient          solanago.PublicKey
+	PositionNftMint    solanago.PublicKey
+	PositionNftAccount solanago.PublicKey
+	// CloseSourceAccount closes the emptied sender NFT account and returns its rent to Owner.
+	CloseSourceAccount bool
+}
+
+// PositionOwner holds the current holder of a position NFT.
+type PositionOwner struct {
+	Owner              solanago.PublicKey
+	PositionNftMint    solanago.PublicKey
+	PositionNftAccount solanago.PublicKey
+}
+
+// LimitOrder is an order executed by LimitOrderKeeper. Amount is the input amount, in token B for buys and token A for sells.
+// LimitPrice is expressed in token B per token A; ReceivedAmount is the quoted output of the executed swaps.
+type LimitOrder struct {
+	ID               string               `json:"id"`
+	Owner            solanago.PublicKey   `json:"owner"`
+	Pool             solanago.PublicKey   `json:"pool"`
+	Side             LimitOrderSide       `json:"side"`
+	Amount           *big.Int             `json:"amount"`
+	LimitPrice       decimal.Decimal      `json:"limitPrice"`
+	Expiry           time.Time            `json:"expiry"`
+	AllowPartialFill bool                 `json:"allowPartialFill"`
+	Status           LimitOrderStatus     `json:"status"`
+	FilledAmount     *big.Int             `json:"filledAmount"`
+	ReceivedAmount   *big.Int             `json:"receivedAmount"`
+	Signatures       []solanago.Signature `json:"signatures"`
+	LastError        string               `json:"lastError,omitempty"`
+}
+
+type LimitOrderKeeperConfig struct {
+	// Store persists orders; an in-memory store is used when nil.
+	Store LimitOrderStore
+	// Signer signs swap transactions for the order owners.
+	Signer func(key solanago.PublicKey) *solanago.PrivateKey
+	// OnStatus is called after every order update.
+	OnStatus func(order LimitOrder)
+	// ExpiryInterval is how often Run expires orders of pools without updates; defaults to 10 seconds.
+	ExpiryInterval time.Duration
+}
+
+// LaunchPoolFee describes the pool fee in bps; only the fields of BaseFeeMode are used.
+type LaunchPoolFee struct {
+	BaseFeeMode                 BaseFeeMode
+	StartingFeeBps              uint16
+	EndingFeeBps                uint16
+	NumberOfPeriod              uint16
+	TotalDuration               uint32
+	SqrtPriceStepBps            uint16
+	SchedulerExpirationDuration uint32
+	MaxLimiterDuration          uint32
+	MaxFeeBps                   uint16
+	ReferenceAmount             *big.Int
+	DynamicFee                  bool
+	// PoolVersion is the pool version the fee is validated against.
+	PoolVersion PoolVersion
+}
+
+// LaunchPoolParams describes a customizable pool in human units: prices in token B per token A and UI token amounts.
+type LaunchPoolParams struct {
+	Payer           solanago.PublicKey
+	Creator         solanago.PublicKey
+	PositionNft     solanago.PublicKey
+	TokenAMint      solanago.PublicKey
+	TokenBMint      solanago.PublicKey
+	InitialPrice    decimal.Decimal
+	PriceRange      PriceRangePreset
+	MinPrice        decimal.Decimal
+	MaxPrice        decimal.Decimal
+	TokenAAmount    decimal.Decimal
+	TokenBAmount    decimal.Decimal
+	Fee             LaunchPoolFee
+	CollectFeeMode  CollectFeeMode
+	ActivationType  ActivationType
+	ActivationTime  time.Time
+	HasAlphaVault   bool
+	IsLockLiquidity bool
+}
+
+// LaunchPoolPreview holds the derived raw values of a launch and the tokens it deposits.
+type LaunchPoolPreview struct {
+	Pool               solanago.PublicKey
+	InitSqrtPrice      *big.Int
+	SqrtMinPrice       *big.Int
+	SqrtMaxPrice       *big.Int
+	MinPrice           decimal.Decimal
+	MaxPrice           decimal.Decimal
+	LiquidityDelta     *big.Int
+	TokenAAmount       *big.Int
+	TokenBAmount       *big.Int
+	TokenAUiAmount     decimal.Decimal
+	TokenBUiAmount     decimal.Decimal
+	UnusedTokenAAmount *big.Int
+	UnusedTokenBAmount *big.Int
+	// TokenAValueShare is the share of the deposit value held in token A.
+	TokenAValueShare decimal.Decimal
+	ActivationPoint  *big.Int
+	PoolFees         PoolFeesParams
+}
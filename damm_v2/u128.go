@@ -14,3 +14,21 @@ func u128FromBig(v *big.Int) binary.Uint128 {
 	hi := new(big.Int).Rsh(new(big.Int).Set(v), 64).Uint64()
 	return binary.Uint128{Lo: lo, Hi: hi}
 }
+
+func u256FromLE(b [32]uint8) *big.Int {
+	out := new(big.Int)
+	for i := len(b) - 1; i >= 0; i-- {
+		out.Lsh(out, 8)
+		out.Or(out, big.NewInt(int64(b[i])))
+	}
+	return out
+}
+
+func u256ToLE(v *big.Int) [32]uint8 {
+	var out [32]uint8
+	b := v.Bytes()
+	for i := 0; i < len(b) && i < len(out); i++ {
+		out[i] = b[len(b)-1-i]
+	}
+	return out
+}
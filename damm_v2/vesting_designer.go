@@ -0,0 +1,196 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+	"github.com/krazyTry/meteora-go/internal/checked"
+	"github.com/krazyTry/meteora-go/internal/clock"
+)
+
+// DesignVesting converts a human vesting description into LockPosition parameters.
+// The rounding remainder of the per period liquidity is unlocked at the cliff, so the total locked is exact.
+func (c *CpAmm) DesignVesting(params DesignVestingParams) (VestingDesign, error) {
+	if params.CurrentPoint == nil {
+		return VestingDesign{}, errors.New("current point is required")
+	}
+	totalLiquidity := params.Liquidity
+	if totalLiquidity == nil {
+		if params.UnlockedLiquidity == nil || params.PercentageBps == 0 || params.PercentageBps > shared.BasisPointMax {
+			return VestingDesign{}, errors.New("liquidity or a valid percentage of unlocked liquidity is required")
+		}
+		totalLiquidity = new(big.Int).Mul(params.UnlockedLiquidity, big.NewInt(int64(params.PercentageBps)))
+		totalLiquidity.Div(totalLiquidity, big.NewInt(shared.BasisPointMax))
+	}
+	if totalLiquidity.Sign() <= 0 {
+		return VestingDesign{}, errors.New("liquidity must be greater than 0")
+	}
+	if params.UnlockedLiquidity != nil && totalLiquidity.Cmp(params.UnlockedLiquidity) > 0 {
+		return VestingDesign{}, errors.New("liquidity exceeds unlocked liquidity")
+	}
+	if params.CliffUnlockBps > shared.BasisPointMax {
+		return VestingDesign{}, errors.New("invalid cliff unlock bps")
+	}
+
+	cliffPoint := new(big.Int).Set(params.CurrentPoint)
+	if !params.CliffTime.IsZero() {
+		if params.CurrentTime.IsZero() {
+			return VestingDesign{}, errors.New("current time is required with cliff time")
+		}
+		cliffPoint.Add(cliffPoint, durationToPoints(params.CliffTime.Sub(params.CurrentTime), params.ActivationType))
+	}
+	if cliffPoint.Cmp(params.CurrentPoint) < 0 {
+		return VestingDesign{}, errors.New("cliff point must not be in the past")
+	}
+
+	cliffUnlockLiquidity := new(big.Int).Mul(totalLiquidity, big.NewInt(int64(params.CliffUnlockBps)))
+	cliffUnlockLiquidity.Div(cliffUnlockLiquidity, big.NewInt(shared.BasisPointMax))
+	vestedLiquidity := new(big.Int).Sub(totalLiquidity, cliffUnlockLiquidity)
+
+	design := VestingDesign{
+		CliffPoint:           cliffPoint,
+		PeriodFrequency:      big.NewInt(0),
+		CliffUnlockLiquidity: cliffUnlockLiquidity,
+		LiquidityPerPeriod:   big.NewInt(0),
+		TotalLiquidity:       totalLiquidity,
+		Remainder:            big.NewInt(0),
+	}
+	if vestedLiquidity.Sign() > 0 {
+		if params.VestDuration <= 0 {
+			design.CliffUnlockLiquidity = new(big.Int).Set(totalLiquidity)
+		} else {
+			periodFrequency := durationToPoints(params.Cadence, params.ActivationType)
+			if periodFrequency.Sign() <= 0 {
+				return VestingDesign{}, errors.New("cadence must be at least one point")
+			}
+			numberOfPeriod, rest := new(big.Int).QuoRem(durationToPoints(params.VestDuration, params.ActivationType), periodFrequency, new(big.Int))
+			if numberOfPeriod.Sign() <= 0 {
+				return VestingDesign{}, errors.New("vest duration must be at least one cadence")
+			}
+			if rest.Sign() != 0 {
+				return VestingDesign{}, errors.New("vest duration must be a whole number of cadences")
+			}
+			if numberOfPeriod.Cmp(big.NewInt(shared.U16Max)) > 0 {
+				return VestingDesign{}, errors.New("too many vesting periods")
+			}
+			liquidityPerPeriod := new(big.Int).Div(vestedLiquidity, numberOfPeriod)
+			if liquidityPerPeriod.Sign() <= 0 {
+				return VestingDesign{}, errors.New("liquidity per period is zero")
+			}
+			remainder := new(big.Int).Sub(vestedLiquidity, new(big.Int).Mul(liquidityPerPeriod, numberOfPeriod))
+			design.PeriodFrequency = periodFrequency
+			design.LiquidityPerPeriod = liquidityPerPeriod
+			design.NumberOfPeriod = uint16(numberOfPeriod.Uint64())
+			design.Remainder = remainder
+			design.CliffUnlockLiquidity = new(big.Int).Add(cliffUnlockLiquidity, remainder)
+		}
+	}
+	if err := checked.AllU128("vesting liquidity", design.CliffUnlockLiquidity, design.LiquidityPerPeriod); err != nil {
+		return VestingDesign{}, err
+	}
+	cliffPointU64, err := toU64(cliffPoint, "vesting cliff point")
+	if err != nil {
+		return VestingDesign{}, err
+	}
+	periodFrequencyU64, err := toU64(design.PeriodFrequency, "vesting period frequency")
+	if err != nil {
+		return VestingDesign{}, err
+	}
+
+	design.Timeline = vestingTimeline([]InnerVesting{{
+		CliffPoint:           cliffPointU64,
+		PeriodFrequency:      periodFrequencyU64,
+		CliffUnlockLiquidity: u128FromBig(design.CliffUnlockLiquidity),
+		LiquidityPerPeriod:   u128FromBig(design.LiquidityPerPeriod),
+		NumberOfPeriod:       design.NumberOfPeriod,
+	}})
+	return design, nil
+}
+
+// ToLockPositionParams copies the designed vesting parameters into params.
+func (d VestingDesign) ToLockPositionParams(params LockPositionParams) LockPositionParams {
+	params.CliffPoint = d.CliffPoint
+	params.PeriodFrequency = d.PeriodFrequency
+	params.CliffUnlockLiquidity = d.CliffUnlockLiquidity
+	params.LiquidityPerPeriod = d.LiquidityPerPeriod
+	params.NumberOfPeriod = d.NumberOfPeriod
+	return params
+}
+
+// GetPositionVestingTimeline combines the inner vesting of a position and all its vesting accounts into one unlock timeline.
+func (c *CpAmm) GetPositionVestingTimeline(ctx context.Context, position solanago.PublicKey, positionState *PositionState) ([]VestingUnlock, error) {
+	vestings, err := c.GetAllVestingsByPosition(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+	return CombineVestingTimeline(positionState, vestings), nil
+}
+
+// CombineVestingTimeline merges the unlock schedules of the inner vesting of positionState and of vestings.
+func CombineVestingTimeline(positionState *PositionState, vestings []*VestingWithAccount) []VestingUnlock {
+	schedules := make([]InnerVesting, 0, len(vestings)+1)
+	if positionState != nil && innerVestingLiquidity(positionState.InnerVesting).Sign() > 0 {
+		schedules = append(schedules, positionState.InnerVesting)
+	}
+	for _, v := range vestings {
+		schedules = append(schedules, v.VestingState.InnerVesting)
+	}
+	return vestingTimeline(schedules)
+}
+
+// vestingTimeline lists the unlock events of the schedules ordered by point.
+func vestingTimeline(schedules []InnerVesting) []VestingUnlock {
+	unlocks := make(map[uint64]*big.Int)
+	add := func(point uint64, liquidity *big.Int) {
+		if liquidity.Sign() == 0 {
+			return
+		}
+		if _, ok := unlocks[point]; !ok {
+			unlocks[point] = big.NewInt(0)
+		}
+		unlocks[point].Add(unlocks[point], liquidity)
+	}
+	for _, schedule := range schedules {
+		add(schedule.CliffPoint, schedule.CliffUnlockLiquidity.BigInt())
+		for period := uint64(1); period <= uint64(schedule.NumberOfPeriod); period++ {
+			add(schedule.CliffPoint+period*schedule.PeriodFrequency, schedule.LiquidityPerPeriod.BigInt())
+		}
+	}
+
+	points := make([]uint64, 0, len(unlocks))
+	for point := range unlocks {
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+	timeline := make([]VestingUnlock, 0, len(points))
+	cumulative := big.NewInt(0)
+	for _, point := range points {
+		cumulative = new(big.Int).Add(cumulative, unlocks[point])
+		timeline = append(timeline, VestingUnlock{
+			Point:              new(big.Int).SetUint64(point),
+			Liquidity:          unlocks[point],
+			CumulativeUnlocked: cumulative,
+		})
+	}
+	return timeline
+}
+
+func innerVestingLiquidity(v InnerVesting) *big.Int {
+	total := new(big.Int).Mul(v.LiquidityPerPeriod.BigInt(), big.NewInt(int64(v.NumberOfPeriod)))
+	return total.Add(total, v.CliffUnlockLiquidity.BigInt())
+}
+
+// durationToPoints converts d into seconds or slots depending on activationType.
+func durationToPoints(d time.Duration, activationType ActivationType) *big.Int {
+	if activationType == ActivationTypeSlot {
+		return big.NewInt(int64(d / clock.SlotDuration))
+	}
+	return big.NewInt(int64(d / time.Second))
+}
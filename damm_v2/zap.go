@@ -0,0 +1,301 @@
+package dammv2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/krazyTry/meteora-go/damm_v2/math"
+	"github.com/krazyTry/meteora-go/damm_v2/math/pool_fees"
+	"github.com/krazyTry/meteora-go/damm_v2/shared"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	"github.com/krazyTry/meteora-go/internal/checked"
+)
+
+// GetWithdrawableLiquidity returns the unlocked liquidity plus the vested liquidity released at currentPoint.
+func (c *CpAmm) GetWithdrawableLiquidity(position *PositionState, vestings []*VestingWithAccount, currentPoint *big.Int) *big.Int {
+	out := new(big.Int).Set(position.UnlockedLiquidity.BigInt())
+	for _, v := range vestings {
+		available := helpers.GetAvailableVestingLiquidity(v.VestingState, currentPoint)
+		if available.Sign() > 0 {
+			out.Add(out, available)
+		}
+	}
+	return out
+}
+
+// GetZapOutQuote calculates the single token output of removing liquidity and swapping the other side.
+func (c *CpAmm) GetZapOutQuote(params GetZapOutQuoteParams) (ZapOutQuote, error) {
+	poolState := params.PoolState
+	isOutputA := poolState.TokenAMint.Equals(params.OutputTokenMint)
+	if !isOutputA && !poolState.TokenBMint.Equals(params.OutputTokenMint) {
+		return ZapOutQuote{}, errors.New("output token mint does not belong to pool")
+	}
+
+	withdrawable := c.GetWithdrawableLiquidity(params.PositionState, params.Vestings, params.CurrentPoint)
+	liquidityDelta := params.LiquidityDelta
+	if liquidityDelta == nil {
+		liquidityDelta = withdrawable
+	}
+	if liquidityDelta.Sign() <= 0 {
+		return ZapOutQuote{}, errors.New("liquidity delta must be greater than 0")
+	}
+	if liquidityDelta.Cmp(withdrawable) > 0 {
+		return ZapOutQuote{}, errors.New("liquidity delta exceeds withdrawable liquidity")
+	}
+
+	withdrawQuote, err := c.GetWithdrawQuote(GetWithdrawQuoteParams{
+		LiquidityDelta:  liquidityDelta,
+		MinSqrtPrice:    poolState.SqrtMinPrice.BigInt(),
+		MaxSqrtPrice:    poolState.SqrtMaxPrice.BigInt(),
+		SqrtPrice:       poolState.SqrtPrice.BigInt(),
+		TokenATokenInfo: params.TokenATokenInfo,
+		TokenBTokenInfo: params.TokenBTokenInfo,
+	})
+	if err != nil {
+		return ZapOutQuote{}, err
+	}
+
+	keepAmount, swapInAmount := withdrawQuote.OutAmountB, withdrawQuote.OutAmountA
+	inputTokenInfo, outputTokenInfo := params.TokenATokenInfo, params.TokenBTokenInfo
+	aToB := true
+	if isOutputA {
+		keepAmount, swapInAmount = withdrawQuote.OutAmountA, withdrawQuote.OutAmountB
+		inputTokenInfo, outputTokenInfo = params.TokenBTokenInfo, params.TokenATokenInfo
+		aToB = false
+	}
+
+	quote := ZapOutQuote{
+		LiquidityDelta:   liquidityDelta,
+		WithdrawAmountA:  withdrawQuote.OutAmountA,
+		WithdrawAmountB:  withdrawQuote.OutAmountB,
+		SwapInAmount:     swapInAmount,
+		SwapOutAmount:    big.NewInt(0),
+		MinSwapOutAmount: big.NewInt(0),
+		TotalFee:         big.NewInt(0),
+		PriceImpact:      decimal.Zero,
+	}
+	if swapInAmount.Sign() > 0 {
+		// the swap executes against the pool after the liquidity has been removed.
+		postRemovePool := *poolState
+		postRemovePool.Liquidity = u128FromBig(new(big.Int).Sub(poolState.Liquidity.BigInt(), liquidityDelta))
+
+		swapQuote, err := math.SwapQuoteExactInput(
+			&postRemovePool,
+			params.CurrentPoint,
+			swapInAmount,
+			params.Slippage,
+			aToB,
+			params.HasReferral,
+			params.TokenADecimal,
+			params.TokenBDecimal,
+			inputTokenInfo,
+			outputTokenInfo,
+		)
+		if err != nil {
+			return ZapOutQuote{}, err
+		}
+		swapOutAmount := new(big.Int).SetUint64(swapQuote.OutputAmount)
+		if outputTokenInfo != nil {
+			swapOutAmount = helpers.CalculateTransferFeeExcludedAmount(swapOutAmount, outputTokenInfo).Amount
+		}
+		totalFee := new(big.Int).Add(new(big.Int).SetUint64(swapQuote.TradingFee), new(big.Int).SetUint64(swapQuote.ProtocolFee))
+		totalFee.Add(totalFee, new(big.Int).SetUint64(swapQuote.PartnerFee))
+		totalFee.Add(totalFee, new(big.Int).SetUint64(swapQuote.ReferralFee))
+
+		quote.SwapOutAmount = swapOutAmount
+		quote.MinSwapOutAmount = swapQuote.MinimumAmountOut
+		quote.TotalFee = totalFee
+		quote.PriceImpact = swapQuote.PriceImpact
+	}
+	quote.TotalOutAmount = new(big.Int).Add(keepAmount, quote.SwapOutAmount)
+	quote.MinTotalOutAmount = new(big.Int).Add(helpers.GetAmountWithSlippage(keepAmount, params.Slippage, SwapModeExactIn), quote.MinSwapOutAmount)
+	return quote, nil
+}
+
+// ZapOut builds a transaction that removes liquidity and swaps the other side into OutputTokenMint.
+func (c *CpAmm) ZapOut(ctx context.Context, params ZapOutParams) (TxBuilder, ZapOutQuote, error) {
+	poolState := params.PoolState
+	quote, err := c.GetZapOutQuote(GetZapOutQuoteParams{
+		PoolState:       poolState,
+		PositionState:   params.PositionState,
+		OutputTokenMint: params.OutputTokenMint,
+		LiquidityDelta:  params.LiquidityDelta,
+		Slippage:        params.Slippage,
+		CurrentPoint:    params.CurrentPoint,
+		Vestings:        params.Vestings,
+		TokenATokenInfo: params.TokenATokenInfo,
+		TokenBTokenInfo: params.TokenBTokenInfo,
+		TokenADecimal:   params.TokenADecimal,
+		TokenBDecimal:   params.TokenBDecimal,
+		HasReferral:     params.ReferralTokenAccount != nil,
+	})
+	if err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+
+	tokenAProgram := helpers.GetTokenProgram(poolState.TokenAFlag)
+	tokenBProgram := helpers.GetTokenProgram(poolState.TokenBFlag)
+	tokenAAccount, tokenBAccount, preIxs, err := c.prepareTokenAccounts(ctx, PrepareTokenAccountParams{
+		Payer:         params.Owner,
+		TokenAOwner:   params.Owner,
+		TokenBOwner:   params.Owner,
+		TokenAMint:    poolState.TokenAMint,
+		TokenBMint:    poolState.TokenBMint,
+		TokenAProgram: tokenAProgram,
+		TokenBProgram: tokenBProgram,
+	})
+	if err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+	if len(params.Vestings) > 0 {
+		vestingAccounts := make([]solanago.PublicKey, 0, len(params.Vestings))
+		for _, v := range params.Vestings {
+			vestingAccounts = append(vestingAccounts, v.Account)
+		}
+		refreshIx, err := c.buildRefreshVestingInstruction(RefreshVestingParams{
+			Owner:              params.Owner,
+			Position:           params.Position,
+			PositionNftAccount: params.PositionNftAccount,
+			Pool:               params.Pool,
+			VestingAccounts:    vestingAccounts,
+		})
+		if err != nil {
+			return nil, ZapOutQuote{}, err
+		}
+		preIxs = append(preIxs, refreshIx)
+	}
+
+	thresholdA := helpers.GetAmountWithSlippage(quote.WithdrawAmountA, params.Slippage, SwapModeExactIn)
+	thresholdB := helpers.GetAmountWithSlippage(quote.WithdrawAmountB, params.Slippage, SwapModeExactIn)
+	if err := checked.AllU128("zap out liquidity delta", quote.LiquidityDelta); err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+	tokenAAmountThreshold, err := toU64(thresholdA, "zap out token a threshold")
+	if err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+	tokenBAmountThreshold, err := toU64(thresholdB, "zap out token b threshold")
+	if err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+	removeIx, err := dammv2gen.NewRemoveLiquidityInstruction(
+		dammv2gen.RemoveLiquidityParameters{
+			LiquidityDelta:        u128FromBig(quote.LiquidityDelta),
+			TokenAAmountThreshold: tokenAAmountThreshold,
+			TokenBAmountThreshold: tokenBAmountThreshold,
+		},
+		c.PoolAuthority,
+		params.Pool,
+		params.Position,
+		tokenAAccount,
+		tokenBAccount,
+		poolState.TokenAVault,
+		poolState.TokenBVault,
+		poolState.TokenAMint,
+		poolState.TokenBMint,
+		params.PositionNftAccount,
+		params.Owner,
+		tokenAProgram,
+		tokenBProgram,
+		c.EventAuthority,
+		dammv2gen.ProgramID,
+	)
+	if err != nil {
+		return nil, ZapOutQuote{}, err
+	}
+
+	builder := solanago.NewTransactionBuilder()
+	for _, ix := range preIxs {
+		builder.AddInstruction(ix)
+	}
+	builder.AddInstruction(removeIx)
+
+	if quote.SwapInAmount.Sign() > 0 {
+		swapInAmount, err := toU64(quote.SwapInAmount, "zap out swap amount in")
+		if err != nil {
+			return nil, ZapOutQuote{}, err
+		}
+		minSwapOutAmount, err := toU64(quote.MinSwapOutAmount, "zap out swap minimum amount out")
+		if err != nil {
+			return nil, ZapOutQuote{}, err
+		}
+		inputTokenAccount, outputTokenAccount := tokenAAccount, tokenBAccount
+		tradeDirection := TradeDirectionAtoB
+		if poolState.TokenAMint.Equals(params.OutputTokenMint) {
+			inputTokenAccount, outputTokenAccount = tokenBAccount, tokenAAccount
+			tradeDirection = TradeDirectionBtoA
+		}
+		rateLimiterApplied, err := c.isRateLimiterApplied(ctx, poolState, tradeDirection)
+		if err != nil {
+			return nil, ZapOutQuote{}, err
+		}
+		swapIx, err := dammv2gen.NewSwap2Instruction(
+			dammv2gen.SwapParameters2{
+				Amount0:  swapInAmount,
+				Amount1:  minSwapOutAmount,
+				SwapMode: uint8(SwapModeExactIn),
+			},
+			c.PoolAuthority,
+			params.Pool,
+			inputTokenAccount,
+			outputTokenAccount,
+			poolState.TokenAVault,
+			poolState.TokenBVault,
+			poolState.TokenAMint,
+			poolState.TokenBMint,
+			params.Owner,
+			tokenAProgram,
+			tokenBProgram,
+			optionalPubkey(params.ReferralTokenAccount),
+			c.EventAuthority,
+			dammv2gen.ProgramID,
+		)
+		if err != nil {
+			return nil, ZapOutQuote{}, err
+		}
+		if rateLimiterApplied {
+			if err := appendRemainingAccounts(swapIx, []*solanago.AccountMeta{solanago.NewAccountMeta(solanago.SysVarInstructionsPubkey, false, false)}); err != nil {
+				return nil, ZapOutQuote{}, err
+			}
+		}
+		builder.AddInstruction(swapIx)
+	}
+
+	if poolState.TokenAMint.Equals(helpers.NativeMint) || poolState.TokenBMint.Equals(helpers.NativeMint) {
+		closeIx, _ := helpers.UnwrapSOLInstruction(params.Owner, params.Owner, true)
+		if closeIx != nil {
+			builder.AddInstruction(closeIx)
+		}
+	}
+	return builder, quote, nil
+}
+
+// isRateLimiterApplied reports whether a swap in tradeDirection needs the instructions sysvar.
+func (c *CpAmm) isRateLimiterApplied(ctx context.Context, poolState *PoolState, tradeDirection TradeDirection) (bool, error) {
+	data := poolState.PoolFees.BaseFee.BaseFeeInfo.Data[:]
+	if BaseFeeMode(data[8]) != BaseFeeModeRateLimiter {
+		return false, nil
+	}
+	currentPoint, err := helpers.GetCurrentPoint(ctx, c.Client, shared.ActivationType(poolState.ActivationType))
+	if err != nil {
+		return false, err
+	}
+	rateLimiterPoolFees, err := helpers.DecodePodAlignedFeeRateLimiter(data)
+	if err != nil {
+		return false, err
+	}
+	return pool_fees.IsRateLimiterApplied(
+		new(big.Int).SetUint64(rateLimiterPoolFees.ReferenceAmount),
+		rateLimiterPoolFees.MaxLimiterDuration,
+		uint16(rateLimiterPoolFees.MaxFeeBps),
+		rateLimiterPoolFees.FeeIncrementBps,
+		currentPoint,
+		big.NewInt(int64(poolState.ActivationPoint)),
+		tradeDirection,
+	), nil
+}
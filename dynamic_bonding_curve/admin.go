@@ -0,0 +1,283 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"errors"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+)
+
+// CreateClaimFeeOperator builds the admin instruction allowing an operator to claim protocol fees.
+func (s *DynamicBondingCurve) CreateClaimFeeOperator(ctx context.Context, params CreateClaimFeeOperatorParams) (solanago.Instruction, error) {
+	return dbcidl.NewCreateClaimProtocolFeeOperatorInstruction(
+		helpers.DeriveClaimFeeOperatorAddress(params.Operator),
+		params.Operator,
+		params.Admin,
+		params.Payer,
+		system.ProgramID,
+		s.EventAuthority,
+		helpers.DynamicBondingCurveProgramID,
+	)
+}
+
+// CloseClaimFeeOperator builds the admin instruction revoking an operator and returning its rent.
+func (s *DynamicBondingCurve) CloseClaimFeeOperator(ctx context.Context, params CloseClaimFeeOperatorParams) (solanago.Instruction, error) {
+	return dbcidl.NewCloseClaimProtocolFeeOperatorInstruction(
+		helpers.DeriveClaimFeeOperatorAddress(params.Operator),
+		params.RentReceiver,
+		params.Admin,
+		s.EventAuthority,
+		helpers.DynamicBondingCurveProgramID,
+	)
+}
+
+// GetClaimFeeOperator returns the claim fee operator account of operator, or nil when none exists.
+func (s *DynamicBondingCurve) GetClaimFeeOperator(ctx context.Context, operator solanago.PublicKey) (*ClaimFeeOperator, error) {
+	acc, err := s.RPC.GetAccountInfoWithOpts(ctx, helpers.DeriveClaimFeeOperatorAddress(operator), &rpc.GetAccountInfoOpts{Commitment: s.Commitment})
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if acc == nil || acc.Value == nil {
+		return nil, nil
+	}
+	return dbcidl.ParseAccount_ClaimFeeOperator(acc.Value.Data.GetBinary())
+}
+
+// GetClaimFeeOperators lists every claim fee operator account of the program.
+func (s *DynamicBondingCurve) GetClaimFeeOperators(ctx context.Context) ([]ProgramAccount[ClaimFeeOperator], error) {
+	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyClaimFeeOperator, nil)
+	accounts, err := s.RPC.GetProgramAccountsWithOpts(ctx, helpers.DynamicBondingCurveProgramID, &rpc.GetProgramAccountsOpts{Commitment: s.Commitment, Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ProgramAccount[ClaimFeeOperator], 0)
+	for _, acc := range accounts {
+		parsed, err := dbcidl.ParseAccount_ClaimFeeOperator(acc.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		out = append(out, ProgramAccount[ClaimFeeOperator]{Pubkey: acc.Pubkey, Account: parsed})
+	}
+	return out, nil
+}
+
+// ClaimProtocolFee builds the operator instruction claiming the protocol trading fees of a pool into
+// the treasury token accounts, which are created first when missing.
+func (s *DynamicBondingCurve) ClaimProtocolFee(ctx context.Context, params ClaimProtocolFeeParams) (pre []solanago.Instruction, ix solanago.Instruction, err error) {
+	poolState, err := s.GetPool(ctx, params.Pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	poolConfigState, err := s.GetPoolConfig(ctx, poolState.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maxBase, maxQuote := poolState.ProtocolBaseFee, poolState.ProtocolQuoteFee
+	if params.MaxBaseAmount != nil {
+		if maxBase, err = helpers.BigIntToU64(params.MaxBaseAmount); err != nil {
+			return nil, nil, err
+		}
+	}
+	if params.MaxQuoteAmount != nil {
+		if maxQuote, err = helpers.BigIntToU64(params.MaxQuoteAmount); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	claim, err := s.protocolFeeClaim(ProgramAccount[VirtualPool]{Pubkey: params.Pool, Account: poolState}, poolConfigState, params.Operator, params.Payer, params.Treasury, maxBase, maxQuote)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ata := range claim.atas {
+		pre = append(pre, helpers.CreateAssociatedTokenAccountIdempotentInstruction(params.Payer, ata.ata, ata.owner, ata.mint, ata.tokenProgram))
+	}
+	return pre, claim.instructions[0], nil
+}
+
+// ClaimProtocolPoolCreationFee builds the operator instruction sending the protocol share of the pool
+// creation fee to the treasury.
+func (s *DynamicBondingCurve) ClaimProtocolPoolCreationFee(ctx context.Context, params ClaimProtocolPoolCreationFeeParams) (solanago.Instruction, error) {
+	poolState, err := s.GetPool(ctx, params.Pool)
+	if err != nil {
+		return nil, err
+	}
+	return s.protocolPoolCreationFeeInstruction(params.Pool, poolState.Config, params.Operator, params.Treasury)
+}
+
+// GetProtocolFeesOwed reports the protocol fees claimable from every pool of configs, or of all pools
+// when configs is empty. Pools with nothing to claim are left out.
+func (s *DynamicBondingCurve) GetProtocolFeesOwed(ctx context.Context, configs ...solanago.PublicKey) ([]ProtocolFeeOwed, error) {
+	pools, poolConfigs, err := s.poolsWithConfigs(ctx, configs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ProtocolFeeOwed, 0)
+	for _, pool := range pools {
+		owed := helpers.ProtocolFeeOwedFromState(pool.Pubkey, pool.Account, poolConfigs[pool.Account.Config])
+		if owed.BaseFee > 0 || owed.QuoteFee > 0 || owed.PoolCreationFee > 0 {
+			out = append(out, owed)
+		}
+	}
+	return out, nil
+}
+
+// ClaimAllProtocolFees builds transactions claiming the protocol fees of every pool of params.Configs,
+// or of all pools, packed like ClaimAllPartnerFees.
+func (s *DynamicBondingCurve) ClaimAllProtocolFees(ctx context.Context, params ClaimAllProtocolFeesParams) (ClaimAllFeesResult, error) {
+	pools, poolConfigs, err := s.poolsWithConfigs(ctx, params.Configs)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	var result ClaimAllFeesResult
+	var claims []feeClaim
+	for _, pool := range pools {
+		config := poolConfigs[pool.Account.Config]
+		owed := helpers.ProtocolFeeOwedFromState(pool.Pubkey, pool.Account, config)
+		claimTradingFee := feeAboveThreshold(owed.BaseFee, owed.QuoteFee, params.MinBaseFee, params.MinQuoteFee)
+		claimCreationFee := params.ClaimPoolCreationFee && owed.PoolCreationFee > 0
+		if !claimTradingFee && !claimCreationFee {
+			result.Skipped = append(result.Skipped, pool.Pubkey)
+			continue
+		}
+		claim := feeClaim{pool: pool.Pubkey}
+		if claimTradingFee {
+			if claim, err = s.protocolFeeClaim(pool, config, params.Operator, params.Payer, params.Treasury, owed.BaseFee, owed.QuoteFee); err != nil {
+				return ClaimAllFeesResult{}, err
+			}
+		}
+		if claimCreationFee {
+			ix, err := s.protocolPoolCreationFeeInstruction(pool.Pubkey, pool.Account.Config, params.Operator, params.Treasury)
+			if err != nil {
+				return ClaimAllFeesResult{}, err
+			}
+			claim.instructions = append(claim.instructions, ix)
+		}
+		claims = append(claims, claim)
+	}
+	result.Batches, err = packFeeClaims(ClaimAllFeesParams{
+		Owner:              params.Operator,
+		Payer:              params.Payer,
+		ComputeUnitLimit:   params.ComputeUnitLimit,
+		MaxTransactionSize: params.MaxTransactionSize,
+	}, claims)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	return result, nil
+}
+
+// protocolFeeClaim builds the claim protocol fee instruction of a pool and the treasury token accounts it needs.
+func (s *DynamicBondingCurve) protocolFeeClaim(pool ProgramAccount[VirtualPool], config *PoolConfig, operator, payer, treasury solanago.PublicKey, maxBase, maxQuote uint64) (feeClaim, error) {
+	if treasury.IsZero() {
+		treasury = helpers.ProtocolTreasury
+	}
+	poolState := pool.Account
+	tokenBaseProgram := helpers.GetTokenProgram(TokenType(config.TokenType))
+	tokenQuoteProgram := helpers.GetTokenProgram(TokenType(config.QuoteTokenFlag))
+
+	tokenBaseAccount, err := helpers.FindAssociatedTokenAddress(treasury, poolState.BaseMint, tokenBaseProgram)
+	if err != nil {
+		return feeClaim{}, err
+	}
+	tokenQuoteAccount, err := helpers.FindAssociatedTokenAddress(treasury, config.QuoteMint, tokenQuoteProgram)
+	if err != nil {
+		return feeClaim{}, err
+	}
+
+	ix, err := dbcidl.NewClaimProtocolFeeInstruction(
+		maxBase,
+		maxQuote,
+		s.PoolAuthority,
+		poolState.Config,
+		pool.Pubkey,
+		poolState.BaseVault,
+		poolState.QuoteVault,
+		poolState.BaseMint,
+		config.QuoteMint,
+		tokenBaseAccount,
+		tokenQuoteAccount,
+		helpers.DeriveClaimFeeOperatorAddress(operator),
+		operator,
+		tokenBaseProgram,
+		tokenQuoteProgram,
+		s.EventAuthority,
+		helpers.DynamicBondingCurveProgramID,
+	)
+	if err != nil {
+		return feeClaim{}, err
+	}
+	return feeClaim{
+		pool: pool.Pubkey,
+		atas: []feeClaimATA{
+			{ata: tokenBaseAccount, owner: treasury, mint: poolState.BaseMint, tokenProgram: tokenBaseProgram},
+			{ata: tokenQuoteAccount, owner: treasury, mint: config.QuoteMint, tokenProgram: tokenQuoteProgram},
+		},
+		instructions: []solanago.Instruction{ix},
+	}, nil
+}
+
+// protocolPoolCreationFeeInstruction builds the claim protocol pool creation fee instruction of a pool.
+func (s *DynamicBondingCurve) protocolPoolCreationFeeInstruction(pool, config, operator, treasury solanago.PublicKey) (solanago.Instruction, error) {
+	if treasury.IsZero() {
+		treasury = helpers.ProtocolTreasury
+	}
+	return dbcidl.NewClaimProtocolPoolCreationFeeInstruction(
+		config,
+		pool,
+		helpers.DeriveClaimFeeOperatorAddress(operator),
+		operator,
+		treasury,
+		s.EventAuthority,
+		helpers.DynamicBondingCurveProgramID,
+	)
+}
+
+// poolsWithConfigs lists the pools of configs, or all pools when configs is empty, with their configs.
+func (s *DynamicBondingCurve) poolsWithConfigs(ctx context.Context, configs []solanago.PublicKey) ([]ProgramAccount[VirtualPool], map[solanago.PublicKey]*PoolConfig, error) {
+	poolConfigs := make(map[solanago.PublicKey]*PoolConfig)
+	if len(configs) == 0 {
+		allConfigs, err := s.GetPoolConfigs(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, config := range allConfigs {
+			poolConfigs[config.Pubkey] = config.Account
+		}
+		pools, err := s.GetPools(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		// a pool whose config failed to parse cannot be priced or claimed.
+		out := pools[:0]
+		for _, pool := range pools {
+			if poolConfigs[pool.Account.Config] != nil {
+				out = append(out, pool)
+			}
+		}
+		return out, poolConfigs, nil
+	}
+
+	var pools []ProgramAccount[VirtualPool]
+	for _, configAddress := range configs {
+		config, err := s.GetPoolConfig(ctx, configAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		poolConfigs[configAddress] = config
+		configPools, err := s.GetPoolsByConfig(ctx, configAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		pools = append(pools, configPools...)
+	}
+	return pools, poolConfigs, nil
+}
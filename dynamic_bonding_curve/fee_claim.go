@@ -0,0 +1,344 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+
+	solanago "github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+	"github.com/krazyTry/meteora-go/internal/txsize"
+)
+
+// feeClaim holds the instructions of one pool in a bulk claim.
+type feeClaim struct {
+	pool solanago.PublicKey
+	// atas are created idempotently at the start of every transaction that carries the claim.
+	atas         []feeClaimATA
+	instructions []solanago.Instruction
+	// unwrap is set when quote is SOL and the owner's WSOL account must be closed to the receiver.
+	unwrap bool
+}
+
+type feeClaimATA struct {
+	ata, owner, mint, tokenProgram solanago.PublicKey
+}
+
+// ClaimAllPartnerFees builds transactions claiming the partner trading fees of every pool whose config
+// has params.Owner as fee claimer, optionally with the surplus and migration fees of migrated pools.
+func (s *DynamicBondingCurve) ClaimAllPartnerFees(ctx context.Context, params ClaimAllFeesParams) (ClaimAllFeesResult, error) {
+	configs, err := s.GetPoolConfigsByFeeClaimer(ctx, params.Owner)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	var result ClaimAllFeesResult
+	var claims []feeClaim
+	for _, config := range configs {
+		pools, err := s.GetPoolsByConfig(ctx, config.Pubkey)
+		if err != nil {
+			return ClaimAllFeesResult{}, err
+		}
+		for _, pool := range pools {
+			claim, err := s.feeClaim(params, pool, config.Account, true)
+			if err != nil {
+				return ClaimAllFeesResult{}, err
+			}
+			if len(claim.instructions) == 0 {
+				result.Skipped = append(result.Skipped, pool.Pubkey)
+				continue
+			}
+			claims = append(claims, claim)
+		}
+	}
+	result.Batches, err = packFeeClaims(params, claims)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	return result, nil
+}
+
+// ClaimAllCreatorFees builds transactions claiming the creator trading fees of every pool of params.Owner,
+// optionally with the surplus and migration fees of migrated pools.
+func (s *DynamicBondingCurve) ClaimAllCreatorFees(ctx context.Context, params ClaimAllFeesParams) (ClaimAllFeesResult, error) {
+	pools, err := s.GetPoolsByCreator(ctx, params.Owner)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	var result ClaimAllFeesResult
+	var claims []feeClaim
+	configs := make(map[solanago.PublicKey]*PoolConfig)
+	for _, pool := range pools {
+		config, ok := configs[pool.Account.Config]
+		if !ok {
+			if config, err = s.GetPoolConfig(ctx, pool.Account.Config); err != nil {
+				return ClaimAllFeesResult{}, err
+			}
+			configs[pool.Account.Config] = config
+		}
+		claim, err := s.feeClaim(params, pool, config, false)
+		if err != nil {
+			return ClaimAllFeesResult{}, err
+		}
+		if len(claim.instructions) == 0 {
+			result.Skipped = append(result.Skipped, pool.Pubkey)
+			continue
+		}
+		claims = append(claims, claim)
+	}
+	result.Batches, err = packFeeClaims(params, claims)
+	if err != nil {
+		return ClaimAllFeesResult{}, err
+	}
+	return result, nil
+}
+
+// feeClaim builds the claim and withdraw instructions of one pool for the partner or the creator.
+func (s *DynamicBondingCurve) feeClaim(params ClaimAllFeesParams, pool ProgramAccount[VirtualPool], config *PoolConfig, isPartner bool) (feeClaim, error) {
+	poolState := pool.Account
+	receiver := params.Owner
+	if !params.Receiver.IsZero() {
+		receiver = params.Receiver
+	}
+	tokenBaseProgram := helpers.GetTokenProgram(TokenType(config.TokenType))
+	tokenQuoteProgram := helpers.GetTokenProgram(TokenType(config.QuoteTokenFlag))
+
+	claim := feeClaim{pool: pool.Pubkey, unwrap: helpers.IsNativeSol(config.QuoteMint)}
+	addATA := func(owner, mint, tokenProgram solanago.PublicKey) (solanago.PublicKey, error) {
+		ata, err := helpers.FindAssociatedTokenAddress(owner, mint, tokenProgram)
+		if err != nil {
+			return solanago.PublicKey{}, err
+		}
+		claim.atas = append(claim.atas, feeClaimATA{ata: ata, owner: owner, mint: mint, tokenProgram: tokenProgram})
+		return ata, nil
+	}
+	// SOL is collected in the owner's WSOL account and unwrapped to the receiver once per transaction.
+	quoteOwner := receiver
+	if claim.unwrap {
+		quoteOwner = params.Owner
+	}
+
+	baseFee, quoteFee := poolState.CreatorBaseFee, poolState.CreatorQuoteFee
+	if isPartner {
+		baseFee, quoteFee = poolState.PartnerBaseFee, poolState.PartnerQuoteFee
+	}
+	claimTradingFee := feeAboveThreshold(baseFee, quoteFee, params.MinBaseFee, params.MinQuoteFee)
+
+	progress := MigrationProgress(poolState.MigrationProgress)
+	migrated := progress == MigrationProgressCreatedPool
+	withdrawSurplus := params.WithdrawSurplus && migrated && poolState.QuoteReserve > config.MigrationQuoteThreshold
+	feeStatus := MigrationFeeWithdrawStatus(poolState.MigrationFeeWithdrawStatus)
+	withdrawMigrationFee := params.WithdrawMigrationFee && migrated && config.MigrationFeePercentage > 0
+	if isPartner {
+		withdrawSurplus = withdrawSurplus && poolState.IsPartnerWithdrawSurplus == 0
+		withdrawMigrationFee = withdrawMigrationFee && config.CreatorMigrationFeePercentage < 100 && feeStatus.IsPartnerWithdraw() == 0
+	} else {
+		withdrawSurplus = withdrawSurplus && config.CreatorTradingFeePercentage > 0 && poolState.IsCreatorWithdrawSurplus == 0
+		withdrawMigrationFee = withdrawMigrationFee && config.CreatorMigrationFeePercentage > 0 && feeStatus.IsCreatorWithdraw() == 0
+	}
+	if !claimTradingFee && !withdrawSurplus && !withdrawMigrationFee {
+		return feeClaim{pool: pool.Pubkey}, nil
+	}
+
+	tokenQuoteAccount, err := addATA(quoteOwner, config.QuoteMint, tokenQuoteProgram)
+	if err != nil {
+		return feeClaim{}, err
+	}
+
+	if claimTradingFee {
+		tokenBaseAccount, err := addATA(receiver, poolState.BaseMint, tokenBaseProgram)
+		if err != nil {
+			return feeClaim{}, err
+		}
+		var ix solanago.Instruction
+		if isPartner {
+			ix, err = dbcidl.NewClaimTradingFeeInstruction(
+				baseFee,
+				quoteFee,
+				s.PoolAuthority,
+				poolState.Config,
+				pool.Pubkey,
+				tokenBaseAccount,
+				tokenQuoteAccount,
+				poolState.BaseVault,
+				poolState.QuoteVault,
+				poolState.BaseMint,
+				config.QuoteMint,
+				params.Owner,
+				tokenBaseProgram,
+				tokenQuoteProgram,
+				s.EventAuthority,
+				helpers.DynamicBondingCurveProgramID,
+			)
+		} else {
+			ix, err = dbcidl.NewClaimCreatorTradingFeeInstruction(
+				baseFee,
+				quoteFee,
+				s.PoolAuthority,
+				pool.Pubkey,
+				tokenBaseAccount,
+				tokenQuoteAccount,
+				poolState.BaseVault,
+				poolState.QuoteVault,
+				poolState.BaseMint,
+				config.QuoteMint,
+				params.Owner,
+				tokenBaseProgram,
+				tokenQuoteProgram,
+				s.EventAuthority,
+				helpers.DynamicBondingCurveProgramID,
+			)
+		}
+		if err != nil {
+			return feeClaim{}, err
+		}
+		claim.instructions = append(claim.instructions, ix)
+	}
+
+	if withdrawSurplus {
+		newWithdrawSurplusInstruction := dbcidl.NewCreatorWithdrawSurplusInstruction
+		if isPartner {
+			newWithdrawSurplusInstruction = dbcidl.NewPartnerWithdrawSurplusInstruction
+		}
+		ix, err := newWithdrawSurplusInstruction(
+			s.PoolAuthority,
+			poolState.Config,
+			pool.Pubkey,
+			tokenQuoteAccount,
+			poolState.QuoteVault,
+			config.QuoteMint,
+			params.Owner,
+			tokenQuoteProgram,
+			s.EventAuthority,
+			helpers.DynamicBondingCurveProgramID,
+		)
+		if err != nil {
+			return feeClaim{}, err
+		}
+		claim.instructions = append(claim.instructions, ix)
+	}
+
+	if withdrawMigrationFee {
+		flag := uint8(1) // 0. partner 1. creator
+		if isPartner {
+			flag = 0
+		}
+		ix, err := dbcidl.NewWithdrawMigrationFeeInstruction(
+			flag,
+			s.PoolAuthority,
+			poolState.Config,
+			pool.Pubkey,
+			tokenQuoteAccount,
+			poolState.QuoteVault,
+			config.QuoteMint,
+			params.Owner,
+			tokenQuoteProgram,
+			s.EventAuthority,
+			helpers.DynamicBondingCurveProgramID,
+		)
+		if err != nil {
+			return feeClaim{}, err
+		}
+		claim.instructions = append(claim.instructions, ix)
+	}
+	return claim, nil
+}
+
+// feeAboveThreshold reports whether a fee reaches its threshold. A threshold of 0 is not set; with
+// neither set any fee above 0 is enough.
+func feeAboveThreshold(baseFee, quoteFee, minBaseFee, minQuoteFee uint64) bool {
+	if minBaseFee == 0 && minQuoteFee == 0 {
+		return baseFee > 0 || quoteFee > 0
+	}
+	return (minBaseFee > 0 && baseFee >= minBaseFee) || (minQuoteFee > 0 && quoteFee >= minQuoteFee)
+}
+
+// packFeeClaims fills transactions with whole pool claims in order until the next one would exceed
+// the size limit. Every transaction creates its token accounts and unwraps SOL on its own.
+func packFeeClaims(params ClaimAllFeesParams, claims []feeClaim) ([]FeeClaimBatch, error) {
+	sizeLimit := params.MaxTransactionSize
+	if sizeLimit == 0 {
+		sizeLimit = txsize.MaxTransactionSize
+	}
+	receiver := params.Owner
+	if !params.Receiver.IsZero() {
+		receiver = params.Receiver
+	}
+
+	build := func(batch []feeClaim) (*solanago.Transaction, int, error) {
+		instructions := []solanago.Instruction{}
+		if params.ComputeUnitLimit > 0 {
+			instructions = append(instructions, computebudget.NewSetComputeUnitLimitInstructionBuilder().SetUnits(params.ComputeUnitLimit).Build())
+		}
+		created := make(map[solanago.PublicKey]bool)
+		unwrap := false
+		for _, claim := range batch {
+			for _, ata := range claim.atas {
+				if created[ata.ata] {
+					continue
+				}
+				created[ata.ata] = true
+				instructions = append(instructions, helpers.CreateAssociatedTokenAccountIdempotentInstruction(params.Payer, ata.ata, ata.owner, ata.mint, ata.tokenProgram))
+			}
+			unwrap = unwrap || claim.unwrap
+		}
+		for _, claim := range batch {
+			instructions = append(instructions, claim.instructions...)
+		}
+		if unwrap {
+			unwrapIx, err := helpers.UnwrapSOLInstruction(params.Owner, receiver, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			instructions = append(instructions, unwrapIx)
+		}
+		tx, err := solanago.NewTransaction(instructions, solanago.Hash{}, solanago.TransactionPayer(params.Payer))
+		if err != nil {
+			return nil, 0, err
+		}
+		size, err := txsize.Of(tx)
+		if err != nil {
+			return nil, 0, err
+		}
+		return tx, size, nil
+	}
+
+	var batches []FeeClaimBatch
+	var current []feeClaim
+	var currentTx *solanago.Transaction
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		pools := make([]solanago.PublicKey, 0, len(current))
+		for _, claim := range current {
+			pools = append(pools, claim.pool)
+		}
+		batches = append(batches, FeeClaimBatch{Transaction: currentTx, Pools: pools})
+		current = nil
+	}
+	for _, claim := range claims {
+		tx, size, err := build(append(current[:len(current):len(current)], claim))
+		if err != nil {
+			return nil, err
+		}
+		if size <= sizeLimit {
+			current = append(current, claim)
+			currentTx = tx
+			continue
+		}
+		flush()
+		tx, size, err = build([]feeClaim{claim})
+		if err != nil {
+			return nil, err
+		}
+		if size > sizeLimit {
+			return nil, fmt.Errorf("claims of pool %s need %d bytes, over the %d byte transaction limit", claim.pool, size, sizeLimit)
+		}
+		current = []feeClaim{claim}
+		currentTx = tx
+	}
+	flush()
+	return batches, nil
+}
@@ -0,0 +1,58 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+
+	solanago "github.com/gagliardetto/solana-go"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+)
+
+// GetPoolFeeMetrics reports the unclaimed and claimed trading fees of a pool per party.
+// With withValue set, the fees are also valued in quote at the current curve price.
+func (s *DynamicBondingCurve) GetPoolFeeMetrics(ctx context.Context, pool solanago.PublicKey, withValue bool) (PoolFeeMetrics, error) {
+	poolState, err := s.GetPool(ctx, pool)
+	if err != nil {
+		return PoolFeeMetrics{}, err
+	}
+	config, err := s.GetPoolConfig(ctx, poolState.Config)
+	if err != nil {
+		return PoolFeeMetrics{}, err
+	}
+	return helpers.PoolFeeMetricsFromState(pool, poolState, config, withValue), nil
+}
+
+// GetFeesByConfig reports the fee metrics of every pool created with a config and their totals.
+func (s *DynamicBondingCurve) GetFeesByConfig(ctx context.Context, configAddress solanago.PublicKey, withValue bool) (FeeMetrics, error) {
+	pools, err := s.GetPoolsByConfig(ctx, configAddress)
+	if err != nil {
+		return FeeMetrics{}, err
+	}
+	return s.feeMetrics(ctx, pools, withValue)
+}
+
+// GetFeesByCreator reports the fee metrics of every pool of a creator and their totals per quote mint.
+func (s *DynamicBondingCurve) GetFeesByCreator(ctx context.Context, creatorAddress solanago.PublicKey, withValue bool) (FeeMetrics, error) {
+	pools, err := s.GetPoolsByCreator(ctx, creatorAddress)
+	if err != nil {
+		return FeeMetrics{}, err
+	}
+	return s.feeMetrics(ctx, pools, withValue)
+}
+
+func (s *DynamicBondingCurve) feeMetrics(ctx context.Context, pools []ProgramAccount[VirtualPool], withValue bool) (FeeMetrics, error) {
+	var metrics FeeMetrics
+	configs := make(map[solanago.PublicKey]*PoolConfig)
+	for _, pool := range pools {
+		config, ok := configs[pool.Account.Config]
+		if !ok {
+			var err error
+			if config, err = s.GetPoolConfig(ctx, pool.Account.Config); err != nil {
+				return FeeMetrics{}, err
+			}
+			configs[pool.Account.Config] = config
+		}
+		helpers.AddPoolFeeMetrics(&metrics, helpers.PoolFeeMetricsFromState(pool.Pubkey, pool.Account, config, withValue))
+	}
+	return metrics, nil
+}
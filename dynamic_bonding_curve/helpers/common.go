@@ -91,7 +91,7 @@ func GetTotalTokenSupply(swapBaseAmount, migrationBaseThreshold *big.Int, locked
 	locked.Add(locked, lockedVestingParams.CliffUnlockAmount)
 	total := new(big.Int).Add(totalCirculating, locked)
 	if total.Sign() < 0 || total.BitLen() > 64 {
-		return nil, errors.New("math overflow")
+		return nil, shared.NewMathOverflowError("total token supply")
 	}
 	return total, nil
 }
@@ -0,0 +1,686 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+	"github.com/shopspring/decimal"
+)
+
+// feeNumeratorBpsShift converts bps to a fee numerator over shared.FeeDenominator.
+const feeNumeratorBpsShift = 5
+
+// configFileDerivedTolerance is the relative difference allowed between a derived field and the
+// value recomputed from the exact fields, so rounded values still load.
+var configFileDerivedTolerance = decimal.New(1, -6)
+
+// configFilePricePrecision is the decimal places kept in config file prices, enough for a price
+// converted back to a sqrt price to land within a few units of the original.
+const configFilePricePrecision = 30
+
+// Config file enum names, indexed by the on-chain value.
+var (
+	tokenTypeNames            = []string{"spl", "token2022"}
+	activationTypeNames       = []string{"slot", "timestamp"}
+	collectFeeModeNames       = []string{"quoteToken", "outputToken"}
+	baseFeeModeNames          = []string{"feeSchedulerLinear", "feeSchedulerExponential", "rateLimiter"}
+	migrationOptionNames      = []string{"metDamm", "metDammV2"}
+	migrationFeeOptionNames   = []string{"fixedBps25", "fixedBps30", "fixedBps100", "fixedBps200", "fixedBps400", "fixedBps600", "customizable"}
+	tokenUpdateAuthorityNames = []string{"creatorUpdateAuthority", "immutable", "partnerUpdateAuthority", "creatorUpdateAndMintAuthority", "partnerUpdateAndMintAuthority"}
+	dammV2BaseFeeModeNames    = []string{"feeTimeSchedulerLinear", "feeTimeSchedulerExponential", "rateLimiter", "feeMarketCapSchedulerLinear", "feeMarketCapSchedulerExp"}
+	dammV2DynamicFeeModeNames = []string{"disabled", "enabled"}
+)
+
+// LoadConfigFile decodes a JSON config file, converts it to create config params and validates them.
+// Unknown fields are rejected so that misspelt settings do not silently fall back to zero, and derived
+// fields that are set must match the values recomputed from the exact fields.
+func LoadConfigFile(data []byte) (shared.CreateConfigParams, error) {
+	var file shared.ConfigFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	params, err := ConfigParamsFromConfigFile(file)
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	if err := ValidateConfigParameters(params); err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	if err := checkConfigFileDerived(file, params); err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	return params, nil
+}
+
+// derivedField is a config file field derived from the exact fields and its recomputed value.
+type derivedField struct {
+	name      string
+	got, want decimal.Decimal
+}
+
+// checkConfigFileDerived recomputes the derived fields of file from params and rejects any set field
+// that disagrees, so an edited price or market cap is not silently dropped.
+func checkConfigFileDerived(file shared.ConfigFile, params shared.CreateConfigParams) error {
+	derived, err := ConfigFileFromConfigParams(params, file.QuoteDecimal)
+	if err != nil {
+		return err
+	}
+	checks := []derivedField{
+		{"base supply", file.BaseSupply, derived.BaseSupply},
+		{"initial price", file.InitialPrice, derived.InitialPrice},
+		{"initial market cap", file.InitialMarketCap, derived.InitialMarketCap},
+		{"migration price", file.MigrationPrice, derived.MigrationPrice},
+		{"migration market cap", file.MigrationMarketCap, derived.MigrationMarketCap},
+		{"locked vesting percentage", file.LockedVesting.Percentage, derived.LockedVesting.Percentage},
+	}
+	for i, point := range file.Curve {
+		if i >= len(derived.Curve) {
+			break
+		}
+		checks = append(checks,
+			derivedField{fmt.Sprintf("curve point %d price", i), point.Price, derived.Curve[i].Price},
+			derivedField{fmt.Sprintf("curve point %d market cap", i), point.MarketCap, derived.Curve[i].MarketCap},
+			derivedField{fmt.Sprintf("curve point %d base amount", i), point.BaseAmount, derived.Curve[i].BaseAmount},
+		)
+	}
+	for _, check := range checks {
+		if check.got.IsZero() {
+			continue
+		}
+		if check.got.Sub(check.want).Abs().GreaterThan(check.want.Abs().Mul(configFileDerivedTolerance)) {
+			return fmt.Errorf("%s %s does not match %s derived from the exact fields", check.name, check.got, check.want)
+		}
+	}
+	return nil
+}
+
+// MarshalConfigFile encodes file as indented JSON for review.
+func MarshalConfigFile(file shared.ConfigFile) ([]byte, error) {
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// ConfigParamsFromConfigFile converts file to create config params, deriving the exact fields left
+// zero from the human ones. Config and Payer are left for the caller.
+func ConfigParamsFromConfigFile(file shared.ConfigFile) (shared.CreateConfigParams, error) {
+	tokenDecimal := int32(file.TokenDecimal)
+	quoteDecimal := int32(file.QuoteDecimal)
+
+	tokenType, err := enumValue(tokenTypeNames, file.TokenType, "token type")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	tokenUpdateAuthority, err := enumValue(tokenUpdateAuthorityNames, file.TokenUpdateAuthority, "token update authority")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	activationType, err := enumValue(activationTypeNames, file.ActivationType, "activation type")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	collectFeeMode, err := enumValue(collectFeeModeNames, file.CollectFeeMode, "collect fee mode")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	migrationOption, err := enumValue(migrationOptionNames, file.MigrationOption, "migration option")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	migrationFeeOption, err := enumValue(migrationFeeOptionNames, file.MigrationFeeOption, "migration fee option")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	migratedPoolBaseFeeMode, err := enumValue(dammV2BaseFeeModeNames, file.MigratedPoolBaseFeeMode, "migrated pool base fee mode")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	migratedCollectFeeMode, err := enumValue(collectFeeModeNames, file.MigratedPoolFee.CollectFeeMode, "migrated pool collect fee mode")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	migratedDynamicFee, err := enumValue(dammV2DynamicFeeModeNames, file.MigratedPoolFee.DynamicFee, "migrated pool dynamic fee")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+
+	baseFee, err := baseFeeFromConfigFile(file.BaseFee, shared.ActivationType(activationType), quoteDecimal)
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	poolCreationFee, err := decimalToU64(file.PoolCreationFee, 9, "pool creation fee")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+
+	var tokenSupply *shared.TokenSupplyParams
+	baseSupply := file.BaseSupply
+	if file.TokenSupply != nil {
+		preMigration, err := decimalToU64(file.TokenSupply.PreMigrationTokenSupply, tokenDecimal, "pre migration token supply")
+		if err != nil {
+			return shared.CreateConfigParams{}, err
+		}
+		postMigration, err := decimalToU64(file.TokenSupply.PostMigrationTokenSupply, tokenDecimal, "post migration token supply")
+		if err != nil {
+			return shared.CreateConfigParams{}, err
+		}
+		tokenSupply = &shared.TokenSupplyParams{PreMigrationTokenSupply: preMigration, PostMigrationTokenSupply: postMigration}
+		if baseSupply.IsZero() {
+			baseSupply = file.TokenSupply.PreMigrationTokenSupply
+		}
+	}
+
+	sqrtStartPrice, err := sqrtPriceFromConfigFile(file.SqrtStartPrice, file.InitialPrice, file.InitialMarketCap, baseSupply, file.TokenDecimal, file.QuoteDecimal, "start")
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+	curve := make([]shared.LiquidityDistributionParameters, 0, len(file.Curve))
+	lowerSqrtPrice := sqrtStartPrice
+	for i, point := range file.Curve {
+		name := fmt.Sprintf("curve point %d", i)
+		sqrtPrice, err := sqrtPriceFromConfigFile(point.SqrtPrice, point.Price, point.MarketCap, baseSupply, file.TokenDecimal, file.QuoteDecimal, name)
+		if err != nil {
+			return shared.CreateConfigParams{}, err
+		}
+		liquidity, err := liquidityFromConfigFile(point, lowerSqrtPrice, sqrtPrice, tokenDecimal, name)
+		if err != nil {
+			return shared.CreateConfigParams{}, err
+		}
+		curve = append(curve, shared.LiquidityDistributionParameters{SqrtPrice: BigToU128(sqrtPrice), Liquidity: BigToU128(liquidity)})
+		lowerSqrtPrice = sqrtPrice
+	}
+
+	var migrationQuoteThreshold uint64
+	if !file.MigrationQuoteThreshold.IsZero() || (file.MigrationPrice.IsZero() && file.MigrationMarketCap.IsZero()) {
+		migrationQuoteThreshold, err = decimalToU64(file.MigrationQuoteThreshold, quoteDecimal, "migration quote threshold")
+	} else {
+		migrationQuoteThreshold, err = migrationQuoteThresholdFromConfigFile(file, baseSupply, sqrtStartPrice, curve)
+	}
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+
+	lockedVesting, err := lockedVestingFromConfigFile(file.LockedVesting, baseSupply, tokenDecimal)
+	if err != nil {
+		return shared.CreateConfigParams{}, err
+	}
+
+	return shared.CreateConfigParams{
+		ConfigParameters: shared.ConfigParameters{
+			PoolFees: shared.PoolFeeParameters{
+				BaseFee:    baseFee,
+				DynamicFee: file.DynamicFee,
+			},
+			CollectFeeMode:             collectFeeMode,
+			MigrationOption:            migrationOption,
+			ActivationType:             activationType,
+			TokenType:                  tokenType,
+			TokenDecimal:               uint8(file.TokenDecimal),
+			PartnerLiquidityPercentage: file.PartnerLiquidityPercentage,
+			PartnerPermanentLockedLiquidityPercentage: file.PartnerPermanentLockedLiquidityPercentage,
+			CreatorLiquidityPercentage:                file.CreatorLiquidityPercentage,
+			CreatorPermanentLockedLiquidityPercentage: file.CreatorPermanentLockedLiquidityPercentage,
+			MigrationQuoteThreshold:                   migrationQuoteThreshold,
+			SqrtStartPrice:                            BigToU128(sqrtStartPrice),
+			LockedVesting:                             lockedVesting,
+			MigrationFeeOption:                        migrationFeeOption,
+			TokenSupply:                               tokenSupply,
+			CreatorTradingFeePercentage:               file.CreatorTradingFeePercentage,
+			TokenUpdateAuthority:                      tokenUpdateAuthority,
+			MigrationFee:                              file.MigrationFee,
+			MigratedPoolFee: shared.MigratedPoolFee{
+				CollectFeeMode: migratedCollectFeeMode,
+				DynamicFee:     migratedDynamicFee,
+				PoolFeeBps:     file.MigratedPoolFee.PoolFeeBps,
+			},
+			PoolCreationFee:                         poolCreationFee,
+			PartnerLiquidityVestingInfo:             file.PartnerLiquidityVesting,
+			CreatorLiquidityVestingInfo:             file.CreatorLiquidityVesting,
+			MigratedPoolBaseFeeMode:                 migratedPoolBaseFeeMode,
+			MigratedPoolMarketCapFeeSchedulerParams: file.MigratedPoolMarketCapFeeScheduler,
+			EnableFirstSwapWithMinFee:               file.EnableFirstSwapWithMinFee,
+			Curve:                                   curve,
+		},
+		FeeClaimer:       file.FeeClaimer,
+		LeftoverReceiver: file.LeftoverReceiver,
+		QuoteMint:        file.QuoteMint,
+	}, nil
+}
+
+// sqrtPriceFromConfigFile returns the exact sqrt price when set, otherwise the sqrt price of price,
+// or of marketCap spread over baseSupply.
+func sqrtPriceFromConfigFile(sqrtPrice, price, marketCap, baseSupply decimal.Decimal, tokenDecimal, quoteDecimal shared.TokenDecimal, name string) (*big.Int, error) {
+	if !sqrtPrice.IsZero() {
+		return decimalToBig(sqrtPrice, name+" sqrt price")
+	}
+	if price.IsZero() && !marketCap.IsZero() {
+		if !baseSupply.IsPositive() {
+			return nil, fmt.Errorf("%s market cap requires a base supply", name)
+		}
+		price = marketCap.DivRound(baseSupply, configFilePricePrecision)
+	}
+	if !price.IsPositive() {
+		return nil, fmt.Errorf("%s requires a sqrt price, price or market cap", name)
+	}
+	return GetSqrtPriceFromPrice(price.String(), int(tokenDecimal), int(quoteDecimal))
+}
+
+// liquidityFromConfigFile returns the exact liquidity of point when set, otherwise the liquidity that
+// sells its base amount between lowerSqrtPrice and upperSqrtPrice.
+func liquidityFromConfigFile(point shared.ConfigFileCurvePoint, lowerSqrtPrice, upperSqrtPrice *big.Int, tokenDecimal int32, name string) (*big.Int, error) {
+	if !point.Liquidity.IsZero() {
+		return decimalToBig(point.Liquidity, name+" liquidity")
+	}
+	if !point.BaseAmount.IsPositive() {
+		return nil, fmt.Errorf("%s requires a liquidity or base amount", name)
+	}
+	if upperSqrtPrice.Cmp(lowerSqrtPrice) <= 0 {
+		return nil, fmt.Errorf("%s price must be above the previous one", name)
+	}
+	// base = liquidity * (upper - lower) / (lower * upper)
+	liquidity := point.BaseAmount.Shift(tokenDecimal).
+		Mul(decimal.NewFromBigInt(lowerSqrtPrice, 0)).
+		Mul(decimal.NewFromBigInt(upperSqrtPrice, 0)).
+		Div(decimal.NewFromBigInt(new(big.Int).Sub(upperSqrtPrice, lowerSqrtPrice), 0))
+	return liquidity.Floor().BigInt(), nil
+}
+
+// migrationQuoteThresholdFromConfigFile returns the quote raised buying along curve from
+// sqrtStartPrice up to the migration price or market cap of file.
+func migrationQuoteThresholdFromConfigFile(file shared.ConfigFile, baseSupply decimal.Decimal, sqrtStartPrice *big.Int, curve []shared.LiquidityDistributionParameters) (uint64, error) {
+	sqrtMigrationPrice, err := sqrtPriceFromConfigFile(decimal.Zero, file.MigrationPrice, file.MigrationMarketCap, baseSupply, file.TokenDecimal, file.QuoteDecimal, "migration")
+	if err != nil {
+		return 0, err
+	}
+	threshold := new(big.Int)
+	lowerSqrtPrice := sqrtStartPrice
+	for _, point := range curve {
+		upperSqrtPrice := point.SqrtPrice.BigInt()
+		reached := upperSqrtPrice.Cmp(sqrtMigrationPrice) >= 0
+		if reached {
+			upperSqrtPrice = sqrtMigrationPrice
+		}
+		if upperSqrtPrice.Cmp(lowerSqrtPrice) > 0 {
+			amount, err := GetDeltaAmountQuoteUnsigned(lowerSqrtPrice, upperSqrtPrice, point.Liquidity.BigInt(), shared.RoundingUp)
+			if err != nil {
+				return 0, err
+			}
+			threshold.Add(threshold, amount)
+		}
+		if reached {
+			return shared.CheckedU64(threshold, "migration quote threshold")
+		}
+		lowerSqrtPrice = upperSqrtPrice
+	}
+	return 0, errors.New("migration price is above the curve")
+}
+
+// lockedVestingFromConfigFile converts the locked vesting, splitting Percentage of baseSupply over
+// the periods when no amount per period is given.
+func lockedVestingFromConfigFile(vesting shared.ConfigFileLockedVesting, baseSupply decimal.Decimal, tokenDecimal int32) (shared.LockedVestingParameters, error) {
+	amountPerPeriod, err := decimalToU64(vesting.AmountPerPeriod, tokenDecimal, "locked vesting amount per period")
+	if err != nil {
+		return shared.LockedVestingParameters{}, err
+	}
+	cliffUnlockAmount, err := decimalToU64(vesting.CliffUnlockAmount, tokenDecimal, "locked vesting cliff unlock amount")
+	if err != nil {
+		return shared.LockedVestingParameters{}, err
+	}
+	if amountPerPeriod == 0 && vesting.Percentage.IsPositive() {
+		if !baseSupply.IsPositive() {
+			return shared.LockedVestingParameters{}, errors.New("locked vesting percentage requires a base supply")
+		}
+		if vesting.NumberOfPeriod == 0 {
+			return shared.LockedVestingParameters{}, errors.New("locked vesting percentage requires a number of periods")
+		}
+		total, err := decimalToU64(vesting.Percentage.Mul(baseSupply).Div(decimal.NewFromInt(100)).Shift(tokenDecimal).Floor(), 0, "locked vesting amount")
+		if err != nil {
+			return shared.LockedVestingParameters{}, err
+		}
+		if cliffUnlockAmount > total {
+			return shared.LockedVestingParameters{}, errors.New("locked vesting cliff unlock amount exceeds the vesting percentage")
+		}
+		amountPerPeriod = (total - cliffUnlockAmount) / vesting.NumberOfPeriod
+		cliffUnlockAmount = total - amountPerPeriod*vesting.NumberOfPeriod
+	}
+	return shared.LockedVestingParameters{
+		AmountPerPeriod:                amountPerPeriod,
+		CliffDurationFromMigrationTime: vesting.CliffDurationFromMigrationTime,
+		Frequency:                      vesting.Frequency,
+		NumberOfPeriod:                 vesting.NumberOfPeriod,
+		CliffUnlockAmount:              cliffUnlockAmount,
+	}, nil
+}
+
+// ConfigFileFromConfigParams converts params to the config file the program would store for them,
+// so an intended config can be diffed against one decoded from chain.
+func ConfigFileFromConfigParams(params shared.CreateConfigParams, quoteDecimal shared.TokenDecimal) (shared.ConfigFile, error) {
+	config, err := PoolConfigFromConfigParameters(params.ConfigParameters)
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	config.QuoteMint = params.QuoteMint
+	config.FeeClaimer = params.FeeClaimer
+	config.LeftoverReceiver = params.LeftoverReceiver
+	return ConfigFileFromPoolConfig(config, quoteDecimal)
+}
+
+// ConfigFileFromPoolConfig decodes an on-chain pool config into a config file.
+// The quote decimal is not stored in the config and comes from the quote mint.
+func ConfigFileFromPoolConfig(config *shared.PoolConfig, quoteDecimal shared.TokenDecimal) (shared.ConfigFile, error) {
+	tokenDecimal := int32(config.TokenDecimal)
+	baseFee := config.PoolFees.BaseFee
+	activationType := shared.ActivationType(config.ActivationType)
+
+	tokenType, err := enumName(tokenTypeNames, config.TokenType, "token type")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	tokenUpdateAuthority, err := enumName(tokenUpdateAuthorityNames, config.TokenUpdateAuthority, "token update authority")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	activationTypeName, err := enumName(activationTypeNames, config.ActivationType, "activation type")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	collectFeeMode, err := enumName(collectFeeModeNames, config.CollectFeeMode, "collect fee mode")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	baseFeeMode, err := enumName(baseFeeModeNames, baseFee.BaseFeeMode, "base fee mode")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	migrationOption, err := enumName(migrationOptionNames, config.MigrationOption, "migration option")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	migrationFeeOption, err := enumName(migrationFeeOptionNames, config.MigrationFeeOption, "migration fee option")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	migratedPoolBaseFeeMode, err := enumName(dammV2BaseFeeModeNames, config.MigratedPoolBaseFeeMode, "migrated pool base fee mode")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	migratedCollectFeeMode, err := enumName(collectFeeModeNames, config.MigratedCollectFeeMode, "migrated pool collect fee mode")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+	migratedDynamicFee, err := enumName(dammV2DynamicFeeModeNames, config.MigratedDynamicFee, "migrated pool dynamic fee")
+	if err != nil {
+		return shared.ConfigFile{}, err
+	}
+
+	file := shared.ConfigFile{
+		QuoteMint:            config.QuoteMint,
+		FeeClaimer:           config.FeeClaimer,
+		LeftoverReceiver:     config.LeftoverReceiver,
+		TokenType:            tokenType,
+		TokenDecimal:         shared.TokenDecimal(config.TokenDecimal),
+		QuoteDecimal:         quoteDecimal,
+		TokenUpdateAuthority: tokenUpdateAuthority,
+		ActivationType:       activationTypeName,
+		CollectFeeMode:       collectFeeMode,
+		BaseFee: shared.ConfigFileBaseFee{
+			BaseFeeMode: baseFeeMode,
+			CliffFeeBps: decimalFromUint64(baseFee.CliffFeeNumerator).Shift(-feeNumeratorBpsShift),
+		},
+		CreatorTradingFeePercentage: config.CreatorTradingFeePercentage,
+		EnableFirstSwapWithMinFee:   config.EnableFirstSwapWithMinFee == 1,
+		PoolCreationFee:             decimalFromUint64(config.PoolCreationFee).Shift(-9),
+		LockedVesting: shared.ConfigFileLockedVesting{
+			AmountPerPeriod:                decimalFromUint64(config.LockedVestingConfig.AmountPerPeriod).Shift(-tokenDecimal),
+			NumberOfPeriod:                 config.LockedVestingConfig.NumberOfPeriod,
+			Frequency:                      config.LockedVestingConfig.Frequency,
+			CliffUnlockAmount:              decimalFromUint64(config.LockedVestingConfig.CliffUnlockAmount).Shift(-tokenDecimal),
+			CliffDurationFromMigrationTime: config.LockedVestingConfig.CliffDurationFromMigrationTime,
+		},
+		SqrtStartPrice:          decimal.NewFromBigInt(config.SqrtStartPrice.BigInt(), 0),
+		MigrationQuoteThreshold: decimalFromUint64(config.MigrationQuoteThreshold).Shift(-int32(quoteDecimal)),
+		MigrationOption:         migrationOption,
+		MigrationFeeOption:      migrationFeeOption,
+		MigrationFee: shared.MigrationFee{
+			FeePercentage:        config.MigrationFeePercentage,
+			CreatorFeePercentage: config.CreatorMigrationFeePercentage,
+		},
+		PartnerLiquidityPercentage:                config.PartnerLiquidityPercentage,
+		PartnerPermanentLockedLiquidityPercentage: config.PartnerPermanentLockedLiquidityPercentage,
+		CreatorLiquidityPercentage:                config.CreatorLiquidityPercentage,
+		CreatorPermanentLockedLiquidityPercentage: config.CreatorPermanentLockedLiquidityPercentage,
+		PartnerLiquidityVesting:                   liquidityVestingInfoParameters(config.PartnerLiquidityVestingInfo),
+		CreatorLiquidityVesting:                   liquidityVestingInfoParameters(config.CreatorLiquidityVestingInfo),
+		MigratedPoolFee: shared.ConfigFileMigratedPoolFee{
+			CollectFeeMode: migratedCollectFeeMode,
+			DynamicFee:     migratedDynamicFee,
+			PoolFeeBps:     config.MigratedPoolFeeBps,
+		},
+		MigratedPoolBaseFeeMode:           migratedPoolBaseFeeMode,
+		MigratedPoolMarketCapFeeScheduler: decodeMigratedPoolBaseFeeBytes(config.MigratedPoolBaseFeeBytes),
+	}
+
+	if shared.BaseFeeMode(baseFee.BaseFeeMode) == shared.BaseFeeModeRateLimiter {
+		referenceAmount := decimalFromUint64(baseFee.ThirdFactor).Shift(-int32(quoteDecimal))
+		maxLimiterDuration := pointsToSeconds(baseFee.SecondFactor, activationType)
+		file.BaseFee.FeeIncrementBps = baseFee.FirstFactor
+		file.BaseFee.MaxLimiterDuration = &maxLimiterDuration
+		file.BaseFee.ReferenceAmount = &referenceAmount
+	} else {
+		periodFrequency := pointsToSeconds(baseFee.SecondFactor, activationType)
+		file.BaseFee.NumberOfPeriod = baseFee.FirstFactor
+		file.BaseFee.PeriodFrequency = &periodFrequency
+		file.BaseFee.ReductionFactor = baseFee.ThirdFactor
+	}
+
+	if dynamicFee := config.PoolFees.DynamicFee; dynamicFee.Initialized == 1 {
+		file.DynamicFee = &shared.DynamicFeeParameters{
+			BinStep:                  dynamicFee.BinStep,
+			BinStepU128:              dynamicFee.BinStepU128,
+			FilterPeriod:             dynamicFee.FilterPeriod,
+			DecayPeriod:              dynamicFee.DecayPeriod,
+			ReductionFactor:          dynamicFee.ReductionFactor,
+			MaxVolatilityAccumulator: dynamicFee.MaxVolatilityAccumulator,
+			VariableFeeControl:       dynamicFee.VariableFeeControl,
+		}
+	}
+	if config.FixedTokenSupplyFlag == 1 {
+		file.TokenSupply = &shared.ConfigFileTokenSupply{
+			PreMigrationTokenSupply:  decimalFromUint64(config.PreMigrationTokenSupply).Shift(-tokenDecimal),
+			PostMigrationTokenSupply: decimalFromUint64(config.PostMigrationTokenSupply).Shift(-tokenDecimal),
+		}
+	}
+
+	baseDecimal := uint8(config.TokenDecimal)
+	file.BaseSupply = decimal.NewFromBigInt(initialBaseSupply(config), -tokenDecimal)
+	lowerSqrtPrice := config.SqrtStartPrice.BigInt()
+	for _, point := range config.Curve {
+		sqrtPrice := point.SqrtPrice.BigInt()
+		if sqrtPrice.Sign() == 0 {
+			break
+		}
+		baseAmount := big.NewInt(0)
+		if sqrtPrice.Cmp(lowerSqrtPrice) > 0 {
+			baseAmount, err = GetDeltaAmountBaseUnsigned(lowerSqrtPrice, sqrtPrice, point.Liquidity.BigInt(), shared.RoundingDown)
+			if err != nil {
+				return shared.ConfigFile{}, err
+			}
+		}
+		price := configFilePrice(sqrtPrice, baseDecimal, uint8(quoteDecimal))
+		file.Curve = append(file.Curve, shared.ConfigFileCurvePoint{
+			SqrtPrice:  decimal.NewFromBigInt(sqrtPrice, 0),
+			Liquidity:  decimal.NewFromBigInt(point.Liquidity.BigInt(), 0),
+			Price:      price,
+			MarketCap:  price.Mul(file.BaseSupply),
+			BaseAmount: decimal.NewFromBigInt(baseAmount, -tokenDecimal),
+		})
+		lowerSqrtPrice = sqrtPrice
+	}
+	file.InitialPrice = configFilePrice(config.SqrtStartPrice.BigInt(), baseDecimal, uint8(quoteDecimal))
+	file.InitialMarketCap = file.InitialPrice.Mul(file.BaseSupply)
+	file.MigrationPrice = configFilePrice(config.MigrationSqrtPrice.BigInt(), baseDecimal, uint8(quoteDecimal))
+	file.MigrationMarketCap = file.MigrationPrice.Mul(file.BaseSupply)
+	if !file.BaseSupply.IsZero() {
+		vesting := decimal.NewFromBigInt(lockedVestingAmount(config), -tokenDecimal)
+		file.LockedVesting.Percentage = vesting.Mul(decimal.NewFromInt(100)).Div(file.BaseSupply)
+	}
+	return file, nil
+}
+
+func baseFeeFromConfigFile(baseFee shared.ConfigFileBaseFee, activationType shared.ActivationType, quoteDecimal int32) (shared.BaseFeeParameters, error) {
+	baseFeeMode, err := enumValue(baseFeeModeNames, baseFee.BaseFeeMode, "base fee mode")
+	if err != nil {
+		return shared.BaseFeeParameters{}, err
+	}
+	cliffFeeNumerator, err := decimalToU64(baseFee.CliffFeeBps, feeNumeratorBpsShift, "cliff fee bps")
+	if err != nil {
+		return shared.BaseFeeParameters{}, err
+	}
+	params := shared.BaseFeeParameters{CliffFeeNumerator: cliffFeeNumerator, BaseFeeMode: baseFeeMode}
+	if shared.BaseFeeMode(baseFeeMode) == shared.BaseFeeModeRateLimiter {
+		if baseFee.ReferenceAmount == nil {
+			return shared.BaseFeeParameters{}, errors.New("rate limiter reference amount is required")
+		}
+		referenceAmount, err := decimalToU64(*baseFee.ReferenceAmount, quoteDecimal, "rate limiter reference amount")
+		if err != nil {
+			return shared.BaseFeeParameters{}, err
+		}
+		maxLimiterDuration, err := secondsToPoints(baseFee.MaxLimiterDuration, activationType, "rate limiter max duration")
+		if err != nil {
+			return shared.BaseFeeParameters{}, err
+		}
+		params.FirstFactor = baseFee.FeeIncrementBps
+		params.SecondFactor = maxLimiterDuration
+		params.ThirdFactor = referenceAmount
+		return params, nil
+	}
+	periodFrequency, err := secondsToPoints(baseFee.PeriodFrequency, activationType, "base fee period frequency")
+	if err != nil {
+		return shared.BaseFeeParameters{}, err
+	}
+	params.FirstFactor = baseFee.NumberOfPeriod
+	params.SecondFactor = periodFrequency
+	params.ThirdFactor = baseFee.ReductionFactor
+	return params, nil
+}
+
+// secondsToPoints converts a duration in seconds to activation points, slots or seconds by
+// activation type. A nil duration is zero.
+func secondsToPoints(seconds *decimal.Decimal, activationType shared.ActivationType, name string) (uint64, error) {
+	if seconds == nil {
+		return 0, nil
+	}
+	points := *seconds
+	unit := "seconds"
+	if activationType == shared.ActivationTypeSlot {
+		points = points.Shift(3).Div(decimal.NewFromInt(shared.SlotDurationMillis))
+		unit = "slots"
+	}
+	if points.IsNegative() {
+		return 0, fmt.Errorf("%s must not be negative", name)
+	}
+	if !points.IsInteger() {
+		return 0, fmt.Errorf("%s must be a whole number of %s", name, unit)
+	}
+	return shared.CheckedU64(points.BigInt(), name)
+}
+
+// pointsToSeconds converts a duration in activation points to seconds.
+func pointsToSeconds(points uint64, activationType shared.ActivationType) decimal.Decimal {
+	seconds := decimalFromUint64(points)
+	if activationType == shared.ActivationTypeSlot {
+		seconds = seconds.Mul(decimal.NewFromInt(shared.SlotDurationMillis)).Shift(-3)
+	}
+	return seconds
+}
+
+// configFilePrice is the price of sqrtPrice kept to configFilePricePrecision decimal places.
+func configFilePrice(sqrtPrice *big.Int, baseDecimal, quoteDecimal uint8) decimal.Decimal {
+	decSqrt := decimal.NewFromBigInt(sqrtPrice, 0)
+	return decSqrt.Mul(decSqrt).
+		Mul(decimal.New(1, int32(baseDecimal)-int32(quoteDecimal))).
+		DivRound(decimal.NewFromBigInt(new(big.Int).Lsh(big.NewInt(1), 128), 0), configFilePricePrecision)
+}
+
+func enumName(names []string, value uint8, field string) (string, error) {
+	if int(value) >= len(names) {
+		return "", fmt.Errorf("unknown %s %d", field, value)
+	}
+	return names[value], nil
+}
+
+func enumValue(names []string, name, field string) (uint8, error) {
+	for i, n := range names {
+		if n == name {
+			return uint8(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", field, name)
+}
+
+func liquidityVestingInfoParameters(info shared.LiquidityVestingInfo) shared.LiquidityVestingInfoParameters {
+	return shared.LiquidityVestingInfoParameters{
+		VestingPercentage:              info.VestingPercentage,
+		BpsPerPeriod:                   info.BpsPerPeriod,
+		NumberOfPeriods:                info.NumberOfPeriods,
+		CliffDurationFromMigrationTime: info.CliffDurationFromMigrationTime,
+		Frequency:                      info.Frequency,
+	}
+}
+
+func liquidityVestingInfo(params shared.LiquidityVestingInfoParameters) shared.LiquidityVestingInfo {
+	info := shared.LiquidityVestingInfo{
+		VestingPercentage:              params.VestingPercentage,
+		BpsPerPeriod:                   params.BpsPerPeriod,
+		NumberOfPeriods:                params.NumberOfPeriods,
+		CliffDurationFromMigrationTime: params.CliffDurationFromMigrationTime,
+		Frequency:                      params.Frequency,
+	}
+	if params.VestingPercentage > 0 {
+		info.IsInitialized = 1
+	}
+	return info
+}
+
+// encodeMigratedPoolBaseFeeBytes packs the market cap fee scheduler the way the pool config stores it.
+func encodeMigratedPoolBaseFeeBytes(params shared.MigratedPoolMarketCapFeeSchedulerParameters) [16]uint8 {
+	var out [16]uint8
+	binary.LittleEndian.PutUint16(out[0:2], params.NumberOfPeriod)
+	binary.LittleEndian.PutUint16(out[2:4], params.SqrtPriceStepBps)
+	binary.LittleEndian.PutUint32(out[4:8], params.SchedulerExpirationDuration)
+	binary.LittleEndian.PutUint64(out[8:16], params.ReductionFactor)
+	return out
+}
+
+func decodeMigratedPoolBaseFeeBytes(data [16]uint8) shared.MigratedPoolMarketCapFeeSchedulerParameters {
+	return shared.MigratedPoolMarketCapFeeSchedulerParameters{
+		NumberOfPeriod:              binary.LittleEndian.Uint16(data[0:2]),
+		SqrtPriceStepBps:            binary.LittleEndian.Uint16(data[2:4]),
+		SchedulerExpirationDuration: binary.LittleEndian.Uint32(data[4:8]),
+		ReductionFactor:             binary.LittleEndian.Uint64(data[8:16]),
+	}
+}
+
+// decimalToU64 scales value by 10^shift and requires a whole u64 result.
+func decimalToU64(value decimal.Decimal, shift int32, name string) (uint64, error) {
+	scaled, err := decimalToBig(value.Shift(shift), name)
+	if err != nil {
+		return 0, err
+	}
+	return shared.CheckedU64(scaled, name)
+}
+
+func decimalToBig(value decimal.Decimal, name string) (*big.Int, error) {
+	if value.IsNegative() {
+		return nil, fmt.Errorf("%s must not be negative", name)
+	}
+	if !value.IsInteger() {
+		return nil, fmt.Errorf("%s must be a whole number of base units", name)
+	}
+	return value.BigInt(), nil
+}
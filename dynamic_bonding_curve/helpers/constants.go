@@ -22,6 +22,7 @@ const (
 	AccountKeyPoolConfig                   = "PoolConfig"
 	AccountKeyVirtualPool                  = "VirtualPool"
 	AccountKeyVirtualPoolMetadata          = "VirtualPoolMetadata"
+	AccountKeyVestingEscrow                = "VestingEscrow"
 )
 
 var (
@@ -31,7 +32,9 @@ var (
 	DammV2ProgramID              = dammv2gen.ProgramID
 	VaultProgramID               = vaultgen.ProgramID
 	LockerProgramID              = solanago.MustPublicKeyFromBase58("LocpQgucEQHbqNABEYvBvwoxCPsSbG91A1QaQhQQqjn")
+	MemoProgramID                = solanago.MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
 	BaseAddress                  = solanago.MustPublicKeyFromBase58("HWzXGcGHy4tcpYfaRDCyLNzXqBTv3E6BttpCH2vJxArv")
+	ProtocolTreasury             = solanago.MustPublicKeyFromBase58("6aYhxiNGmG8AyU25rh2R7iFu4pBrqnQHpNUGhmsEXRcm")
 
 	DammV1MigrationFeeAddress = []solanago.PublicKey{
 		solanago.MustPublicKeyFromBase58("8f848CEy8eY6PhJ3VcemtBDzPPSD4Vq7aJczLZ3o8MmX"),
@@ -0,0 +1,292 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+
+	mathutil "github.com/krazyTry/meteora-go/dynamic_bonding_curve/math"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+	"github.com/shopspring/decimal"
+)
+
+// curveDesignTrial is one builder call DesignCurve tries.
+type curveDesignTrial struct {
+	builder shared.CurveBuilder
+	build   func() (shared.ConfigParameters, error)
+}
+
+// DesignCurve searches the curve builders and their parameters for configs meeting goals and returns
+// the candidates ranked best first: valid configs before invalid ones, then those within the price impact
+// limit, then by score. The market cap builders need both market caps; BuildCurve needs a target raise
+// or a migration market cap.
+func DesignCurve(goals shared.CurveDesignGoals) ([]shared.CurveCandidate, error) {
+	if goals.TotalTokenSupply == 0 {
+		return nil, errors.New("totalTokenSupply must be greater than zero")
+	}
+	hasMarketCaps := goals.InitialMarketCap > 0 && goals.MigrationMarketCap > 0
+	if hasMarketCaps && goals.InitialMarketCap >= goals.MigrationMarketCap {
+		return nil, errors.New("initial market cap must be less than migration market cap")
+	}
+	if !hasMarketCaps && goals.TargetRaise <= 0 && goals.MigrationMarketCap <= 0 {
+		return nil, errors.New("goals need market caps, a migration market cap or a target raise")
+	}
+
+	base := goals.BuildCurveBaseParams
+	if goals.VestingPercentage > 0 {
+		// without vesting periods the whole amount unlocks at the cliff.
+		base.LockedVestingParams.TotalLockedVestingAmount = uint64(float64(goals.TotalTokenSupply) * goals.VestingPercentage / 100)
+		if base.LockedVestingParams.NumberOfVestingPeriod == 0 {
+			base.LockedVestingParams.CliffUnlockAmount = base.LockedVestingParams.TotalLockedVestingAmount
+		}
+	}
+
+	trials := curveDesignTrials(goals, base, hasMarketCaps)
+	candidates := make([]shared.CurveCandidate, 0, len(trials))
+	for _, trial := range trials {
+		config, err := trial.build()
+		if err != nil {
+			continue
+		}
+		candidate, err := measureCurveCandidate(goals, trial.builder, config)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("no curve builder produced a config for the goals")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.ValidationError == nil) != (b.ValidationError == nil) {
+			return a.ValidationError == nil
+		}
+		if a.MeetsPriceImpact != b.MeetsPriceImpact {
+			return a.MeetsPriceImpact
+		}
+		return a.Score < b.Score
+	})
+	if goals.MaxCandidates > 0 && len(candidates) > goals.MaxCandidates {
+		candidates = candidates[:goals.MaxCandidates]
+	}
+	return candidates, nil
+}
+
+func curveDesignTrials(goals shared.CurveDesignGoals, base shared.BuildCurveBaseParams, hasMarketCaps bool) []curveDesignTrial {
+	var trials []curveDesignTrial
+	percentages := migrationPercentageGrid(goals, base)
+
+	for _, percentage := range percentages {
+		threshold := goals.TargetRaise
+		if threshold <= 0 {
+			migrationQuoteAmount := GetMigrationQuoteAmount(decimalFromFloat(goals.MigrationMarketCap), decimalFromFloat(percentage))
+			threshold = GetMigrationQuoteThresholdFromMigrationQuoteAmount(migrationQuoteAmount, decimalFromUint64(uint64(base.MigrationFee.FeePercentage))).InexactFloat64()
+		}
+		if threshold <= 0 {
+			continue
+		}
+		params := shared.BuildCurveParams{BuildCurveBaseParams: base, PercentageSupplyOnMigration: percentage, MigrationQuoteThreshold: threshold}
+		trials = append(trials, curveDesignTrial{shared.CurveBuilderBuildCurve, func() (shared.ConfigParameters, error) {
+			return BuildCurve(params)
+		}})
+	}
+	if !hasMarketCaps {
+		return trials
+	}
+
+	marketCapParams := shared.BuildCurveWithMarketCapParams{BuildCurveBaseParams: base, InitialMarketCap: goals.InitialMarketCap, MigrationMarketCap: goals.MigrationMarketCap}
+	trials = append(trials, curveDesignTrial{shared.CurveBuilderWithMarketCap, func() (shared.ConfigParameters, error) {
+		return BuildCurveWithMarketCap(marketCapParams)
+	}})
+
+	for _, percentage := range percentages {
+		params := shared.BuildCurveWithTwoSegmentsParams{
+			BuildCurveBaseParams:        base,
+			InitialMarketCap:            goals.InitialMarketCap,
+			MigrationMarketCap:          goals.MigrationMarketCap,
+			PercentageSupplyOnMigration: percentage,
+		}
+		trials = append(trials, curveDesignTrial{shared.CurveBuilderWithTwoSegments, func() (shared.ConfigParameters, error) {
+			return BuildCurveWithTwoSegments(params)
+		}})
+	}
+
+	// the mid price is a whole quote amount, so it only fits tokens priced above one quote unit.
+	supply := float64(goals.TotalTokenSupply)
+	midPrice := uint64(math.Sqrt(goals.InitialMarketCap / supply * goals.MigrationMarketCap / supply))
+	if midPrice > 0 && float64(midPrice) > goals.InitialMarketCap/supply {
+		seen := map[uint64]bool{}
+		for _, percentage := range percentages {
+			rounded := uint64(math.Round(percentage))
+			if rounded == 0 || seen[rounded] {
+				continue
+			}
+			seen[rounded] = true
+			params := shared.BuildCurveWithMidPriceParams{
+				BuildCurveBaseParams:        base,
+				InitialMarketCap:            goals.InitialMarketCap,
+				MigrationMarketCap:          goals.MigrationMarketCap,
+				MidPrice:                    midPrice,
+				PercentageSupplyOnMigration: rounded,
+			}
+			trials = append(trials, curveDesignTrial{shared.CurveBuilderWithMidPrice, func() (shared.ConfigParameters, error) {
+				return BuildCurveWithMidPrice(params)
+			}})
+		}
+	}
+
+	// geometric weights put more liquidity towards the end of the curve when the ratio is above one.
+	for _, ratio := range []float64{0.8, 0.9, 1, 1.1, 1.2, 1.5} {
+		weights := make([]float64, 16)
+		for i := range weights {
+			weights[i] = math.Pow(ratio, float64(i))
+		}
+		params := shared.BuildCurveWithLiquidityWeightsParams{
+			BuildCurveBaseParams: base,
+			InitialMarketCap:     goals.InitialMarketCap,
+			MigrationMarketCap:   goals.MigrationMarketCap,
+			LiquidityWeights:     weights,
+		}
+		trials = append(trials, curveDesignTrial{shared.CurveBuilderWithLiquidityWeights, func() (shared.ConfigParameters, error) {
+			return BuildCurveWithLiquidityWeights(params)
+		}})
+	}
+
+	sqrtPrices, err := geometricSqrtPrices(goals, 8)
+	if err != nil {
+		return trials
+	}
+	segments := len(sqrtPrices) - 1
+	increasing := make([]uint64, segments)
+	decreasing := make([]uint64, segments)
+	for i := 0; i < segments; i++ {
+		increasing[i] = uint64(i + 1)
+		decreasing[i] = uint64(segments - i)
+	}
+	for _, weights := range [][]uint64{nil, increasing, decreasing} {
+		params := shared.BuildCurveWithCustomSqrtPricesParams{BuildCurveBaseParams: base, SqrtPrices: sqrtPrices, LiquidityWeights: weights}
+		trials = append(trials, curveDesignTrial{shared.CurveBuilderWithCustomSqrtPrices, func() (shared.ConfigParameters, error) {
+			return BuildCurveWithCustomSqrtPrices(params)
+		}})
+	}
+	return trials
+}
+
+// migrationPercentageGrid returns the supply percentages on migration to try. A percentage sold goal
+// leaves the rest of the supply, less vesting and leftover, for migration.
+func migrationPercentageGrid(goals shared.CurveDesignGoals, base shared.BuildCurveBaseParams) []float64 {
+	if goals.PercentageSoldOnCurve <= 0 {
+		return []float64{10, 20, 30, 40, 50}
+	}
+	supply := float64(base.TotalTokenSupply)
+	vesting := float64(base.LockedVestingParams.TotalLockedVestingAmount) * 100 / supply
+	leftover := float64(base.Leftover) * 100 / supply
+	target := 100 - goals.PercentageSoldOnCurve - vesting - leftover
+
+	var grid []float64
+	for _, offset := range []float64{-10, -5, 0, 5, 10} {
+		if percentage := target + offset; percentage > 0 && percentage < 100 {
+			grid = append(grid, percentage)
+		}
+	}
+	return grid
+}
+
+// geometricSqrtPrices spaces segments+1 sqrt prices geometrically from the initial to the migration market cap.
+func geometricSqrtPrices(goals shared.CurveDesignGoals, segments int) ([]*big.Int, error) {
+	ratio := goals.MigrationMarketCap / goals.InitialMarketCap
+	sqrtPrices := make([]*big.Int, 0, segments+1)
+	for i := 0; i <= segments; i++ {
+		marketCap := goals.InitialMarketCap * math.Pow(ratio, float64(i)/float64(segments))
+		sqrtPrice, err := GetSqrtPriceFromMarketCap(marketCap, goals.TotalTokenSupply, goals.TokenBaseDecimal, goals.TokenQuoteDecimal)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(sqrtPrices); n > 0 && sqrtPrice.Cmp(sqrtPrices[n-1]) <= 0 {
+			return nil, errors.New("market caps are too close for custom sqrt prices")
+		}
+		sqrtPrices = append(sqrtPrices, sqrtPrice)
+	}
+	return sqrtPrices, nil
+}
+
+// measureCurveCandidate reports what config achieves against goals and validates it.
+func measureCurveCandidate(goals shared.CurveDesignGoals, builder shared.CurveBuilder, config shared.ConfigParameters) (shared.CurveCandidate, error) {
+	poolConfig, err := PoolConfigFromConfigParameters(config)
+	if err != nil {
+		return shared.CurveCandidate{}, err
+	}
+	sqrtStartPrice := config.SqrtStartPrice.BigInt()
+	migrationSqrtPrice := poolConfig.MigrationSqrtPrice.BigInt()
+	swapBaseAmount, err := GetBaseTokenForSwap(sqrtStartPrice, migrationSqrtPrice, config.Curve)
+	if err != nil {
+		return shared.CurveCandidate{}, err
+	}
+
+	baseDecimal := uint8(goals.TokenBaseDecimal)
+	quoteDecimal := uint8(goals.TokenQuoteDecimal)
+	supply := decimalFromUint64(goals.TotalTokenSupply)
+	supplyLamports := supply.Shift(int32(baseDecimal))
+	hundred := decimal.NewFromInt(100)
+
+	candidate := shared.CurveCandidate{
+		Builder:               builder,
+		Config:                config,
+		InitialMarketCap:      getPriceFromSqrtPrice(sqrtStartPrice, baseDecimal, quoteDecimal).Mul(supply),
+		MigrationMarketCap:    getPriceFromSqrtPrice(migrationSqrtPrice, baseDecimal, quoteDecimal).Mul(supply),
+		Raise:                 decimalFromUint64(config.MigrationQuoteThreshold).Shift(-int32(quoteDecimal)),
+		PercentageSoldOnCurve: decimal.NewFromBigInt(swapBaseAmount, 0).Mul(hundred).Div(supplyLamports),
+		VestingPercentage:     decimal.NewFromBigInt(GetTotalVestingAmount(config.LockedVesting), 0).Mul(hundred).Div(supplyLamports),
+		MeetsPriceImpact:      true,
+	}
+	if goals.ImpactBuyAmount > 0 {
+		candidate.PriceImpact, err = firstBuyPriceImpact(poolConfig, goals.ImpactBuyAmount, goals.TokenQuoteDecimal)
+		if err != nil {
+			return shared.CurveCandidate{}, err
+		}
+		candidate.MeetsPriceImpact = goals.MaxPriceImpact <= 0 || candidate.PriceImpact.InexactFloat64() <= goals.MaxPriceImpact
+	}
+
+	candidate.Score = relativeError(candidate.InitialMarketCap, goals.InitialMarketCap) +
+		relativeError(candidate.MigrationMarketCap, goals.MigrationMarketCap) +
+		relativeError(candidate.Raise, goals.TargetRaise) +
+		relativeError(candidate.PercentageSoldOnCurve, goals.PercentageSoldOnCurve) +
+		relativeError(candidate.VestingPercentage, goals.VestingPercentage)
+	candidate.ValidationError = ValidateConfigParameters(shared.CreateConfigParams{ConfigParameters: config, LeftoverReceiver: goals.LeftoverReceiver})
+	return candidate, nil
+}
+
+// firstBuyPriceImpact is the price increase in percent of buying amount quote from a fresh pool of config.
+func firstBuyPriceImpact(config *shared.PoolConfig, amount float64, quoteDecimal shared.TokenDecimal) (decimal.Decimal, error) {
+	amountIn, err := lamportsFromDecimal(decimalFromFloat(amount), quoteDecimal)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	pool := &shared.VirtualPool{SqrtPrice: config.SqrtStartPrice}
+	tradeDirection := shared.TradeDirectionQuoteToBase
+	feeMode := mathutil.GetFeeMode(shared.CollectFeeMode(config.CollectFeeMode), tradeDirection, false)
+	currentPoint := big.NewInt(0)
+
+	result, err := mathutil.GetSwapResultFromExactInput(pool, config, amountIn, feeMode, tradeDirection, currentPoint, false)
+	if err != nil {
+		// a buy larger than the curve stops at the migration price.
+		result, err = mathutil.GetSwapResultFromPartialInput(pool, config, amountIn, feeMode, tradeDirection, currentPoint, false)
+		if err != nil {
+			return decimal.Zero, err
+		}
+	}
+	startSqrtPrice := decimal.NewFromBigInt(config.SqrtStartPrice.BigInt(), 0)
+	nextSqrtPrice := decimal.NewFromBigInt(result.NextSqrtPrice, 0)
+	ratio := nextSqrtPrice.Div(startSqrtPrice)
+	return ratio.Mul(ratio).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)), nil
+}
+
+func relativeError(achieved decimal.Decimal, goal float64) float64 {
+	if goal <= 0 {
+		return 0
+	}
+	return math.Abs(achieved.InexactFloat64()-goal) / goal
+}
@@ -0,0 +1,270 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	mathutil "github.com/krazyTry/meteora-go/dynamic_bonding_curve/math"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+	"github.com/shopspring/decimal"
+)
+
+// ExportCurve samples the curve of config from the start price to the migration price with the
+// on-chain delta amount math: base sold rounds down and quote raised rounds up, as for a buy.
+func ExportCurve(config *shared.PoolConfig, params shared.CurveExportParams) (shared.CurveDataset, error) {
+	if params.SamplesPerSegment <= 0 {
+		return shared.CurveDataset{}, errors.New("samples per segment must be greater than 0")
+	}
+	migrationSqrtPrice := config.MigrationSqrtPrice.BigInt()
+	if migrationSqrtPrice.Sign() == 0 {
+		return shared.CurveDataset{}, errors.New("migration sqrt price is not set")
+	}
+
+	baseDecimal := uint8(config.TokenDecimal)
+	quoteDecimal := uint8(params.QuoteDecimal)
+	supply := decimal.NewFromBigInt(initialBaseSupply(config), -int32(baseDecimal))
+	dataset := shared.CurveDataset{
+		BaseSupply:          supply,
+		MigrationBaseAmount: decimal.NewFromBigInt(new(big.Int).SetUint64(config.MigrationBaseThreshold), -int32(baseDecimal)),
+		LockedVesting:       decimal.NewFromBigInt(lockedVestingAmount(config), -int32(baseDecimal)),
+	}
+	if !supply.IsZero() {
+		dataset.LockedVestingPercentage = dataset.LockedVesting.Mul(decimal.NewFromInt(100)).Div(supply)
+	}
+
+	sample := func(segment int, sqrtPrice, baseSold, quoteRaised *big.Int) shared.CurveSample {
+		price := getPriceFromSqrtPrice(sqrtPrice, baseDecimal, quoteDecimal)
+		out := shared.CurveSample{
+			Segment:     segment,
+			SqrtPrice:   sqrtPrice,
+			Price:       price,
+			MarketCap:   price.Mul(supply),
+			BaseSold:    decimal.NewFromBigInt(baseSold, -int32(baseDecimal)),
+			QuoteRaised: decimal.NewFromBigInt(quoteRaised, -int32(quoteDecimal)),
+		}
+		if !supply.IsZero() {
+			out.SupplySoldPercentage = out.BaseSold.Mul(decimal.NewFromInt(100)).Div(supply)
+		}
+		return out
+	}
+
+	lower := config.SqrtStartPrice.BigInt()
+	baseSold := big.NewInt(0)
+	quoteRaised := big.NewInt(0)
+	start := sample(0, lower, baseSold, quoteRaised)
+	start.SegmentBoundary = true
+	dataset.Samples = append(dataset.Samples, start)
+
+	samples := big.NewInt(int64(params.SamplesPerSegment))
+	for i, point := range config.Curve {
+		upper := point.SqrtPrice.BigInt()
+		if upper.Sign() == 0 || lower.Cmp(migrationSqrtPrice) >= 0 {
+			break
+		}
+		last := upper.Cmp(migrationSqrtPrice) >= 0
+		if last {
+			upper = migrationSqrtPrice
+		}
+		liquidity := point.Liquidity.BigInt()
+		step := new(big.Int).Sub(upper, lower)
+
+		var segmentBase, segmentQuote *big.Int
+		for k := 1; k <= params.SamplesPerSegment; k++ {
+			sqrtPrice := new(big.Int).Mul(step, big.NewInt(int64(k)))
+			sqrtPrice.Div(sqrtPrice, samples).Add(sqrtPrice, lower)
+			base, err := mathutil.GetDeltaAmountBaseUnsigned(lower, sqrtPrice, liquidity, shared.RoundingDown)
+			if err != nil {
+				return shared.CurveDataset{}, err
+			}
+			quote, err := mathutil.GetDeltaAmountQuoteUnsigned(lower, sqrtPrice, liquidity, shared.RoundingUp)
+			if err != nil {
+				return shared.CurveDataset{}, err
+			}
+			segmentBase, segmentQuote = base, quote
+			s := sample(i, sqrtPrice, new(big.Int).Add(baseSold, base), new(big.Int).Add(quoteRaised, quote))
+			s.SegmentBoundary = k == params.SamplesPerSegment
+			s.Migration = last && k == params.SamplesPerSegment
+			dataset.Samples = append(dataset.Samples, s)
+		}
+		baseSold.Add(baseSold, segmentBase)
+		quoteRaised.Add(quoteRaised, segmentQuote)
+		lower = upper
+		if last {
+			break
+		}
+	}
+	if !dataset.Samples[len(dataset.Samples)-1].Migration {
+		return shared.CurveDataset{}, errors.New("curve ends below the migration price")
+	}
+	return dataset, nil
+}
+
+// WriteCurveCSV writes one row per sample with a header row.
+func WriteCurveCSV(w io.Writer, dataset shared.CurveDataset) error {
+	writer := csv.NewWriter(w)
+	header := []string{"segment", "sqrt_price", "price", "market_cap", "base_sold", "quote_raised", "supply_sold_percentage", "segment_boundary", "migration"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, s := range dataset.Samples {
+		record := []string{
+			strconv.Itoa(s.Segment),
+			s.SqrtPrice.String(),
+			s.Price.String(),
+			s.MarketCap.String(),
+			s.BaseSold.String(),
+			s.QuoteRaised.String(),
+			s.SupplySoldPercentage.String(),
+			strconv.FormatBool(s.SegmentBoundary),
+			strconv.FormatBool(s.Migration),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCurveJSON writes the dataset as indented JSON.
+func WriteCurveJSON(w io.Writer, dataset shared.CurveDataset) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dataset)
+}
+
+const (
+	svgWidth       = 900
+	svgPanelHeight = 380
+	svgMarginLeft  = 90
+	svgMarginRight = 30
+	svgMarginTop   = 40
+	svgMarginBelow = 50
+	svgTicks       = 5
+)
+
+type svgBand struct {
+	from, to float64
+	label    string
+	color    string
+}
+
+type svgPanel struct {
+	title, xLabel, yLabel string
+	xs, ys                []float64
+	// boundaries are the x positions of the segment ends.
+	boundaries []float64
+	migration  int
+	bands      []svgBand
+}
+
+// WriteCurveSVG draws price against supply sold and market cap against quote raised as a standalone SVG.
+// Segment ends are dashed lines and the migration point is circled; the price chart also shades
+// the migration base amount and locked vesting after the supply sold on the curve.
+func WriteCurveSVG(w io.Writer, dataset shared.CurveDataset) error {
+	if len(dataset.Samples) == 0 {
+		return errors.New("dataset has no samples")
+	}
+	price := svgPanel{title: "Price vs supply sold", xLabel: "supply sold (%)", yLabel: "price", migration: -1}
+	marketCap := svgPanel{title: "Market cap vs quote raised", xLabel: "quote raised", yLabel: "market cap", migration: -1}
+	for i, s := range dataset.Samples {
+		price.xs = append(price.xs, s.SupplySoldPercentage.InexactFloat64())
+		price.ys = append(price.ys, s.Price.InexactFloat64())
+		marketCap.xs = append(marketCap.xs, s.QuoteRaised.InexactFloat64())
+		marketCap.ys = append(marketCap.ys, s.MarketCap.InexactFloat64())
+		if s.SegmentBoundary && i > 0 {
+			price.boundaries = append(price.boundaries, price.xs[i])
+			marketCap.boundaries = append(marketCap.boundaries, marketCap.xs[i])
+		}
+		if s.Migration {
+			price.migration, marketCap.migration = i, i
+		}
+	}
+	if !dataset.BaseSupply.IsZero() {
+		sold := price.xs[len(price.xs)-1]
+		migrationPercentage := dataset.MigrationBaseAmount.Mul(decimal.NewFromInt(100)).Div(dataset.BaseSupply).InexactFloat64()
+		vestingPercentage := dataset.LockedVestingPercentage.InexactFloat64()
+		price.bands = append(price.bands, svgBand{sold, sold + migrationPercentage, "migration", "#cfe8ff"})
+		if vestingPercentage > 0 {
+			price.bands = append(price.bands, svgBand{sold + migrationPercentage, sold + migrationPercentage + vestingPercentage, "locked vesting", "#ffe2c2"})
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		svgWidth, 2*svgPanelHeight, svgWidth, 2*svgPanelHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	writeSVGPanel(&b, 0, price)
+	writeSVGPanel(&b, svgPanelHeight, marketCap)
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSVGPanel(b *strings.Builder, top float64, panel svgPanel) {
+	left := float64(svgMarginLeft)
+	right := float64(svgWidth - svgMarginRight)
+	plotTop := top + svgMarginTop
+	bottom := top + svgPanelHeight - svgMarginBelow
+
+	xMax, yMax := 0.0, 0.0
+	for i := range panel.xs {
+		xMax = max(xMax, panel.xs[i])
+		yMax = max(yMax, panel.ys[i])
+	}
+	for _, band := range panel.bands {
+		xMax = max(xMax, band.to)
+	}
+	if xMax == 0 {
+		xMax = 1
+	}
+	if yMax == 0 {
+		yMax = 1
+	}
+	x := func(v float64) float64 { return left + v/xMax*(right-left) }
+	y := func(v float64) float64 { return bottom - v/yMax*(bottom-plotTop) }
+
+	fmt.Fprintf(b, `<text x="%g" y="%g" font-size="14" font-weight="bold">%s</text>`+"\n", left, top+svgMarginTop/2, panel.title)
+	for _, band := range panel.bands {
+		fmt.Fprintf(b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n", x(band.from), plotTop, x(band.to)-x(band.from), bottom-plotTop, band.color)
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle">%s</text>`+"\n", (x(band.from)+x(band.to))/2, plotTop+14, band.label)
+	}
+
+	for i := 0; i <= svgTicks; i++ {
+		xv := xMax * float64(i) / svgTicks
+		yv := yMax * float64(i) / svgTicks
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#eeeeee"/>`+"\n", left, y(yv), right, y(yv))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle">%s</text>`+"\n", x(xv), bottom+16, svgNumber(xv))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="end">%s</text>`+"\n", left-6, y(yv)+4, svgNumber(yv))
+	}
+	fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333333"/>`+"\n", left, bottom, right, bottom)
+	fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333333"/>`+"\n", left, plotTop, left, bottom)
+	fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle">%s</text>`+"\n", (left+right)/2, bottom+36, panel.xLabel)
+	fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle" transform="rotate(-90 %.2f %.2f)">%s</text>`+"\n",
+		left-70, (plotTop+bottom)/2, left-70, (plotTop+bottom)/2, panel.yLabel)
+
+	for _, boundary := range panel.boundaries {
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#999999" stroke-dasharray="4 4"/>`+"\n", x(boundary), plotTop, x(boundary), bottom)
+	}
+
+	points := make([]string, len(panel.xs))
+	for i := range panel.xs {
+		points[i] = fmt.Sprintf("%.2f,%.2f", x(panel.xs[i]), y(panel.ys[i]))
+	}
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="#1f6feb" stroke-width="2"/>`+"\n", strings.Join(points, " "))
+
+	if i := panel.migration; i >= 0 {
+		fmt.Fprintf(b, `<circle cx="%.2f" cy="%.2f" r="5" fill="#d1242f"/>`+"\n", x(panel.xs[i]), y(panel.ys[i]))
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="end" fill="#d1242f">migration</text>`+"\n", x(panel.xs[i])-8, y(panel.ys[i])+4)
+	}
+}
+
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
@@ -0,0 +1,315 @@
+package helpers
+
+import (
+	"errors"
+	"math/big"
+
+	mathutil "github.com/krazyTry/meteora-go/dynamic_bonding_curve/math"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+	dbcgen "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+	"github.com/krazyTry/meteora-go/internal/dynamicfee"
+	"github.com/shopspring/decimal"
+)
+
+// PoolConfigFromConfigParameters derives the pool config the program stores for configParams,
+// including the migration sqrt price, swap base amount and migration base threshold.
+func PoolConfigFromConfigParameters(configParams shared.ConfigParameters) (*shared.PoolConfig, error) {
+	config := &shared.PoolConfig{}
+	if len(configParams.Curve) == 0 {
+		return nil, errors.New("Curve is empty")
+	}
+	if len(configParams.Curve) > len(config.Curve) {
+		return nil, errors.New("Curve has too many points")
+	}
+
+	sqrtStartPrice := configParams.SqrtStartPrice.BigInt()
+	migrationQuoteThreshold := new(big.Int).SetUint64(configParams.MigrationQuoteThreshold)
+	migrationSqrtPrice, err := GetMigrationThresholdPrice(migrationQuoteThreshold, sqrtStartPrice, configParams.Curve)
+	if err != nil {
+		return nil, err
+	}
+	swapBaseAmount, err := GetBaseTokenForSwap(sqrtStartPrice, migrationSqrtPrice, configParams.Curve)
+	if err != nil {
+		return nil, err
+	}
+	swapBaseAmountBuffer, err := GetSwapAmountWithBuffer(swapBaseAmount, sqrtStartPrice, configParams.Curve)
+	if err != nil {
+		return nil, err
+	}
+	migrationQuoteAmount, _ := migrationQuoteAmountAndFee(migrationQuoteThreshold, configParams.MigrationFee.FeePercentage)
+	migrationBaseAmount, err := GetMigrationBaseToken(migrationQuoteAmount, migrationSqrtPrice, shared.MigrationOption(configParams.MigrationOption))
+	if err != nil {
+		return nil, err
+	}
+	swapBase, err := shared.CheckedU64(swapBaseAmountBuffer, "swap base amount")
+	if err != nil {
+		return nil, err
+	}
+	migrationBase, err := shared.CheckedU64(migrationBaseAmount, "migration base amount")
+	if err != nil {
+		return nil, err
+	}
+
+	config.PoolFees.BaseFee = shared.BaseFeeConfig{
+		CliffFeeNumerator: configParams.PoolFees.BaseFee.CliffFeeNumerator,
+		SecondFactor:      configParams.PoolFees.BaseFee.SecondFactor,
+		ThirdFactor:       configParams.PoolFees.BaseFee.ThirdFactor,
+		FirstFactor:       configParams.PoolFees.BaseFee.FirstFactor,
+		BaseFeeMode:       configParams.PoolFees.BaseFee.BaseFeeMode,
+	}
+	if dynamicFee := configParams.PoolFees.DynamicFee; dynamicFee != nil {
+		config.PoolFees.DynamicFee = shared.DynamicFeeConfig{
+			Initialized:              1,
+			MaxVolatilityAccumulator: dynamicFee.MaxVolatilityAccumulator,
+			VariableFeeControl:       dynamicFee.VariableFeeControl,
+			BinStep:                  dynamicFee.BinStep,
+			FilterPeriod:             dynamicFee.FilterPeriod,
+			DecayPeriod:              dynamicFee.DecayPeriod,
+			ReductionFactor:          dynamicFee.ReductionFactor,
+			BinStepU128:              dynamicFee.BinStepU128,
+		}
+	}
+	config.PartnerLiquidityVestingInfo = liquidityVestingInfo(configParams.PartnerLiquidityVestingInfo)
+	config.CreatorLiquidityVestingInfo = liquidityVestingInfo(configParams.CreatorLiquidityVestingInfo)
+	config.CollectFeeMode = configParams.CollectFeeMode
+	config.MigrationOption = configParams.MigrationOption
+	config.ActivationType = configParams.ActivationType
+	config.TokenDecimal = configParams.TokenDecimal
+	config.TokenType = configParams.TokenType
+	config.PartnerPermanentLockedLiquidityPercentage = configParams.PartnerPermanentLockedLiquidityPercentage
+	config.PartnerLiquidityPercentage = configParams.PartnerLiquidityPercentage
+	config.CreatorPermanentLockedLiquidityPercentage = configParams.CreatorPermanentLockedLiquidityPercentage
+	config.CreatorLiquidityPercentage = configParams.CreatorLiquidityPercentage
+	config.MigrationFeeOption = configParams.MigrationFeeOption
+	config.CreatorTradingFeePercentage = configParams.CreatorTradingFeePercentage
+	config.TokenUpdateAuthority = configParams.TokenUpdateAuthority
+	config.MigrationFeePercentage = configParams.MigrationFee.FeePercentage
+	config.CreatorMigrationFeePercentage = configParams.MigrationFee.CreatorFeePercentage
+	config.SwapBaseAmount = swapBase
+	config.MigrationQuoteThreshold = configParams.MigrationQuoteThreshold
+	config.MigrationBaseThreshold = migrationBase
+	config.MigrationSqrtPrice = BigToU128(migrationSqrtPrice)
+	config.LockedVestingConfig = dbcgen.LockedVestingConfig{
+		AmountPerPeriod:                configParams.LockedVesting.AmountPerPeriod,
+		CliffDurationFromMigrationTime: configParams.LockedVesting.CliffDurationFromMigrationTime,
+		Frequency:                      configParams.LockedVesting.Frequency,
+		NumberOfPeriod:                 configParams.LockedVesting.NumberOfPeriod,
+		CliffUnlockAmount:              configParams.LockedVesting.CliffUnlockAmount,
+	}
+	if configParams.TokenSupply != nil {
+		config.FixedTokenSupplyFlag = 1
+		config.PreMigrationTokenSupply = configParams.TokenSupply.PreMigrationTokenSupply
+		config.PostMigrationTokenSupply = configParams.TokenSupply.PostMigrationTokenSupply
+	}
+	config.MigratedCollectFeeMode = configParams.MigratedPoolFee.CollectFeeMode
+	config.MigratedDynamicFee = configParams.MigratedPoolFee.DynamicFee
+	config.MigratedPoolFeeBps = configParams.MigratedPoolFee.PoolFeeBps
+	config.MigratedPoolBaseFeeMode = configParams.MigratedPoolBaseFeeMode
+	config.MigratedPoolBaseFeeBytes = encodeMigratedPoolBaseFeeBytes(configParams.MigratedPoolMarketCapFeeSchedulerParams)
+	if configParams.EnableFirstSwapWithMinFee {
+		config.EnableFirstSwapWithMinFee = 1
+	}
+	config.PoolCreationFee = configParams.PoolCreationFee
+	config.SqrtStartPrice = configParams.SqrtStartPrice
+	for i, point := range configParams.Curve {
+		config.Curve[i] = dbcgen.LiquidityDistributionConfig{SqrtPrice: point.SqrtPrice, Liquidity: point.Liquidity}
+	}
+	return config, nil
+}
+
+// SimulateCurveFill fills a fresh pool of config with equal quote buys until the curve is complete
+// and reports every buy plus the fee, migration and surplus totals at migration.
+// The buy completing the curve is a partial fill, as it is on-chain.
+func SimulateCurveFill(config *shared.PoolConfig, params shared.SimulateCurveFillParams) (shared.CurveFillReport, error) {
+	if params.Steps <= 0 {
+		return shared.CurveFillReport{}, errors.New("steps must be greater than 0")
+	}
+	migrationQuoteThreshold := new(big.Int).SetUint64(config.MigrationQuoteThreshold)
+	if migrationQuoteThreshold.Sign() == 0 {
+		return shared.CurveFillReport{}, errors.New("migration quote threshold must be greater than 0")
+	}
+	stepAmount := new(big.Int).Add(migrationQuoteThreshold, big.NewInt(int64(params.Steps-1)))
+	stepAmount.Div(stepAmount, big.NewInt(int64(params.Steps)))
+
+	initialBaseSupply := initialBaseSupply(config)
+	baseReserve := new(big.Int).Set(initialBaseSupply)
+	quoteReserve := big.NewInt(0)
+	pool := &shared.VirtualPool{SqrtPrice: config.SqrtStartPrice, ActivationPoint: params.ActivationPoint}
+
+	tradeDirection := shared.TradeDirectionQuoteToBase
+	feeMode := mathutil.GetFeeMode(shared.CollectFeeMode(config.CollectFeeMode), tradeDirection, params.HasReferral)
+	baseDecimal := uint8(config.TokenDecimal)
+	quoteDecimal := uint8(params.QuoteDecimal)
+	supply := decimal.NewFromBigInt(initialBaseSupply, -int32(baseDecimal))
+
+	report := shared.CurveFillReport{
+		FeesInBase:        feeMode.FeesOnBaseToken,
+		InitialBaseSupply: initialBaseSupply,
+		TotalProtocolFee:  big.NewInt(0),
+		TotalPartnerFee:   big.NewInt(0),
+		TotalCreatorFee:   big.NewInt(0),
+		TotalReferralFee:  big.NewInt(0),
+	}
+
+	// fees above 99% are rejected by the program, so the curve completes within 100 times the steps.
+	maxSteps := params.Steps*100 + 1
+	for i := 0; quoteReserve.Cmp(migrationQuoteThreshold) < 0; i++ {
+		if i == maxSteps {
+			return shared.CurveFillReport{}, errors.New("curve did not complete")
+		}
+		point := params.ActivationPoint
+		if n := len(params.Timeline); n > 0 {
+			point = params.Timeline[min(i, n-1)]
+		}
+		currentPoint := new(big.Int).SetUint64(point)
+		currentTime := point
+		if shared.ActivationType(config.ActivationType) == shared.ActivationTypeSlot {
+			currentTime = point * shared.SlotDurationMillis / 1000
+		}
+		oldSqrtPrice := pool.SqrtPrice.BigInt()
+		updateVolatilityReferences(config, pool, currentTime)
+
+		result, err := mathutil.GetSwapResultFromExactInput(pool, config, stepAmount, feeMode, tradeDirection, currentPoint, false)
+		if err != nil {
+			partial, perr := mathutil.GetSwapResultFromPartialInput(pool, config, stepAmount, feeMode, tradeDirection, currentPoint, false)
+			if perr != nil {
+				return shared.CurveFillReport{}, perr
+			}
+			if partial.AmountLeft.Sign() == 0 {
+				return shared.CurveFillReport{}, err
+			}
+			result = partial
+		}
+
+		pool.SqrtPrice = BigToU128(result.NextSqrtPrice)
+		updateVolatilityAccumulator(config, pool, oldSqrtPrice, currentTime)
+		quoteReserve.Add(quoteReserve, result.ExcludedFeeInputAmount)
+		baseRemoved := new(big.Int).Set(result.OutputAmount)
+		if feeMode.FeesOnBaseToken {
+			baseRemoved.Add(baseRemoved, result.TradingFee)
+			baseRemoved.Add(baseRemoved, result.ProtocolFee)
+			baseRemoved.Add(baseRemoved, result.ReferralFee)
+		}
+		baseReserve.Sub(baseReserve, baseRemoved)
+		if baseReserve.Sign() < 0 {
+			return shared.CurveFillReport{}, errors.New("base supply is too small for the curve")
+		}
+
+		creatorFee := new(big.Int).Mul(result.TradingFee, big.NewInt(int64(config.CreatorTradingFeePercentage)))
+		creatorFee.Div(creatorFee, big.NewInt(100))
+		partnerFee := new(big.Int).Sub(result.TradingFee, creatorFee)
+		report.TotalProtocolFee.Add(report.TotalProtocolFee, result.ProtocolFee)
+		report.TotalPartnerFee.Add(report.TotalPartnerFee, partnerFee)
+		report.TotalCreatorFee.Add(report.TotalCreatorFee, creatorFee)
+		report.TotalReferralFee.Add(report.TotalReferralFee, result.ReferralFee)
+
+		price := getPriceFromSqrtPrice(result.NextSqrtPrice, baseDecimal, quoteDecimal)
+		report.Steps = append(report.Steps, shared.CurveFillStep{
+			Point:       point,
+			QuoteIn:     result.IncludedFeeInputAmount,
+			BaseOut:     result.OutputAmount,
+			SqrtPrice:   result.NextSqrtPrice,
+			Price:       price,
+			MarketCap:   price.Mul(supply),
+			BaseSold:    new(big.Int).Sub(initialBaseSupply, baseReserve),
+			QuoteRaised: new(big.Int).Set(quoteReserve),
+			ProtocolFee: result.ProtocolFee,
+			PartnerFee:  partnerFee,
+			CreatorFee:  creatorFee,
+			ReferralFee: result.ReferralFee,
+		})
+	}
+
+	last := report.Steps[len(report.Steps)-1]
+	report.BaseSold = last.BaseSold
+	report.QuoteRaised = last.QuoteRaised
+	report.MigrationPrice = last.Price
+	report.MigrationMarketCap = last.MarketCap
+
+	migrationQuoteAmount, migrationFee := migrationQuoteAmountAndFee(migrationQuoteThreshold, config.MigrationFeePercentage)
+	report.MigrationQuoteAmount = migrationQuoteAmount
+	report.MigrationBaseAmount = new(big.Int).SetUint64(config.MigrationBaseThreshold)
+	report.CreatorMigrationFee = new(big.Int).Mul(migrationFee, big.NewInt(int64(config.CreatorMigrationFeePercentage)))
+	report.CreatorMigrationFee.Div(report.CreatorMigrationFee, big.NewInt(100))
+	report.PartnerMigrationFee = new(big.Int).Sub(migrationFee, report.CreatorMigrationFee)
+
+	report.Surplus = new(big.Int).Sub(quoteReserve, migrationQuoteThreshold)
+	partnerAndCreatorSurplus := new(big.Int).Mul(report.Surplus, big.NewInt(shared.PartnerAndCreatorSurplusPercent))
+	partnerAndCreatorSurplus.Div(partnerAndCreatorSurplus, big.NewInt(100))
+	report.ProtocolSurplus = new(big.Int).Sub(report.Surplus, partnerAndCreatorSurplus)
+	report.CreatorSurplus = new(big.Int).Mul(partnerAndCreatorSurplus, big.NewInt(int64(config.CreatorTradingFeePercentage)))
+	report.CreatorSurplus.Div(report.CreatorSurplus, big.NewInt(100))
+	report.PartnerSurplus = new(big.Int).Sub(partnerAndCreatorSurplus, report.CreatorSurplus)
+
+	report.LockedVesting = lockedVestingAmount(config)
+	report.Leftover = new(big.Int).Sub(baseReserve, report.MigrationBaseAmount)
+	report.Leftover.Sub(report.Leftover, report.LockedVesting)
+	if report.Leftover.Sign() < 0 {
+		return shared.CurveFillReport{}, errors.New("base supply is too small for migration")
+	}
+	return report, nil
+}
+
+// initialBaseSupply is the base amount minted into a new pool of config.
+func initialBaseSupply(config *shared.PoolConfig) *big.Int {
+	if config.FixedTokenSupplyFlag == 1 {
+		return new(big.Int).SetUint64(config.PreMigrationTokenSupply)
+	}
+	total := new(big.Int).SetUint64(config.SwapBaseAmount)
+	total.Add(total, new(big.Int).SetUint64(config.MigrationBaseThreshold))
+	return total.Add(total, lockedVestingAmount(config))
+}
+
+func lockedVestingAmount(config *shared.PoolConfig) *big.Int {
+	vesting := config.LockedVestingConfig
+	total := new(big.Int).Mul(new(big.Int).SetUint64(vesting.AmountPerPeriod), new(big.Int).SetUint64(vesting.NumberOfPeriod))
+	return total.Add(total, new(big.Int).SetUint64(vesting.CliffUnlockAmount))
+}
+
+// migrationQuoteAmountAndFee splits the migration quote threshold into the quote deposited in the DAMM pool and the migration fee.
+func migrationQuoteAmountAndFee(migrationQuoteThreshold *big.Int, migrationFeePercentage uint8) (*big.Int, *big.Int) {
+	amount := new(big.Int).Mul(migrationQuoteThreshold, big.NewInt(int64(100-int(migrationFeePercentage))))
+	amount.Div(amount, big.NewInt(100))
+	return amount, new(big.Int).Sub(migrationQuoteThreshold, amount)
+}
+
+func getPriceFromSqrtPrice(sqrtPrice *big.Int, baseDecimal, quoteDecimal uint8) decimal.Decimal {
+	decSqrt := decimal.NewFromBigInt(sqrtPrice, 0)
+	return decSqrt.Mul(decSqrt).
+		Mul(decimal.New(1, int32(baseDecimal)-int32(quoteDecimal))).
+		Div(decimal.NewFromBigInt(new(big.Int).Lsh(big.NewInt(1), 128), 0))
+}
+
+// updateVolatilityReferences refreshes the pool's volatility tracker before a simulated swap.
+func updateVolatilityReferences(config *shared.PoolConfig, pool *shared.VirtualPool, currentTime uint64) {
+	dynamicFee := config.PoolFees.DynamicFee
+	if dynamicFee.Initialized == 0 {
+		return
+	}
+	tracker := &pool.VolatilityTracker
+	elapsed := currentTime - tracker.LastUpdateTimestamp
+	if currentTime < tracker.LastUpdateTimestamp || elapsed < uint64(dynamicFee.FilterPeriod) {
+		return
+	}
+	tracker.SqrtPriceReference = pool.SqrtPrice
+	tracker.VolatilityReference = BigToU128(dynamicfee.DecayedReference(
+		tracker.VolatilityAccumulator.BigInt(), uint64(dynamicFee.ReductionFactor), elapsed, uint64(dynamicFee.DecayPeriod),
+	))
+}
+
+// updateVolatilityAccumulator records the price movement of a simulated swap on the pool's volatility tracker.
+func updateVolatilityAccumulator(config *shared.PoolConfig, pool *shared.VirtualPool, oldSqrtPrice *big.Int, currentTime uint64) {
+	dynamicFee := config.PoolFees.DynamicFee
+	if dynamicFee.Initialized == 0 {
+		return
+	}
+	tracker := &pool.VolatilityTracker
+	binStep := dynamicFee.BinStepU128.BigInt()
+	sqrtPrice := pool.SqrtPrice.BigInt()
+	tracker.VolatilityAccumulator = BigToU128(dynamicfee.Accumulator(
+		binStep, sqrtPrice, tracker.SqrtPriceReference.BigInt(), tracker.VolatilityReference.BigInt(), uint64(dynamicFee.MaxVolatilityAccumulator),
+	))
+	if dynamicfee.DeltaBinID(binStep, oldSqrtPrice, sqrtPrice).Sign() > 0 {
+		tracker.LastUpdateTimestamp = currentTime
+	}
+}
@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"math/big"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+// PoolFeeMetricsFromState computes the fee metrics of a pool from its state and config.
+// With withValue set, base fees are also valued at the pool's current sqrt price.
+func PoolFeeMetricsFromState(poolAddress solanago.PublicKey, pool *shared.VirtualPool, config *shared.PoolConfig, withValue bool) shared.PoolFeeMetrics {
+	creatorBase, partnerBase := splitTradingFee(pool.Metrics.TotalTradingBaseFee, config.CreatorTradingFeePercentage)
+	creatorQuote, partnerQuote := splitTradingFee(pool.Metrics.TotalTradingQuoteFee, config.CreatorTradingFeePercentage)
+
+	metrics := shared.PoolFeeMetrics{
+		Pool:      poolAddress,
+		Config:    pool.Config,
+		BaseMint:  pool.BaseMint,
+		QuoteMint: config.QuoteMint,
+		Partner:   partyFees(partnerBase, partnerQuote, pool.PartnerBaseFee, pool.PartnerQuoteFee),
+		Creator:   partyFees(creatorBase, creatorQuote, pool.CreatorBaseFee, pool.CreatorQuoteFee),
+		Protocol: partyFees(
+			new(big.Int).SetUint64(pool.Metrics.TotalProtocolBaseFee),
+			new(big.Int).SetUint64(pool.Metrics.TotalProtocolQuoteFee),
+			pool.ProtocolBaseFee,
+			pool.ProtocolQuoteFee,
+		),
+	}
+	if withValue {
+		sqrtPrice := pool.SqrtPrice.BigInt()
+		for _, party := range []*shared.PartyFees{&metrics.Partner, &metrics.Creator, &metrics.Protocol} {
+			valueFeeAmounts(&party.Unclaimed, sqrtPrice)
+			valueFeeAmounts(&party.Claimed, sqrtPrice)
+		}
+	}
+	return metrics
+}
+
+// AddPoolFeeMetrics appends pool to metrics and adds it to the totals of its quote mint.
+func AddPoolFeeMetrics(metrics *shared.FeeMetrics, pool shared.PoolFeeMetrics) {
+	metrics.Pools = append(metrics.Pools, pool)
+	var totals *shared.QuoteFeeTotals
+	for i := range metrics.Totals {
+		if metrics.Totals[i].QuoteMint.Equals(pool.QuoteMint) {
+			totals = &metrics.Totals[i]
+			break
+		}
+	}
+	if totals == nil {
+		metrics.Totals = append(metrics.Totals, shared.QuoteFeeTotals{QuoteMint: pool.QuoteMint})
+		totals = &metrics.Totals[len(metrics.Totals)-1]
+	}
+	totals.Pools++
+	addPartyFees(&totals.Partner, pool.Partner)
+	addPartyFees(&totals.Creator, pool.Creator)
+	addPartyFees(&totals.Protocol, pool.Protocol)
+}
+
+// ProtocolFeeOwedFromState reports the unclaimed protocol trading fees of a pool and the protocol share
+// of its pool creation fee unless already claimed.
+func ProtocolFeeOwedFromState(poolAddress solanago.PublicKey, pool *shared.VirtualPool, config *shared.PoolConfig) shared.ProtocolFeeOwed {
+	owed := shared.ProtocolFeeOwed{
+		Pool:      poolAddress,
+		Config:    pool.Config,
+		BaseMint:  pool.BaseMint,
+		QuoteMint: config.QuoteMint,
+		BaseFee:   pool.ProtocolBaseFee,
+		QuoteFee:  pool.ProtocolQuoteFee,
+	}
+	if config.PoolCreationFee > 0 && shared.CreationFeeBits(pool.CreationFeeBits).IsProtocolClaimed() == 0 {
+		owed.PoolCreationFee = config.PoolCreationFee * shared.ProtocolPoolCreationFeePercent / 100
+	}
+	return owed
+}
+
+// splitTradingFee splits a trading fee into the creator and partner shares the way a swap does.
+func splitTradingFee(tradingFee uint64, creatorTradingFeePercentage uint8) (*big.Int, *big.Int) {
+	total := new(big.Int).SetUint64(tradingFee)
+	creator := new(big.Int).Mul(total, big.NewInt(int64(creatorTradingFeePercentage)))
+	creator.Div(creator, big.NewInt(100))
+	return creator, total.Sub(total, creator)
+}
+
+// partyFees reports the unclaimed fees and the claimed rest of the earned fees. Swaps round the creator
+// share down one by one, so the split of the lifetime total may exceed a party's unclaimed fee slightly.
+func partyFees(earnedBase, earnedQuote *big.Int, unclaimedBase, unclaimedQuote uint64) shared.PartyFees {
+	claimed := func(earned *big.Int, unclaimed uint64) *big.Int {
+		out := new(big.Int).Sub(earned, new(big.Int).SetUint64(unclaimed))
+		if out.Sign() < 0 {
+			return big.NewInt(0)
+		}
+		return out
+	}
+	return shared.PartyFees{
+		Unclaimed: shared.FeeAmounts{Base: new(big.Int).SetUint64(unclaimedBase), Quote: new(big.Int).SetUint64(unclaimedQuote)},
+		Claimed:   shared.FeeAmounts{Base: claimed(earnedBase, unclaimedBase), Quote: claimed(earnedQuote, unclaimedQuote)},
+	}
+}
+
+// valueFeeAmounts sets Value to the quote plus the base converted at sqrtPrice, rounding down.
+func valueFeeAmounts(amounts *shared.FeeAmounts, sqrtPrice *big.Int) {
+	value := new(big.Int).Mul(amounts.Base, sqrtPrice)
+	value.Mul(value, sqrtPrice)
+	value.Rsh(value, 128)
+	amounts.Value = value.Add(value, amounts.Quote)
+}
+
+func addPartyFees(dst *shared.PartyFees, src shared.PartyFees) {
+	addFeeAmounts(&dst.Unclaimed, src.Unclaimed)
+	addFeeAmounts(&dst.Claimed, src.Claimed)
+}
+
+func addFeeAmounts(dst *shared.FeeAmounts, src shared.FeeAmounts) {
+	if dst.Base == nil {
+		dst.Base = big.NewInt(0)
+	}
+	dst.Base.Add(dst.Base, src.Base)
+	if dst.Quote == nil {
+		dst.Quote = big.NewInt(0)
+	}
+	dst.Quote.Add(dst.Quote, src.Quote)
+	if src.Value != nil {
+		if dst.Value == nil {
+			dst.Value = big.NewInt(0)
+		}
+		dst.Value.Add(dst.Value, src.Value)
+	}
+}
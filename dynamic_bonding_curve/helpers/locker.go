@@ -0,0 +1,125 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+// claimV2Discriminator is the anchor discriminator of the locker claim_v2 instruction.
+var claimV2Discriminator = func() [8]byte {
+	hash := sha256.Sum256([]byte("global:claim_v2"))
+	var out [8]byte
+	copy(out[:], hash[:8])
+	return out
+}()
+
+// ParseVestingEscrow decodes a locker escrow account.
+func ParseVestingEscrow(data []byte) (*shared.VestingEscrow, error) {
+	if len(data) < 8 || !bytes.Equal(data[:8], discriminator(AccountKeyVestingEscrow)) {
+		return nil, fmt.Errorf("account is not a locker vesting escrow")
+	}
+	escrow := new(shared.VestingEscrow)
+	if err := bin.NewBorshDecoder(data[8:]).Decode(escrow); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vesting escrow: %w", err)
+	}
+	return escrow, nil
+}
+
+// VestingTotalLocked is the amount the escrow releases over its whole schedule.
+func VestingTotalLocked(escrow *shared.VestingEscrow) uint64 {
+	return escrow.CliffUnlockAmount + escrow.AmountPerPeriod*escrow.NumberOfPeriod
+}
+
+// VestingUnlockedAmount is the amount unlocked at now, the way the locker computes it on claim.
+// A cancelled escrow stops unlocking at its cancel time.
+func VestingUnlockedAmount(escrow *shared.VestingEscrow, now uint64) uint64 {
+	if escrow.CancelledAt > 0 {
+		now = min(now, escrow.CancelledAt)
+	}
+	if now < escrow.CliffTime {
+		return 0
+	}
+	periods := escrow.NumberOfPeriod
+	if escrow.Frequency > 0 {
+		periods = min((now-escrow.CliffTime)/escrow.Frequency, escrow.NumberOfPeriod)
+	}
+	return escrow.CliffUnlockAmount + periods*escrow.AmountPerPeriod
+}
+
+// VestingSchedule lists the unlocks of the escrow: the cliff unlock followed by one unlock per period.
+func VestingSchedule(escrow *shared.VestingEscrow) []shared.VestingUnlock {
+	schedule := make([]shared.VestingUnlock, 0, escrow.NumberOfPeriod+1)
+	cumulative := uint64(0)
+	if escrow.CliffUnlockAmount > 0 {
+		cumulative = escrow.CliffUnlockAmount
+		schedule = append(schedule, shared.VestingUnlock{Time: escrow.CliffTime, Amount: escrow.CliffUnlockAmount, Cumulative: cumulative})
+	}
+	if escrow.AmountPerPeriod == 0 {
+		return schedule
+	}
+	for i := uint64(1); i <= escrow.NumberOfPeriod; i++ {
+		cumulative += escrow.AmountPerPeriod
+		schedule = append(schedule, shared.VestingUnlock{Time: escrow.CliffTime + i*escrow.Frequency, Amount: escrow.AmountPerPeriod, Cumulative: cumulative})
+	}
+	return schedule
+}
+
+// VestingStatusFromEscrow reports the vesting of escrow at now.
+func VestingStatusFromEscrow(pool, escrowAddress, escrowToken solanago.PublicKey, escrow *shared.VestingEscrow, now uint64) shared.CreatorVestingStatus {
+	unlocked := VestingUnlockedAmount(escrow, now)
+	status := shared.CreatorVestingStatus{
+		Pool:        pool,
+		Escrow:      escrowAddress,
+		EscrowToken: escrowToken,
+		Recipient:   escrow.Recipient,
+		TokenMint:   escrow.TokenMint,
+		Now:         now,
+		TotalLocked: VestingTotalLocked(escrow),
+		Unlocked:    unlocked,
+		Claimed:     escrow.TotalClaimedAmount,
+		Cancelled:   escrow.CancelledAt > 0,
+		Schedule:    VestingSchedule(escrow),
+	}
+	if unlocked > escrow.TotalClaimedAmount && !status.Cancelled {
+		status.ClaimableNow = unlocked - escrow.TotalClaimedAmount
+	}
+	if !status.Cancelled {
+		for i := range status.Schedule {
+			if status.Schedule[i].Time > now {
+				next := status.Schedule[i]
+				status.NextUnlock = &next
+				break
+			}
+		}
+	}
+	return status
+}
+
+// ClaimVestingInstruction builds the locker claim_v2 instruction moving up to maxAmount unlocked tokens
+// from the escrow to recipientToken. The recipient signs.
+func ClaimVestingInstruction(escrow, tokenMint, escrowToken, recipient, recipientToken, tokenProgram solanago.PublicKey, maxAmount uint64) solanago.Instruction {
+	// discriminator, max_amount and a None remaining_accounts_info.
+	data := make([]byte, 0, 17)
+	data = append(data, claimV2Discriminator[:]...)
+	data = binary.LittleEndian.AppendUint64(data, maxAmount)
+	data = append(data, 0)
+
+	accounts := solanago.AccountMetaSlice{
+		solanago.NewAccountMeta(escrow, true, false),
+		solanago.NewAccountMeta(tokenMint, false, false),
+		solanago.NewAccountMeta(escrowToken, true, false),
+		solanago.NewAccountMeta(recipient, true, true),
+		solanago.NewAccountMeta(recipientToken, true, false),
+		solanago.NewAccountMeta(MemoProgramID, false, false),
+		solanago.NewAccountMeta(tokenProgram, false, false),
+		solanago.NewAccountMeta(DeriveLockerEventAuthority(), false, false),
+		solanago.NewAccountMeta(LockerProgramID, false, false),
+	}
+	return solanago.NewInstruction(LockerProgramID, accounts, data)
+}
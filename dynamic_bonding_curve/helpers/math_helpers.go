@@ -13,7 +13,7 @@ func Add(a, b *big.Int) *big.Int {
 
 func Sub(a, b *big.Int) (*big.Int, error) {
 	if b.Cmp(a) > 0 {
-		return nil, errors.New("SafeMath: subtraction overflow")
+		return nil, shared.NewMathOverflowError("SafeMath: subtraction")
 	}
 	return new(big.Int).Sub(a, b), nil
 }
@@ -47,6 +47,19 @@ func CreateAssociatedTokenAccountInstruction(payer, ata, owner, mint, tokenProgr
 	return solanago.NewInstruction(solanago.SPLAssociatedTokenAccountProgramID, accounts, nil)
 }
 
+// CreateAssociatedTokenAccountIdempotentInstruction builds an ATA create instruction that also succeeds when the account exists.
+func CreateAssociatedTokenAccountIdempotentInstruction(payer, ata, owner, mint, tokenProgram solanago.PublicKey) solanago.Instruction {
+	accounts := solanago.AccountMetaSlice{
+		solanago.NewAccountMeta(payer, true, true),
+		solanago.NewAccountMeta(ata, true, false),
+		solanago.NewAccountMeta(owner, false, false),
+		solanago.NewAccountMeta(mint, false, false),
+		solanago.NewAccountMeta(system.ProgramID, false, false),
+		solanago.NewAccountMeta(tokenProgram, false, false),
+	}
+	return solanago.NewInstruction(solanago.SPLAssociatedTokenAccountProgramID, accounts, []byte{1})
+}
+
 func UnwrapSOLInstruction(owner, receiver solanago.PublicKey, allowOwnerOffCurve bool) (solanago.Instruction, error) {
 	ata, err := FindAssociatedTokenAddress(owner, NativeMint, token.ProgramID)
 	if err != nil {
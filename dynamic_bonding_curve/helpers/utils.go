@@ -127,7 +127,7 @@ func BigIntToU64(v *big.Int) (uint64, error) {
 		return 0, errors.New("value must be non-negative")
 	}
 	if v.BitLen() > 64 {
-		return 0, errors.New("value overflows uint64")
+		return 0, shared.NewMathOverflowError("big int to u64")
 	}
 	return v.Uint64(), nil
 }
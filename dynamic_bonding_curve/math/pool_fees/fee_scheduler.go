@@ -21,7 +21,7 @@ func GetBaseFeeNumeratorByPeriod(cliffFeeNumerator *big.Int, numberOfPeriod uint
 		periodValue = big.NewInt(int64(numberOfPeriod))
 	}
 	if periodValue.Cmp(big.NewInt(int64(dbc.U16Max))) > 0 {
-		return nil, errors.New("Math overflow")
+		return nil, dbc.NewMathOverflowError("fee scheduler period")
 	}
 	periodNumber := int(periodValue.Uint64())
 
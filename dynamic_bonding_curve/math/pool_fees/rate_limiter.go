@@ -121,7 +121,7 @@ func GetFeeNumeratorFromExcludedAmount(cliffFeeNumerator, referenceAmount, feeIn
 		includedFeeAmount = new(big.Int).Add(includedFeeAmount, includedRemaining)
 	} else {
 		if isOverflow {
-			return nil, errors.New("Math overflow")
+			return nil, dbc.NewMathOverflowError("rate limiter included fee amount")
 		}
 		excludedRemaining := new(big.Int).Sub(excludedFeeAmount, checkedExcludedFeeAmount)
 		includedRemaining, err := mulDiv(excludedRemaining, big.NewInt(dbc.FeeDenominator), new(big.Int).Sub(big.NewInt(dbc.FeeDenominator), big.NewInt(dbc.MaxFeeNumerator)), dbc.RoundingUp)
@@ -15,11 +15,13 @@ func MulDiv(x, y, denominator *big.Int, rounding dbc.Rounding) (*big.Int, error)
 		return new(big.Int).Mul(x, y), nil
 	}
 	prod := new(big.Int).Mul(x, y)
+	if err := dbc.CheckedU256(prod, "MulDiv product"); err != nil {
+		return nil, err
+	}
 	if rounding == dbc.RoundingUp {
-		numerator := new(big.Int).Add(prod, new(big.Int).Sub(denominator, big.NewInt(1)))
-		return new(big.Int).Div(numerator, denominator), nil
+		prod.Add(prod, new(big.Int).Sub(denominator, big.NewInt(1)))
 	}
-	return new(big.Int).Div(prod, denominator), nil
+	return prod.Div(prod, denominator), nil
 }
 
 func MulShr(x, y *big.Int, offset uint) *big.Int {
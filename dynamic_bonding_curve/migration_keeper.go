@@ -0,0 +1,473 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	sendandconfirmtransaction "github.com/gagliardetto/solana-go/rpc/sendAndConfirmTransaction"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+)
+
+const defaultMigrationRetryDelay = 5 * time.Second
+
+// MigrationStore persists migration jobs.
+type MigrationStore interface {
+	Save(job MigrationJob) error
+	Load(pool solanago.PublicKey) (MigrationJob, bool, error)
+	List() ([]MigrationJob, error)
+}
+
+// MemoryMigrationStore keeps migration jobs in memory.
+type MemoryMigrationStore struct {
+	mu   sync.RWMutex
+	jobs map[solanago.PublicKey]MigrationJob
+}
+
+func NewMemoryMigrationStore() *MemoryMigrationStore {
+	return &MemoryMigrationStore{jobs: make(map[solanago.PublicKey]MigrationJob)}
+}
+
+func (s *MemoryMigrationStore) Save(job MigrationJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.Pool] = job
+	return nil
+}
+
+func (s *MemoryMigrationStore) Load(pool solanago.PublicKey) (MigrationJob, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[pool]
+	return job, ok, nil
+}
+
+func (s *MemoryMigrationStore) List() ([]MigrationJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]MigrationJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out = append(out, job)
+	}
+	return out, nil
+}
+
+// MigrationKeeper watches virtual pools and drives them from curve completion to a migrated DAMM pool.
+// The next step is always derived from on-chain state, so a step that landed but was not confirmed is never sent twice.
+type MigrationKeeper struct {
+	dbc      *DynamicBondingCurve
+	wsClient *ws.Client
+	config   MigrationKeeperConfig
+
+	mu         sync.Mutex
+	runCtx     context.Context
+	errCh      chan error
+	subscribed map[solanago.PublicKey]bool
+	inFlight   map[solanago.PublicKey]bool
+}
+
+func NewMigrationKeeper(dbc *DynamicBondingCurve, wsClient *ws.Client, config MigrationKeeperConfig) *MigrationKeeper {
+	if config.Store == nil {
+		config.Store = NewMemoryMigrationStore()
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaultMigrationRetryDelay
+	}
+	return &MigrationKeeper{
+		dbc:        dbc,
+		wsClient:   wsClient,
+		config:     config,
+		subscribed: make(map[solanago.PublicKey]bool),
+		inFlight:   make(map[solanago.PublicKey]bool),
+	}
+}
+
+// Add tracks pool; dammConfig overrides the DAMM config derived from the migration fee option and may be zero.
+func (k *MigrationKeeper) Add(pool, dammConfig solanago.PublicKey) (MigrationJob, error) {
+	job, ok, err := k.config.Store.Load(pool)
+	if err != nil {
+		return MigrationJob{}, err
+	}
+	if !ok {
+		job = MigrationJob{Pool: pool}
+	}
+	if !dammConfig.IsZero() {
+		job.DammConfig = dammConfig
+	}
+	if err := k.config.Store.Save(job); err != nil {
+		return MigrationJob{}, err
+	}
+
+	k.mu.Lock()
+	running := k.runCtx != nil
+	k.mu.Unlock()
+	if running && !job.Done {
+		if err := k.watch(pool, false); err != nil {
+			return MigrationJob{}, err
+		}
+	}
+	return job, nil
+}
+
+// Run watches every unfinished job and every pool of config.Configs until ctx is done or a subscription fails.
+func (k *MigrationKeeper) Run(ctx context.Context) error {
+	k.mu.Lock()
+	if k.runCtx != nil {
+		k.mu.Unlock()
+		return errors.New("keeper is already running")
+	}
+	k.runCtx = ctx
+	k.errCh = make(chan error, 1)
+	k.mu.Unlock()
+	defer func() {
+		k.mu.Lock()
+		k.runCtx = nil
+		k.subscribed = make(map[solanago.PublicKey]bool)
+		k.mu.Unlock()
+	}()
+
+	for _, config := range k.config.Configs {
+		pools, err := k.dbc.GetPoolsByConfig(ctx, config)
+		if err != nil {
+			return err
+		}
+		for _, pool := range pools {
+			if IsMigrated(pool.Account.IsMigrated) == IsMigratedCompleted {
+				continue
+			}
+			if err := k.track(pool.Pubkey); err != nil {
+				return err
+			}
+		}
+		if err := k.watch(config, true); err != nil {
+			return err
+		}
+	}
+
+	jobs, err := k.config.Store.List()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Done {
+			continue
+		}
+		if err := k.watch(job.Pool, false); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-k.errCh:
+		return err
+	}
+}
+
+// watch subscribes once to a pool account and processes each update, or, for a config,
+// to its virtual pools so that pools launched later are tracked too.
+func (k *MigrationKeeper) watch(key solanago.PublicKey, isConfig bool) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.subscribed[key] {
+		return nil
+	}
+	ctx, errCh := k.runCtx, k.errCh
+	fail := func(err error) {
+		if ctx.Err() == nil {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}
+	}
+
+	if isConfig {
+		filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyVirtualPool, &helpers.Filter{
+			Owner:  key,
+			Offset: helpers.ComputeStructOffset(new(shared.VirtualPool), "Config"),
+		})
+		sub, err := k.wsClient.ProgramSubscribeWithOpts(helpers.DynamicBondingCurveProgramID, k.dbc.Commitment, solanago.EncodingBase64, filters)
+		if err != nil {
+			return err
+		}
+		k.subscribed[key] = true
+		go func() {
+			defer sub.Unsubscribe()
+			for {
+				got, err := sub.Recv(ctx)
+				if err != nil {
+					fail(err)
+					return
+				}
+				if got == nil || got.Value.Account == nil {
+					continue
+				}
+				poolState, err := dbcidl.ParseAccount_VirtualPool(got.Value.Account.Data.GetBinary())
+				if err != nil || IsMigrated(poolState.IsMigrated) == IsMigratedCompleted {
+					continue
+				}
+				// pools already tracked have their own subscription.
+				if _, ok, err := k.config.Store.Load(got.Value.Pubkey); err != nil {
+					fail(err)
+					return
+				} else if ok {
+					continue
+				}
+				if err := k.track(got.Value.Pubkey); err != nil {
+					fail(err)
+					return
+				}
+				if err := k.watch(got.Value.Pubkey, false); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+		return nil
+	}
+
+	sub, err := k.wsClient.AccountSubscribeWithOpts(key, k.dbc.Commitment, solanago.EncodingBase64)
+	if err != nil {
+		return err
+	}
+	k.subscribed[key] = true
+	go func() {
+		defer sub.Unsubscribe()
+		// the curve may already be complete before the pool changes again.
+		k.trigger(ctx, key)
+		for {
+			got, err := sub.Recv(ctx)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if got.Value == nil {
+				continue
+			}
+			k.trigger(ctx, key)
+		}
+	}()
+	return nil
+}
+
+// trigger processes pool in the background unless it is already being processed;
+// the running pass re-reads the pool after every step, so the update is not lost.
+func (k *MigrationKeeper) trigger(ctx context.Context, pool solanago.PublicKey) {
+	k.mu.Lock()
+	if k.inFlight[pool] {
+		k.mu.Unlock()
+		return
+	}
+	k.inFlight[pool] = true
+	k.mu.Unlock()
+	go func() {
+		defer func() {
+			k.mu.Lock()
+			delete(k.inFlight, pool)
+			k.mu.Unlock()
+		}()
+		// step failures are recorded on the job and retried on the next update.
+		_, _ = k.Process(ctx, pool)
+	}()
+}
+
+// Process runs the remaining migration steps of pool in order.
+// A failing step is retried after RetryDelay until it succeeds, ctx is done or MaxAttempts is reached.
+// It returns without error while the bonding curve is not complete yet.
+func (k *MigrationKeeper) Process(ctx context.Context, pool solanago.PublicKey) (MigrationJob, error) {
+	job, ok, err := k.config.Store.Load(pool)
+	if err != nil {
+		return MigrationJob{}, err
+	}
+	if !ok {
+		job = MigrationJob{Pool: pool}
+	}
+	if job.Done {
+		return job, nil
+	}
+
+	for {
+		step, pending, err := k.nextStep(ctx, &job)
+		if err == nil && !pending {
+			return job, k.save(job)
+		}
+		var sig solanago.Signature
+		if err == nil {
+			sig, err = k.runStep(ctx, job, step)
+		}
+		if err != nil {
+			job.Attempts++
+			job.LastError = err.Error()
+			job.UpdatedAt = time.Now()
+			if serr := k.save(job); serr != nil {
+				return job, serr
+			}
+			if k.config.MaxAttempts > 0 && job.Attempts >= k.config.MaxAttempts {
+				return job, err
+			}
+			select {
+			case <-ctx.Done():
+				return job, ctx.Err()
+			case <-time.After(k.config.RetryDelay):
+			}
+			continue
+		}
+
+		job.Completed = append(job.Completed, step)
+		job.Signatures = append(job.Signatures, sig)
+		job.Attempts = 0
+		job.LastError = ""
+		job.UpdatedAt = time.Now()
+		if err := k.save(job); err != nil {
+			return job, err
+		}
+		if k.config.OnStep != nil {
+			k.config.OnStep(job, step)
+		}
+	}
+}
+
+// nextStep returns the first permissionless step of the pool migration status.
+// pending is false when the curve is not complete yet or nothing is left, in which case job is marked done.
+func (k *MigrationKeeper) nextStep(ctx context.Context, job *MigrationJob) (MigrationStep, bool, error) {
+	status, err := k.dbc.GetMigrationStatus(ctx, job.Pool)
+	if err != nil {
+		return 0, false, err
+	}
+	job.MigrationOption = status.MigrationOption
+	if job.DammConfig.IsZero() {
+		job.DammConfig = status.DammConfig
+	}
+	for _, step := range status.Remaining {
+		// surplus and migration fees are withdrawn by their owners.
+		if !step.Signer.IsZero() {
+			continue
+		}
+		if !step.Ready {
+			return 0, false, nil
+		}
+		if step.Step == MigrationStepMigrate && job.DammConfig.IsZero() {
+			return 0, false, errors.New("damm config is required for customizable migration fee option")
+		}
+		return step.Step, true, nil
+	}
+	job.Done = true
+	return 0, false, nil
+}
+
+// runStep builds, signs and confirms the transaction of one migration step.
+func (k *MigrationKeeper) runStep(ctx context.Context, job MigrationJob, step MigrationStep) (solanago.Signature, error) {
+	payer := k.config.Payer
+	var (
+		pre, post []solanago.Instruction
+		ix        solanago.Instruction
+		err       error
+	)
+	switch step {
+	case MigrationStepCreateLocker:
+		pre, ix, post, err = k.dbc.CreateLocker(ctx, CreateLockerParams{Payer: payer, VirtualPool: job.Pool})
+	case MigrationStepCreateMetadata:
+		poolState, perr := k.dbc.GetPool(ctx, job.Pool)
+		if perr != nil {
+			return solanago.Signature{}, perr
+		}
+		params := CreateDammV1MigrationMetadataParams{VirtualPool: job.Pool, Config: poolState.Config, Payer: payer}
+		if job.MigrationOption == MigrationOptionMetDammV2 {
+			ix, err = k.dbc.CreateDammV2MigrationMetadata(ctx, params)
+		} else {
+			ix, err = k.dbc.CreateDammV1MigrationMetadata(ctx, params)
+		}
+	case MigrationStepMigrate:
+		params := MigrateToDammV1Params{Payer: payer, VirtualPool: job.Pool, DammConfig: job.DammConfig}
+		if job.MigrationOption == MigrationOptionMetDammV2 {
+			resp, merr := k.dbc.MigrateToDammV2(ctx, params)
+			if merr != nil {
+				return solanago.Signature{}, merr
+			}
+			return k.send(ctx, resp.Transaction, resp.FirstPositionNFT, resp.SecondPositionNFT)
+		}
+		pre, ix, post, err = k.dbc.MigrateToDammV1(ctx, params)
+	case MigrationStepLockPartnerLp, MigrationStepLockCreatorLp:
+		pre, ix, post, err = k.dbc.LockDammV1LpToken(ctx, DammLpTokenParams{
+			Payer:       payer,
+			VirtualPool: job.Pool,
+			DammConfig:  job.DammConfig,
+			IsPartner:   step == MigrationStepLockPartnerLp,
+		})
+	case MigrationStepClaimPartnerLp, MigrationStepClaimCreatorLp:
+		pre, ix, post, err = k.dbc.ClaimDammV1LpToken(ctx, DammLpTokenParams{
+			Payer:       payer,
+			VirtualPool: job.Pool,
+			DammConfig:  job.DammConfig,
+			IsPartner:   step == MigrationStepClaimPartnerLp,
+		})
+	case MigrationStepWithdrawLeftover:
+		pre, ix, post, err = k.dbc.WithdrawLeftover(ctx, WithdrawLeftoverParams{Payer: payer, VirtualPool: job.Pool})
+	default:
+		return solanago.Signature{}, fmt.Errorf("unknown migration step %d", step)
+	}
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+
+	instructions := append(pre, ix)
+	instructions = append(instructions, post...)
+	tx, err := solanago.NewTransaction(instructions, solanago.Hash{}, solanago.TransactionPayer(payer))
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	return k.send(ctx, tx)
+}
+
+// send sets a fresh blockhash, signs with the keeper signer plus extra, submits and confirms tx.
+func (k *MigrationKeeper) send(ctx context.Context, tx *solanago.Transaction, extra ...solanago.PrivateKey) (solanago.Signature, error) {
+	if k.config.Signer == nil {
+		return solanago.Signature{}, errors.New("keeper signer is required")
+	}
+	latestBlockhash, err := k.dbc.RPC.GetLatestBlockhash(ctx, k.dbc.Commitment)
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	tx.Message.RecentBlockhash = latestBlockhash.Value.Blockhash
+	tx.Signatures = nil
+	if _, err := tx.Sign(func(key solanago.PublicKey) *solanago.PrivateKey {
+		for i := range extra {
+			if extra[i].PublicKey().Equals(key) {
+				return &extra[i]
+			}
+		}
+		return k.config.Signer(key)
+	}); err != nil {
+		return solanago.Signature{}, err
+	}
+	sig, err := k.dbc.RPC.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{PreflightCommitment: k.dbc.Commitment})
+	if err != nil {
+		return solanago.Signature{}, err
+	}
+	if _, err := sendandconfirmtransaction.WaitForConfirmation(ctx, k.wsClient, sig, nil); err != nil {
+		return solanago.Signature{}, err
+	}
+	return sig, nil
+}
+
+// track stores a new job for pool unless one exists.
+func (k *MigrationKeeper) track(pool solanago.PublicKey) error {
+	_, ok, err := k.config.Store.Load(pool)
+	if err != nil || ok {
+		return err
+	}
+	return k.config.Store.Save(MigrationJob{Pool: pool})
+}
+
+func (k *MigrationKeeper) save(job MigrationJob) error {
+	return k.config.Store.Save(job)
+}
@@ -0,0 +1,202 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"errors"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	dammv2gen "github.com/krazyTry/meteora-go/gen/damm_v2"
+	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+)
+
+// GetMigrationStatus reads a virtual pool, its config and migration metadata and reports
+// the migration phase, the steps still to be done, who may send each and the migrated DAMM accounts.
+func (s *DynamicBondingCurve) GetMigrationStatus(ctx context.Context, pool solanago.PublicKey) (MigrationStatus, error) {
+	poolState, err := s.GetPool(ctx, pool)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	config, err := s.GetPoolConfig(ctx, poolState.Config)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	status := MigrationStatus{
+		Pool:                    pool,
+		Config:                  poolState.Config,
+		MigrationProgress:       MigrationProgress(poolState.MigrationProgress),
+		MigrationOption:         MigrationOption(config.MigrationOption),
+		QuoteReserve:            poolState.QuoteReserve,
+		MigrationQuoteThreshold: config.MigrationQuoteThreshold,
+		MigrationMetadata:       migrationMetadataAddress(MigrationOption(config.MigrationOption), pool),
+	}
+	isV2 := status.MigrationOption == MigrationOptionMetDammV2
+	if isV2 {
+		status.DammConfig = helpers.GetDammV2Config(MigrationFeeOption(config.MigrationFeeOption))
+	} else {
+		status.DammConfig = helpers.GetDammV1Config(MigrationFeeOption(config.MigrationFeeOption))
+	}
+	if !status.DammConfig.IsZero() {
+		if isV2 {
+			status.DammPool = helpers.DeriveDammV2PoolAddress(status.DammConfig, poolState.BaseMint, config.QuoteMint)
+		} else {
+			status.DammPool = helpers.DeriveDammV1PoolAddress(status.DammConfig, poolState.BaseMint, config.QuoteMint)
+		}
+	}
+	hasLocker := config.LockedVestingConfig.AmountPerPeriod > 0 || config.LockedVestingConfig.CliffUnlockAmount > 0
+	if hasLocker {
+		status.LockEscrow = helpers.DeriveEscrow(helpers.DeriveBaseKeyForLocker(pool))
+	}
+
+	progress := status.MigrationProgress
+	curveComplete := progress != MigrationProgressPreBondingCurve
+	migrated := progress == MigrationProgressCreatedPool
+	add := func(step MigrationStep, ready bool, signer, recipient solanago.PublicKey) {
+		status.Remaining = append(status.Remaining, MigrationStepStatus{Step: step, Ready: ready, Signer: signer, Recipient: recipient})
+	}
+
+	if !migrated {
+		if hasLocker && progress <= MigrationProgressPostBondingCurve {
+			add(MigrationStepCreateLocker, progress == MigrationProgressPostBondingCurve, solanago.PublicKey{}, status.LockEscrow)
+		}
+		metadataExists, err := s.accountExists(ctx, status.MigrationMetadata)
+		if err != nil {
+			return MigrationStatus{}, err
+		}
+		if !metadataExists {
+			add(MigrationStepCreateMetadata, progress == MigrationProgressLockedVesting, solanago.PublicKey{}, solanago.PublicKey{})
+		}
+		add(MigrationStepMigrate, progress == MigrationProgressLockedVesting && metadataExists, solanago.PublicKey{}, status.DammPool)
+	}
+
+	if !isV2 {
+		if !status.DammPool.IsZero() {
+			status.LpMint = helpers.DeriveDammV1LpMintAddress(status.DammPool)
+			status.PartnerLockEscrow = helpers.DeriveDammV1LockEscrowAddress(status.DammPool, config.FeeClaimer)
+			status.CreatorLockEscrow = helpers.DeriveDammV1LockEscrowAddress(status.DammPool, poolState.Creator)
+		}
+		// before migration the LP split is only known from the config percentages.
+		lockPartner := config.PartnerPermanentLockedLiquidityPercentage > 0
+		lockCreator := config.CreatorPermanentLockedLiquidityPercentage > 0
+		claimPartner := config.PartnerLiquidityPercentage > 0
+		claimCreator := config.CreatorLiquidityPercentage > 0
+		if migrated {
+			metadata, err := s.dammV1MigrationMetadata(ctx, pool)
+			if err != nil {
+				return MigrationStatus{}, err
+			}
+			status.LpMint = metadata.LpMint
+			lockPartner = metadata.PartnerLockedLiquidity > 0 && metadata.PartnerLockedStatus == 0
+			lockCreator = metadata.CreatorLockedLiquidity > 0 && metadata.CreatorLockedStatus == 0
+			claimPartner = metadata.PartnerLiquidity > 0 && metadata.PartnerClaimStatus == 0
+			claimCreator = metadata.CreatorLiquidity > 0 && metadata.CreatorClaimStatus == 0
+		}
+		if lockPartner {
+			add(MigrationStepLockPartnerLp, migrated, solanago.PublicKey{}, status.PartnerLockEscrow)
+		}
+		if lockCreator {
+			add(MigrationStepLockCreatorLp, migrated, solanago.PublicKey{}, status.CreatorLockEscrow)
+		}
+		if claimPartner {
+			add(MigrationStepClaimPartnerLp, migrated, solanago.PublicKey{}, config.FeeClaimer)
+		}
+		if claimCreator {
+			add(MigrationStepClaimCreatorLp, migrated, solanago.PublicKey{}, poolState.Creator)
+		}
+	} else if migrated && !status.DammPool.IsZero() {
+		positions, err := s.dammV2Positions(ctx, status.DammPool)
+		if err != nil {
+			return MigrationStatus{}, err
+		}
+		status.Positions = positions
+	}
+
+	if config.FixedTokenSupplyFlag == 1 && poolState.IsWithdrawLeftover == 0 {
+		add(MigrationStepWithdrawLeftover, migrated, solanago.PublicKey{}, config.LeftoverReceiver)
+	}
+
+	// surplus is the quote collected above the migration threshold.
+	if poolState.QuoteReserve > config.MigrationQuoteThreshold {
+		if poolState.IsPartnerWithdrawSurplus == 0 {
+			add(MigrationStepPartnerWithdrawSurplus, migrated, config.FeeClaimer, config.FeeClaimer)
+		}
+		if config.CreatorTradingFeePercentage > 0 && poolState.IsCreatorWithdrawSurplus == 0 {
+			add(MigrationStepCreatorWithdrawSurplus, migrated, poolState.Creator, poolState.Creator)
+		}
+	}
+
+	if config.MigrationFeePercentage > 0 {
+		feeStatus := MigrationFeeWithdrawStatus(poolState.MigrationFeeWithdrawStatus)
+		if config.CreatorMigrationFeePercentage < 100 && feeStatus.IsPartnerWithdraw() == 0 {
+			add(MigrationStepPartnerWithdrawMigrationFee, curveComplete, config.FeeClaimer, config.FeeClaimer)
+		}
+		if config.CreatorMigrationFeePercentage > 0 && feeStatus.IsCreatorWithdraw() == 0 {
+			add(MigrationStepCreatorWithdrawMigrationFee, curveComplete, poolState.Creator, poolState.Creator)
+		}
+	}
+
+	switch {
+	case !curveComplete:
+		status.Phase = MigrationPhaseBondingCurve
+	case progress == MigrationProgressPostBondingCurve:
+		status.Phase = MigrationPhaseCurveComplete
+	case progress == MigrationProgressLockedVesting:
+		status.Phase = MigrationPhaseReadyToMigrate
+	case len(status.Remaining) > 0:
+		status.Phase = MigrationPhaseMigrated
+	default:
+		status.Phase = MigrationPhaseFinished
+	}
+	return status, nil
+}
+
+// dammV2Positions lists the positions of a DAMM v2 pool: the partner and creator positions created by
+// the migration and any position opened on the pool afterwards.
+func (s *DynamicBondingCurve) dammV2Positions(ctx context.Context, dammPool solanago.PublicKey) ([]MigratedPosition, error) {
+	filters := []rpc.RPCFilter{
+		{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: dammv2gen.Account_Position[:]}},
+		{Memcmp: &rpc.RPCFilterMemcmp{Offset: helpers.ComputeStructOffset(new(dammv2gen.Position), "Pool"), Bytes: dammPool[:]}},
+	}
+	accounts, err := s.RPC.GetProgramAccountsWithOpts(ctx, helpers.DammV2ProgramID, &rpc.GetProgramAccountsOpts{Commitment: s.Commitment, Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigratedPosition, 0, len(accounts))
+	for _, acc := range accounts {
+		position, err := dammv2gen.ParseAccount_Position(acc.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		out = append(out, MigratedPosition{Position: acc.Pubkey, NftMint: position.NftMint})
+	}
+	return out, nil
+}
+
+func (s *DynamicBondingCurve) accountExists(ctx context.Context, address solanago.PublicKey) (bool, error) {
+	acc, err := s.RPC.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{Commitment: s.Commitment})
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return acc != nil && acc.Value != nil, nil
+}
+
+func (s *DynamicBondingCurve) dammV1MigrationMetadata(ctx context.Context, pool solanago.PublicKey) (*MeteoraDammMigrationMetadata, error) {
+	acc, err := s.RPC.GetAccountInfoWithOpts(ctx, helpers.DeriveDammV1MigrationMetadataAddress(pool), &rpc.GetAccountInfoOpts{Commitment: s.Commitment})
+	if err != nil {
+		return nil, err
+	}
+	return dbcidl.ParseAccount_MeteoraDammMigrationMetadata(acc.Value.Data.GetBinary())
+}
+
+func migrationMetadataAddress(option MigrationOption, pool solanago.PublicKey) solanago.PublicKey {
+	if option == MigrationOptionMetDammV2 {
+		return helpers.DeriveDammV2MigrationMetadataAddress(pool)
+	}
+	return helpers.DeriveDammV1MigrationMetadataAddress(pool)
+}
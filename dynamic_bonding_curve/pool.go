@@ -8,6 +8,7 @@ import (
 	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
 	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/math"
 	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/math/pool_fees"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
 	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
 
 	solanago "github.com/gagliardetto/solana-go"
@@ -288,6 +289,14 @@ func (s *DynamicBondingCurve) SwapBuyIx(ctx context.Context, firstBuyParam First
 	if err = helpers.ValidateSwapAmount(firstBuyParam.BuyAmount); err != nil {
 		return
 	}
+	buyAmount, err := shared.CheckedU64(firstBuyParam.BuyAmount, "first buy amount")
+	if err != nil {
+		return
+	}
+	minimumAmountOut, err := shared.CheckedU64(firstBuyParam.MinimumAmountOut, "first buy minimum amount out")
+	if err != nil {
+		return
+	}
 	// rate limiter check
 	rateLimiterApplied := false
 	if BaseFeeMode(baseFee.BaseFeeMode) == BaseFeeModeRateLimiter {
@@ -346,7 +355,7 @@ func (s *DynamicBondingCurve) SwapBuyIx(ctx context.Context, firstBuyParam First
 	}
 
 	if inputMint.Equals(helpers.NativeMint) {
-		wrapIx, werr := helpers.WrapSOLInstruction(firstBuyParam.Buyer, inputTokenAccount, firstBuyParam.BuyAmount.Uint64())
+		wrapIx, werr := helpers.WrapSOLInstruction(firstBuyParam.Buyer, inputTokenAccount, buyAmount)
 		if werr != nil {
 			err = werr
 			return
@@ -360,7 +369,7 @@ func (s *DynamicBondingCurve) SwapBuyIx(ctx context.Context, firstBuyParam First
 		}
 	}
 
-	params := dbcidl.SwapParameters{AmountIn: firstBuyParam.BuyAmount.Uint64(), MinimumAmountOut: firstBuyParam.MinimumAmountOut.Uint64()}
+	params := dbcidl.SwapParameters{AmountIn: buyAmount, MinimumAmountOut: minimumAmountOut}
 
 	ix, err = dbcidl.NewSwapInstruction(
 		params,
@@ -395,6 +404,14 @@ func (s *DynamicBondingCurve) Swap(ctx context.Context, params SwapParams) ([]so
 	if err := helpers.ValidateSwapAmount(params.AmountIn); err != nil {
 		return nil, nil, nil, err
 	}
+	amountIn, err := shared.CheckedU64(params.AmountIn, "swap amount in")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	minimumAmountOut, err := shared.CheckedU64(params.MinimumAmountOut, "swap minimum amount out")
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	poolState, err := s.GetPool(ctx, params.Pool)
 	if err != nil {
 		return nil, nil, nil, err
@@ -422,7 +439,7 @@ func (s *DynamicBondingCurve) Swap(ctx context.Context, params SwapParams) ([]so
 	}
 
 	if inputMint.Equals(helpers.NativeMint) {
-		wrapIx, werr := helpers.WrapSOLInstruction(params.Owner, ataIn, params.AmountIn.Uint64())
+		wrapIx, werr := helpers.WrapSOLInstruction(params.Owner, ataIn, amountIn)
 		if werr != nil {
 			return nil, nil, nil, werr
 		}
@@ -438,8 +455,8 @@ func (s *DynamicBondingCurve) Swap(ctx context.Context, params SwapParams) ([]so
 
 	swapIx, err := dbcidl.NewSwapInstruction(
 		dbcidl.SwapParameters{
-			AmountIn:         params.AmountIn.Uint64(),
-			MinimumAmountOut: params.MinimumAmountOut.Uint64(),
+			AmountIn:         amountIn,
+			MinimumAmountOut: minimumAmountOut,
 		},
 		s.PoolAuthority,
 		poolState.Config,
@@ -500,13 +517,17 @@ func (s *DynamicBondingCurve) Swap2(ctx context.Context, params Swap2Params) ([]
 		return nil, nil, nil, err
 	}
 
-	var amount0, amount1 uint64
+	rawAmount0, rawAmount1 := params.AmountIn, params.MinimumAmountOut
 	if params.SwapMode == SwapModeExactOut {
-		amount0 = params.AmountOut.Uint64()
-		amount1 = params.MaximumAmountIn.Uint64()
-	} else {
-		amount0 = params.AmountIn.Uint64()
-		amount1 = params.MinimumAmountOut.Uint64()
+		rawAmount0, rawAmount1 = params.AmountOut, params.MaximumAmountIn
+	}
+	amount0, err := shared.CheckedU64(rawAmount0, "swap2 amount 0")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	amount1, err := shared.CheckedU64(rawAmount1, "swap2 amount 1")
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	if err := helpers.ValidateSwapAmount(new(big.Int).SetUint64(amount0)); err != nil {
 		return nil, nil, nil, err
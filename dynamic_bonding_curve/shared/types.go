@@ -5,6 +5,9 @@ import (
 
 	"github.com/gagliardetto/solana-go"
 	dbcidl "github.com/krazyTry/meteora-go/gen/dynamic_bonding_curve"
+	"github.com/krazyTry/meteora-go/internal/checked"
+	"github.com/krazyTry/meteora-go/internal/clock"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -37,6 +40,11 @@ const (
 	ProtocolFeePercent = 20
 	HostFeePercent     = 20
 
+	// PartnerAndCreatorSurplusPercent is the share of the migration surplus left after the protocol share.
+	PartnerAndCreatorSurplusPercent = 80
+
+	SlotDurationMillis = clock.SlotDurationMillis
+
 	SwapBufferPercentage = 25
 
 	MaxMigrationFeePercentage        = 99
@@ -86,6 +94,7 @@ type DynamicFeeParameters = dbcidl.DynamicFeeParameters
 type LiquidityDistributionParameters = dbcidl.LiquidityDistributionParameters
 type MigratedPoolMarketCapFeeSchedulerParameters = dbcidl.MigratedPoolMarketCapFeeSchedulerParams
 type LiquidityVestingInfoParameters = dbcidl.LiquidityVestingInfoParams
+type LiquidityVestingInfo = dbcidl.LiquidityVestingInfo
 type CreatePartnerMetadataParameters = dbcidl.CreatePartnerMetadataParameters
 type CreateVirtualPoolMetadataParameters = dbcidl.CreateVirtualPoolMetadataParameters
 type PoolFeesConfig = dbcidl.PoolFeesConfig
@@ -102,6 +111,8 @@ type PoolConfig = dbcidl.PoolConfig
 type VirtualPool = dbcidl.VirtualPool
 type MeteoraDammMigrationMetadata = dbcidl.MeteoraDammMigrationMetadata
 type LockEscrow = dbcidl.LockEscrow
+
+type ClaimFeeOperator = dbcidl.ClaimFeeOperator
 type PartnerMetadata = dbcidl.PartnerMetadata
 type VirtualPoolMetadata = dbcidl.VirtualPoolMetadata
 
@@ -250,6 +261,19 @@ func (m MigrationFeeWithdrawStatus) IsCreatorWithdraw() uint8 {
 	return uint8(m) & creatorMask
 }
 
+// CreationFeeBits records which parties claimed the pool creation fee.
+type CreationFeeBits uint8
+
+func (c CreationFeeBits) IsPartnerClaimed() uint8 {
+	partnerMask := uint8(1) << 0 // 0x01
+	return uint8(c) & partnerMask
+}
+
+func (c CreationFeeBits) IsProtocolClaimed() uint8 {
+	protocolMask := uint8(1) << 1 // 0x02
+	return uint8(c) & protocolMask
+}
+
 // Param/DTO structs mirroring TS types.
 type CreateConfigParams struct {
 	ConfigParameters
@@ -430,3 +454,395 @@ type BaseFeeHandler interface {
 	GetBaseFeeNumeratorFromIncludedFeeAmount(currentPoint, activationPoint *big.Int, tradeDirection TradeDirection, includedFeeAmount *big.Int) *big.Int
 	GetBaseFeeNumeratorFromExcludedFeeAmount(currentPoint, activationPoint *big.Int, tradeDirection TradeDirection, excludedFeeAmount *big.Int) *big.Int
 }
+
+type SimulateCurveFillParams struct {
+	// Steps is the number of equal quote buys the migration quote threshold is split into.
+	Steps int
+	// QuoteDecimal is used for prices and market caps.
+	QuoteDecimal TokenDecimal
+	// ActivationPoint is the slot or timestamp the pool activates at.
+	ActivationPoint uint64
+	// Timeline holds the slot or timestamp of each buy; the last entry is reused once it runs out
+	// and the activation point is used when it is empty. Slots are converted to time at
+	// SlotDurationMillis for the dynamic fee.
+	Timeline []uint64
+	// HasReferral charges a referral fee on every buy.
+	HasReferral bool
+}
+
+// CurveFillStep is the pool after one simulated buy. Fees are those of the buy; the other amounts are cumulative.
+type CurveFillStep struct {
+	Point       uint64
+	QuoteIn     *big.Int
+	BaseOut     *big.Int
+	SqrtPrice   *big.Int
+	Price       decimal.Decimal
+	MarketCap   decimal.Decimal
+	BaseSold    *big.Int
+	QuoteRaised *big.Int
+	ProtocolFee *big.Int
+	PartnerFee  *big.Int
+	CreatorFee  *big.Int
+	ReferralFee *big.Int
+}
+
+// CurveFillReport is a bonding curve filled from the start price to the migration price.
+// All amounts are in the smallest token units.
+type CurveFillReport struct {
+	Steps []CurveFillStep
+	// FeesInBase reports whether buy fees are charged in the base token rather than the quote token.
+	FeesInBase bool
+
+	InitialBaseSupply  *big.Int
+	BaseSold           *big.Int
+	QuoteRaised        *big.Int
+	MigrationPrice     decimal.Decimal
+	MigrationMarketCap decimal.Decimal
+
+	TotalProtocolFee *big.Int
+	TotalPartnerFee  *big.Int
+	TotalCreatorFee  *big.Int
+	TotalReferralFee *big.Int
+
+	// MigrationQuoteAmount and MigrationBaseAmount seed the DAMM pool.
+	MigrationQuoteAmount *big.Int
+	MigrationBaseAmount  *big.Int
+	PartnerMigrationFee  *big.Int
+	CreatorMigrationFee  *big.Int
+
+	// Surplus is the quote reserve above the migration quote threshold once the curve is complete.
+	Surplus         *big.Int
+	ProtocolSurplus *big.Int
+	PartnerSurplus  *big.Int
+	CreatorSurplus  *big.Int
+
+	LockedVesting *big.Int
+	// Leftover is the base left in the pool after migration; the leftover receiver withdraws it
+	// for fixed token supply configs.
+	Leftover *big.Int
+}
+
+// CurveBuilder names the helpers builder a designed curve comes from.
+type CurveBuilder string
+
+const (
+	CurveBuilderBuildCurve           CurveBuilder = "BuildCurve"
+	CurveBuilderWithMarketCap        CurveBuilder = "BuildCurveWithMarketCap"
+	CurveBuilderWithTwoSegments      CurveBuilder = "BuildCurveWithTwoSegments"
+	CurveBuilderWithMidPrice         CurveBuilder = "BuildCurveWithMidPrice"
+	CurveBuilderWithLiquidityWeights CurveBuilder = "BuildCurveWithLiquidityWeights"
+	CurveBuilderWithCustomSqrtPrices CurveBuilder = "BuildCurveWithCustomSqrtPrices"
+)
+
+// CurveDesignGoals are the targets DesignCurve searches the curve builders for.
+// Market caps, raise and buy size are in quote token units and percentages are of the total token supply.
+// A zero goal is left unconstrained.
+type CurveDesignGoals struct {
+	BuildCurveBaseParams
+	InitialMarketCap      float64
+	MigrationMarketCap    float64
+	TargetRaise           float64
+	PercentageSoldOnCurve float64
+	// VestingPercentage overrides LockedVestingParams.TotalLockedVestingAmount.
+	VestingPercentage float64
+	// MaxPriceImpact is the largest price increase, in percent, allowed for a first buy of ImpactBuyAmount.
+	ImpactBuyAmount float64
+	MaxPriceImpact  float64
+	// MaxCandidates limits the returned candidates; zero returns all of them.
+	MaxCandidates int
+	// LeftoverReceiver is used to validate fixed token supply configs.
+	LeftoverReceiver solana.PublicKey
+}
+
+// CurveCandidate is one designed curve and the goals it achieves.
+type CurveCandidate struct {
+	Builder CurveBuilder
+	Config  ConfigParameters
+
+	InitialMarketCap      decimal.Decimal
+	MigrationMarketCap    decimal.Decimal
+	Raise                 decimal.Decimal
+	PercentageSoldOnCurve decimal.Decimal
+	VestingPercentage     decimal.Decimal
+	PriceImpact           decimal.Decimal
+
+	// Score is the summed relative error against the goals; lower is better.
+	Score float64
+	// MeetsPriceImpact reports whether PriceImpact is within MaxPriceImpact.
+	MeetsPriceImpact bool
+	// ValidationError is the ValidateConfigParameters error, nil for a valid config.
+	ValidationError error
+}
+
+// ConfigFile is a config in human units for review and diffing: fees in bps, token amounts in
+// whole tokens, the pool creation fee in SOL, durations in seconds and enums by name.
+// Every curve point, the start price and the migration threshold can be given either exactly, as a
+// sqrt price, liquidity or raw threshold, or by price, market cap and segment base amount; market caps
+// are priced against BaseSupply, which defaults to the fixed pre-migration supply. The locked vesting
+// can be given by Percentage of BaseSupply instead of by amounts. LoadConfigFile fills in the missing
+// exact fields and rejects any set human field that does not match the value recomputed from them.
+type ConfigFile struct {
+	QuoteMint        solana.PublicKey `json:"quoteMint"`
+	FeeClaimer       solana.PublicKey `json:"feeClaimer"`
+	LeftoverReceiver solana.PublicKey `json:"leftoverReceiver"`
+
+	// TokenType is "spl" or "token2022".
+	TokenType    string       `json:"tokenType"`
+	TokenDecimal TokenDecimal `json:"tokenDecimal"`
+	QuoteDecimal TokenDecimal `json:"quoteDecimal"`
+	// TokenUpdateAuthority is "creatorUpdateAuthority", "immutable", "partnerUpdateAuthority",
+	// "creatorUpdateAndMintAuthority" or "partnerUpdateAndMintAuthority".
+	TokenUpdateAuthority string `json:"tokenUpdateAuthority"`
+	// ActivationType is "slot" or "timestamp".
+	ActivationType string `json:"activationType"`
+	// CollectFeeMode is "quoteToken" or "outputToken".
+	CollectFeeMode string `json:"collectFeeMode"`
+
+	BaseFee                     ConfigFileBaseFee     `json:"baseFee"`
+	DynamicFee                  *DynamicFeeParameters `json:"dynamicFee,omitempty"`
+	CreatorTradingFeePercentage uint8                 `json:"creatorTradingFeePercentage"`
+	EnableFirstSwapWithMinFee   bool                  `json:"enableFirstSwapWithMinFee"`
+	PoolCreationFee             decimal.Decimal       `json:"poolCreationFee"`
+
+	// TokenSupply is set for fixed supply tokens.
+	TokenSupply   *ConfigFileTokenSupply  `json:"tokenSupply,omitempty"`
+	LockedVesting ConfigFileLockedVesting `json:"lockedVesting"`
+
+	// BaseSupply is the base token minted into a pool, used for the market caps and vesting percentage.
+	BaseSupply       decimal.Decimal `json:"baseSupply"`
+	SqrtStartPrice   decimal.Decimal `json:"sqrtStartPrice"`
+	InitialPrice     decimal.Decimal `json:"initialPrice"`
+	InitialMarketCap decimal.Decimal `json:"initialMarketCap"`
+
+	Curve []ConfigFileCurvePoint `json:"curve"`
+
+	MigrationQuoteThreshold decimal.Decimal `json:"migrationQuoteThreshold"`
+	MigrationPrice          decimal.Decimal `json:"migrationPrice"`
+	MigrationMarketCap      decimal.Decimal `json:"migrationMarketCap"`
+
+	// MigrationOption is "metDamm" or "metDammV2".
+	MigrationOption string `json:"migrationOption"`
+	// MigrationFeeOption is "fixedBps25", "fixedBps30", "fixedBps100", "fixedBps200", "fixedBps400",
+	// "fixedBps600" or "customizable".
+	MigrationFeeOption                        string                         `json:"migrationFeeOption"`
+	MigrationFee                              MigrationFee                   `json:"migrationFee"`
+	PartnerLiquidityPercentage                uint8                          `json:"partnerLiquidityPercentage"`
+	PartnerPermanentLockedLiquidityPercentage uint8                          `json:"partnerPermanentLockedLiquidityPercentage"`
+	CreatorLiquidityPercentage                uint8                          `json:"creatorLiquidityPercentage"`
+	CreatorPermanentLockedLiquidityPercentage uint8                          `json:"creatorPermanentLockedLiquidityPercentage"`
+	PartnerLiquidityVesting                   LiquidityVestingInfoParameters `json:"partnerLiquidityVesting"`
+	CreatorLiquidityVesting                   LiquidityVestingInfoParameters `json:"creatorLiquidityVesting"`
+	MigratedPoolFee                           ConfigFileMigratedPoolFee      `json:"migratedPoolFee"`
+	// MigratedPoolBaseFeeMode is "feeTimeSchedulerLinear", "feeTimeSchedulerExponential", "rateLimiter",
+	// "feeMarketCapSchedulerLinear" or "feeMarketCapSchedulerExp".
+	MigratedPoolBaseFeeMode           string                                      `json:"migratedPoolBaseFeeMode"`
+	MigratedPoolMarketCapFeeScheduler MigratedPoolMarketCapFeeSchedulerParameters `json:"migratedPoolMarketCapFeeScheduler"`
+}
+
+// ConfigFileBaseFee is the base fee. The fee scheduler modes use the period fields and the
+// rate limiter uses the limiter fields.
+type ConfigFileBaseFee struct {
+	// BaseFeeMode is "feeSchedulerLinear", "feeSchedulerExponential" or "rateLimiter".
+	BaseFeeMode string          `json:"baseFeeMode"`
+	CliffFeeBps decimal.Decimal `json:"cliffFeeBps"`
+
+	NumberOfPeriod uint16 `json:"numberOfPeriod,omitempty"`
+	// PeriodFrequency is in seconds and must be a whole number of slots for slot activation.
+	PeriodFrequency *decimal.Decimal `json:"periodFrequency,omitempty"`
+	// ReductionFactor is a fee numerator for the linear scheduler and bps for the exponential one.
+	ReductionFactor uint64 `json:"reductionFactor,omitempty"`
+
+	FeeIncrementBps uint16 `json:"feeIncrementBps,omitempty"`
+	// MaxLimiterDuration is in seconds like PeriodFrequency.
+	MaxLimiterDuration *decimal.Decimal `json:"maxLimiterDuration,omitempty"`
+	ReferenceAmount    *decimal.Decimal `json:"referenceAmount,omitempty"`
+}
+
+type ConfigFileTokenSupply struct {
+	PreMigrationTokenSupply  decimal.Decimal `json:"preMigrationTokenSupply"`
+	PostMigrationTokenSupply decimal.Decimal `json:"postMigrationTokenSupply"`
+}
+
+type ConfigFileLockedVesting struct {
+	AmountPerPeriod                decimal.Decimal `json:"amountPerPeriod"`
+	NumberOfPeriod                 uint64          `json:"numberOfPeriod"`
+	Frequency                      uint64          `json:"frequency"`
+	CliffUnlockAmount              decimal.Decimal `json:"cliffUnlockAmount"`
+	CliffDurationFromMigrationTime uint64          `json:"cliffDurationFromMigrationTime"`
+	// Percentage is the locked vesting share of the base supply. When AmountPerPeriod is zero it is
+	// split over NumberOfPeriod after CliffUnlockAmount, the remainder unlocking at the cliff.
+	Percentage decimal.Decimal `json:"percentage"`
+}
+
+// ConfigFileCurvePoint is the curve segment ending at SqrtPrice. Price or MarketCap stand in for
+// SqrtPrice, and BaseAmount, the base token sold across the segment, for Liquidity.
+type ConfigFileCurvePoint struct {
+	SqrtPrice  decimal.Decimal `json:"sqrtPrice"`
+	Liquidity  decimal.Decimal `json:"liquidity"`
+	Price      decimal.Decimal `json:"price"`
+	MarketCap  decimal.Decimal `json:"marketCap"`
+	BaseAmount decimal.Decimal `json:"baseAmount"`
+}
+
+// ConfigFileMigratedPoolFee is the fee of the DAMM v2 pool created at migration.
+type ConfigFileMigratedPoolFee struct {
+	// CollectFeeMode is "quoteToken" or "outputToken".
+	CollectFeeMode string `json:"collectFeeMode"`
+	// DynamicFee is "disabled" or "enabled".
+	DynamicFee string `json:"dynamicFee"`
+	PoolFeeBps uint16 `json:"poolFeeBps"`
+}
+
+type CurveExportParams struct {
+	// SamplesPerSegment is the number of equal sqrt price steps each curve segment is sampled at.
+	SamplesPerSegment int
+	// QuoteDecimal is used for prices, market caps and quote amounts.
+	QuoteDecimal TokenDecimal
+}
+
+// CurveSample is the curve at one sqrt price. Amounts are in whole tokens and exclude fees;
+// BaseSold and QuoteRaised are cumulative from the start price.
+type CurveSample struct {
+	Segment     int             `json:"segment"`
+	SqrtPrice   *big.Int        `json:"sqrtPrice"`
+	Price       decimal.Decimal `json:"price"`
+	MarketCap   decimal.Decimal `json:"marketCap"`
+	BaseSold    decimal.Decimal `json:"baseSold"`
+	QuoteRaised decimal.Decimal `json:"quoteRaised"`
+	// SupplySoldPercentage is BaseSold as a share of BaseSupply.
+	SupplySoldPercentage decimal.Decimal `json:"supplySoldPercentage"`
+	// SegmentBoundary marks the start price and the end of each segment.
+	SegmentBoundary bool `json:"segmentBoundary"`
+	Migration       bool `json:"migration"`
+}
+
+// CurveDataset is a bonding curve sampled from the start price to the migration price.
+type CurveDataset struct {
+	Samples []CurveSample `json:"samples"`
+	// BaseSupply is the base token minted into a pool, used for market caps and percentages.
+	BaseSupply              decimal.Decimal `json:"baseSupply"`
+	MigrationBaseAmount     decimal.Decimal `json:"migrationBaseAmount"`
+	LockedVesting           decimal.Decimal `json:"lockedVesting"`
+	LockedVestingPercentage decimal.Decimal `json:"lockedVestingPercentage"`
+}
+
+// FeeAmounts is a fee in base and quote smallest units.
+type FeeAmounts struct {
+	Base  *big.Int `json:"base,omitempty"`
+	Quote *big.Int `json:"quote"`
+	// Value is Quote plus Base at the current curve price, in quote smallest units. It is nil when fees are not valued.
+	Value *big.Int `json:"value,omitempty"`
+}
+
+// PartyFees are the fees of one party. Claimed is the lifetime earned fee less Unclaimed.
+type PartyFees struct {
+	Unclaimed FeeAmounts `json:"unclaimed"`
+	Claimed   FeeAmounts `json:"claimed"`
+}
+
+// PoolFeeMetrics are the trading fees of a pool per party. The pool only records the lifetime trading fee
+// of partner and creator together, so their lifetime fees are split by the config's creator trading fee percentage.
+type PoolFeeMetrics struct {
+	Pool      solana.PublicKey `json:"pool"`
+	Config    solana.PublicKey `json:"config"`
+	BaseMint  solana.PublicKey `json:"baseMint"`
+	QuoteMint solana.PublicKey `json:"quoteMint"`
+	Partner   PartyFees        `json:"partner"`
+	Creator   PartyFees        `json:"creator"`
+	Protocol  PartyFees        `json:"protocol"`
+}
+
+// QuoteFeeTotals sums the fees of pools sharing a quote mint. Base sums the smallest units of each
+// pool's own base token; Value prices them in quote.
+type QuoteFeeTotals struct {
+	QuoteMint solana.PublicKey `json:"quoteMint"`
+	Pools     int              `json:"pools"`
+	Partner   PartyFees        `json:"partner"`
+	Creator   PartyFees        `json:"creator"`
+	Protocol  PartyFees        `json:"protocol"`
+}
+
+// FeeMetrics are the fee metrics of a set of pools with their totals per quote mint.
+type FeeMetrics struct {
+	Pools  []PoolFeeMetrics `json:"pools"`
+	Totals []QuoteFeeTotals `json:"totals"`
+}
+
+// ProtocolFeeOwed is what the protocol can claim from a pool. PoolCreationFee is the protocol share
+// of the pool creation fee in lamports and 0 once claimed.
+type ProtocolFeeOwed struct {
+	Pool            solana.PublicKey `json:"pool"`
+	Config          solana.PublicKey `json:"config"`
+	BaseMint        solana.PublicKey `json:"baseMint"`
+	QuoteMint       solana.PublicKey `json:"quoteMint"`
+	BaseFee         uint64           `json:"baseFee"`
+	QuoteFee        uint64           `json:"quoteFee"`
+	PoolCreationFee uint64           `json:"poolCreationFee"`
+}
+
+// VestingEscrow is the escrow account of the locker program holding the locked vesting of a pool.
+type VestingEscrow struct {
+	Recipient           solana.PublicKey `json:"recipient"`
+	TokenMint           solana.PublicKey `json:"tokenMint"`
+	Creator             solana.PublicKey `json:"creator"`
+	Base                solana.PublicKey `json:"base"`
+	EscrowBump          uint8            `json:"escrowBump"`
+	UpdateRecipientMode uint8            `json:"updateRecipientMode"`
+	CancelMode          uint8            `json:"cancelMode"`
+	TokenProgramFlag    uint8            `json:"tokenProgramFlag"`
+	Padding0            [4]uint8         `json:"padding0"`
+	CliffTime           uint64           `json:"cliffTime"`
+	Frequency           uint64           `json:"frequency"`
+	CliffUnlockAmount   uint64           `json:"cliffUnlockAmount"`
+	AmountPerPeriod     uint64           `json:"amountPerPeriod"`
+	NumberOfPeriod      uint64           `json:"numberOfPeriod"`
+	TotalClaimedAmount  uint64           `json:"totalClaimedAmount"`
+	VestingStartTime    uint64           `json:"vestingStartTime"`
+	CancelledAt         uint64           `json:"cancelledAt"`
+	Padding1            uint64           `json:"padding1"`
+	Padding             [80]uint8        `json:"padding"`
+}
+
+// VestingUnlock is one unlock of a vesting schedule.
+type VestingUnlock struct {
+	Time   uint64 `json:"time"`
+	Amount uint64 `json:"amount"`
+	// Cumulative is the total unlocked once this unlock happened.
+	Cumulative uint64 `json:"cumulative"`
+}
+
+// CreatorVestingStatus is the locked vesting of a pool's creator. While the locker has not been created
+// Escrow is zero, TotalLocked comes from the config and the schedule, which starts at migration, is empty.
+type CreatorVestingStatus struct {
+	Pool         solana.PublicKey `json:"pool"`
+	Escrow       solana.PublicKey `json:"escrow"`
+	EscrowToken  solana.PublicKey `json:"escrowToken"`
+	Recipient    solana.PublicKey `json:"recipient"`
+	TokenMint    solana.PublicKey `json:"tokenMint"`
+	Now          uint64           `json:"now"`
+	TotalLocked  uint64           `json:"totalLocked"`
+	Unlocked     uint64           `json:"unlocked"`
+	Claimed      uint64           `json:"claimed"`
+	ClaimableNow uint64           `json:"claimableNow"`
+	NextUnlock   *VestingUnlock   `json:"nextUnlock,omitempty"`
+	Cancelled    bool             `json:"cancelled"`
+	Schedule     []VestingUnlock  `json:"schedule"`
+}
+
+// Checked narrowing of program integers, see internal/checked.
+var (
+	ErrMathOverflow = checked.ErrMathOverflow
+	U256Max         = checked.U256Max
+)
+
+// MathOverflowError names the operation that overflowed; it matches ErrMathOverflow with errors.Is.
+type MathOverflowError = checked.MathOverflowError
+
+func NewMathOverflowError(op string) error { return checked.NewMathOverflowError(op) }
+
+// CheckedU64 narrows v to u64, failing when it does not fit. A nil v is zero.
+func CheckedU64(v *big.Int, op string) (uint64, error) { return checked.U64(v, op) }
+
+func CheckedU128(v *big.Int, op string) error { return checked.U128(v, op) }
+
+func CheckedU256(v *big.Int, op string) error { return checked.U256(v, op) }
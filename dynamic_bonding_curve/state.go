@@ -29,6 +29,20 @@ func (s *DynamicBondingCurve) GetPoolConfig(ctx context.Context, configAddress s
 	return parsed, nil
 }
 
+// GetPoolConfigFile fetches a pool config and decodes it into a config file in human units,
+// reading the quote decimal from the quote mint.
+func (s *DynamicBondingCurve) GetPoolConfigFile(ctx context.Context, configAddress solanago.PublicKey) (ConfigFile, error) {
+	config, err := s.GetPoolConfig(ctx, configAddress)
+	if err != nil {
+		return ConfigFile{}, err
+	}
+	quoteDecimal, err := helpers.GetTokenDecimals(ctx, s.RPC, config.QuoteMint)
+	if err != nil {
+		return ConfigFile{}, err
+	}
+	return helpers.ConfigFileFromPoolConfig(config, TokenDecimal(quoteDecimal))
+}
+
 func (s *DynamicBondingCurve) GetPoolConfigs(ctx context.Context) ([]ProgramAccount[PoolConfig], error) {
 	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPoolConfig, nil)
 	accounts, err := s.RPC.GetProgramAccountsWithOpts(ctx, helpers.DynamicBondingCurveProgramID, &rpc.GetProgramAccountsOpts{Commitment: s.Commitment, Filters: filters})
@@ -67,6 +81,27 @@ func (s *DynamicBondingCurve) GetPoolConfigsByOwner(ctx context.Context, owner s
 	return out, nil
 }
 
+// GetPoolConfigsByFeeClaimer lists the configs whose fee claimer is feeClaimer, filtered on the RPC side.
+func (s *DynamicBondingCurve) GetPoolConfigsByFeeClaimer(ctx context.Context, feeClaimer solanago.PublicKey) ([]ProgramAccount[PoolConfig], error) {
+	filters := helpers.CreateProgramAccountFilter(helpers.AccountKeyPoolConfig, &helpers.Filter{
+		Owner:  feeClaimer,
+		Offset: helpers.ComputeStructOffset(new(shared.PoolConfig), "FeeClaimer"),
+	})
+	accounts, err := s.RPC.GetProgramAccountsWithOpts(ctx, helpers.DynamicBondingCurveProgramID, &rpc.GetProgramAccountsOpts{Commitment: s.Commitment, Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ProgramAccount[PoolConfig], 0)
+	for _, acc := range accounts {
+		parsed, err := dbcidl.ParseAccount_PoolConfig(acc.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		out = append(out, ProgramAccount[PoolConfig]{Pubkey: acc.Pubkey, Account: parsed})
+	}
+	return out, nil
+}
+
 func (s *DynamicBondingCurve) GetPool(ctx context.Context, poolAddress solanago.PublicKey) (*VirtualPool, error) {
 	acc, err := s.RPC.GetAccountInfoWithOpts(ctx, poolAddress, &rpc.GetAccountInfoOpts{Commitment: s.Commitment})
 	if err != nil {
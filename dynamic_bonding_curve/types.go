@@ -2,11 +2,18 @@ package dynamic_bonding_curve
 
 import (
 	"math/big"
+	"time"
 
 	solanago "github.com/gagliardetto/solana-go"
 	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
 )
 
+// ErrMathOverflow is matched by every MathOverflowError returned by checked arithmetic.
+var ErrMathOverflow = shared.ErrMathOverflow
+
+// MathOverflowError names the operation that overflowed.
+type MathOverflowError = shared.MathOverflowError
+
 // IDL type aliases.
 type ConfigParameters = shared.ConfigParameters
 
@@ -56,6 +63,10 @@ type MeteoraDammMigrationMetadata = shared.MeteoraDammMigrationMetadata
 
 type LockEscrow = shared.LockEscrow
 
+type ClaimFeeOperator = shared.ClaimFeeOperator
+
+type VestingEscrow = shared.VestingEscrow
+
 type PartnerMetadata = shared.PartnerMetadata
 
 type VirtualPoolMetadata = shared.VirtualPoolMetadata
@@ -195,10 +206,65 @@ const (
 
 type MigrationFeeWithdrawStatus = shared.MigrationFeeWithdrawStatus
 
+type CreationFeeBits = shared.CreationFeeBits
+
+// MigrationStep is one transaction of the migration sequence.
+// MigrationKeeper runs the permissionless steps; surplus and migration fee withdrawals need the receiving party to sign.
+type MigrationStep uint8
+
+const (
+	MigrationStepCreateLocker MigrationStep = iota
+	MigrationStepCreateMetadata
+	MigrationStepMigrate
+	MigrationStepLockPartnerLp
+	MigrationStepLockCreatorLp
+	MigrationStepClaimPartnerLp
+	MigrationStepClaimCreatorLp
+	MigrationStepWithdrawLeftover
+	MigrationStepPartnerWithdrawSurplus
+	MigrationStepCreatorWithdrawSurplus
+	MigrationStepPartnerWithdrawMigrationFee
+	MigrationStepCreatorWithdrawMigrationFee
+)
+
+// MigrationPhase is the coarse migration state of a virtual pool.
+type MigrationPhase uint8
+
+const (
+	// MigrationPhaseBondingCurve means the quote reserve has not reached the migration threshold.
+	MigrationPhaseBondingCurve MigrationPhase = iota
+	// MigrationPhaseCurveComplete means the curve is complete and the locked vesting escrow is not created yet.
+	MigrationPhaseCurveComplete
+	// MigrationPhaseReadyToMigrate means the pool can be migrated once its migration metadata exists.
+	MigrationPhaseReadyToMigrate
+	// MigrationPhaseMigrated means the DAMM pool exists and post-migration steps may remain.
+	MigrationPhaseMigrated
+	// MigrationPhaseFinished means no step remains.
+	MigrationPhaseFinished
+)
+
 // Param/DTO structs mirroring TS types.
 
 type CreateConfigParams = shared.CreateConfigParams
 
+type ConfigFile = shared.ConfigFile
+
+type FeeAmounts = shared.FeeAmounts
+
+type PartyFees = shared.PartyFees
+
+type PoolFeeMetrics = shared.PoolFeeMetrics
+
+type QuoteFeeTotals = shared.QuoteFeeTotals
+
+type FeeMetrics = shared.FeeMetrics
+
+type ProtocolFeeOwed = shared.ProtocolFeeOwed
+
+type VestingUnlock = shared.VestingUnlock
+
+type CreatorVestingStatus = shared.CreatorVestingStatus
+
 // BaseFee equals BaseFeeConfig without padding.
 
 type BaseFee = shared.BaseFeeConfig
@@ -494,6 +560,90 @@ type ClaimPartnerPoolCreationFeeParams struct {
 	FeeReceiver solanago.PublicKey
 }
 
+type ClaimAllFeesParams struct {
+	// Owner is the partner fee claimer or the pool creator and signs every transaction.
+	Owner solanago.PublicKey
+	Payer solanago.PublicKey
+	// Receiver gets the claimed tokens; defaults to Owner.
+	Receiver solanago.PublicKey
+	// Trading fees of a pool are claimed once an unclaimed amount reaches its threshold. A threshold
+	// of 0 is not set; with neither set every pool with a fee above 0 is claimed.
+	MinBaseFee  uint64
+	MinQuoteFee uint64
+	// WithdrawSurplus and WithdrawMigrationFee add the surplus and migration fee withdrawals of migrated pools.
+	WithdrawSurplus      bool
+	WithdrawMigrationFee bool
+	// ComputeUnitLimit is set on every transaction when not 0.
+	ComputeUnitLimit uint32
+	// MaxTransactionSize bounds the serialized transaction size; defaults to 1232 bytes.
+	MaxTransactionSize int
+}
+
+// FeeClaimBatch is one unsigned transaction of a bulk claim and the pools it covers.
+type FeeClaimBatch struct {
+	Transaction *solanago.Transaction
+	Pools       []solanago.PublicKey
+}
+
+type ClaimAllFeesResult struct {
+	Batches []FeeClaimBatch
+	// Skipped lists the pools with nothing to claim or fees below the thresholds.
+	Skipped []solanago.PublicKey
+}
+
+type CreateClaimFeeOperatorParams struct {
+	Operator solanago.PublicKey
+	// Admin is the program admin and signs the instruction.
+	Admin solanago.PublicKey
+	Payer solanago.PublicKey
+}
+
+type CloseClaimFeeOperatorParams struct {
+	Operator     solanago.PublicKey
+	Admin        solanago.PublicKey
+	RentReceiver solanago.PublicKey
+}
+
+type ClaimProtocolFeeParams struct {
+	Pool     solanago.PublicKey
+	Operator solanago.PublicKey
+	Payer    solanago.PublicKey
+	// MaxBaseAmount and MaxQuoteAmount default to the unclaimed protocol fees when nil.
+	MaxBaseAmount  *big.Int
+	MaxQuoteAmount *big.Int
+	// Treasury owns the receiving token accounts; defaults to helpers.ProtocolTreasury.
+	Treasury solanago.PublicKey
+}
+
+type ClaimProtocolPoolCreationFeeParams struct {
+	Pool     solanago.PublicKey
+	Operator solanago.PublicKey
+	// Treasury defaults to helpers.ProtocolTreasury.
+	Treasury solanago.PublicKey
+}
+
+type ClaimAllProtocolFeesParams struct {
+	Operator solanago.PublicKey
+	Payer    solanago.PublicKey
+	Treasury solanago.PublicKey
+	// Configs limits the claim to pools of these configs; all pools when empty.
+	Configs []solanago.PublicKey
+	// Protocol trading fees follow the thresholds of ClaimAllFeesParams.
+	MinBaseFee  uint64
+	MinQuoteFee uint64
+	// ClaimPoolCreationFee adds the protocol share of unclaimed pool creation fees.
+	ClaimPoolCreationFee bool
+	ComputeUnitLimit     uint32
+	MaxTransactionSize   int
+}
+
+type ClaimCreatorVestingParams struct {
+	Pool  solanago.PublicKey
+	Payer solanago.PublicKey
+	// MaxAmount defaults to everything claimable when nil.
+	MaxAmount *big.Int
+}
+
 // Interfaces.
 type FeeResult struct {
 	Amount      *big.Int
@@ -513,3 +663,78 @@ type ProgramAccount[T any] struct {
 	Pubkey  solanago.PublicKey
 	Account *T
 }
+
+// MigrationJob is the persisted migration progress of one virtual pool.
+type MigrationJob struct {
+	Pool            solanago.PublicKey   `json:"pool"`
+	DammConfig      solanago.PublicKey   `json:"dammConfig"`
+	MigrationOption MigrationOption      `json:"migrationOption"`
+	Completed       []MigrationStep      `json:"completed"`
+	Signatures      []solanago.Signature `json:"signatures"`
+	// Attempts counts consecutive failures of the current step.
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError"`
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type MigrationKeeperConfig struct {
+	// Store persists jobs; an in-memory store is used when nil.
+	Store MigrationStore
+	// Payer pays for and signs every migration transaction.
+	Payer solanago.PublicKey
+	// Signer signs migration transactions.
+	Signer func(key solanago.PublicKey) *solanago.PrivateKey
+	// Configs lists pool configs whose current and future pools are tracked.
+	Configs []solanago.PublicKey
+	// MaxAttempts bounds consecutive failures of a step in one pass; 0 retries until ctx is done.
+	MaxAttempts int
+	// RetryDelay is the wait between retries; defaults to 5 seconds.
+	RetryDelay time.Duration
+	// OnStep is called after every confirmed step.
+	OnStep func(job MigrationJob, step MigrationStep)
+}
+
+// MigrationStepStatus is one remaining migration step.
+type MigrationStepStatus struct {
+	Step MigrationStep `json:"step"`
+	// Ready reports whether the step can be sent now; otherwise an earlier step or phase must land first.
+	Ready bool `json:"ready"`
+	// Signer must sign the step; zero means anyone may send it.
+	Signer solanago.PublicKey `json:"signer"`
+	// Recipient receives the tokens, LP or escrow of the step; zero when nobody does.
+	Recipient solanago.PublicKey `json:"recipient"`
+}
+
+// MigrationStatus interprets the migration flags of a virtual pool.
+type MigrationStatus struct {
+	Pool                    solanago.PublicKey `json:"pool"`
+	Config                  solanago.PublicKey `json:"config"`
+	Phase                   MigrationPhase     `json:"phase"`
+	MigrationProgress       MigrationProgress  `json:"migrationProgress"`
+	MigrationOption         MigrationOption    `json:"migrationOption"`
+	QuoteReserve            uint64             `json:"quoteReserve"`
+	MigrationQuoteThreshold uint64             `json:"migrationQuoteThreshold"`
+	// Remaining lists the steps still to be done, in execution order.
+	Remaining []MigrationStepStatus `json:"remaining"`
+
+	// LockEscrow is the locked vesting escrow; zero when the config has no locked vesting.
+	LockEscrow        solanago.PublicKey `json:"lockEscrow"`
+	MigrationMetadata solanago.PublicKey `json:"migrationMetadata"`
+	// DammConfig and DammPool are zero for the customizable migration fee option.
+	DammConfig solanago.PublicKey `json:"dammConfig"`
+	DammPool   solanago.PublicKey `json:"dammPool"`
+
+	// DAMM v1 only.
+	LpMint            solanago.PublicKey `json:"lpMint"`
+	PartnerLockEscrow solanago.PublicKey `json:"partnerLockEscrow"`
+	CreatorLockEscrow solanago.PublicKey `json:"creatorLockEscrow"`
+
+	// DAMM v2 only: the positions of the migrated pool, including those opened after migration.
+	Positions []MigratedPosition `json:"positions"`
+}
+
+type MigratedPosition struct {
+	Position solanago.PublicKey `json:"position"`
+	NftMint  solanago.PublicKey `json:"nftMint"`
+}
@@ -0,0 +1,102 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+)
+
+// GetVestingEscrow returns the locker escrow at escrowAddress, or nil when it does not exist.
+func (s *DynamicBondingCurve) GetVestingEscrow(ctx context.Context, escrowAddress solanago.PublicKey) (*VestingEscrow, error) {
+	acc, err := s.RPC.GetAccountInfoWithOpts(ctx, escrowAddress, &rpc.GetAccountInfoOpts{Commitment: s.Commitment})
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if acc == nil || acc.Value == nil {
+		return nil, nil
+	}
+	return helpers.ParseVestingEscrow(acc.Value.Data.GetBinary())
+}
+
+// GetCreatorVestingStatus reports the locked vesting of a pool: the total locked, what is unlocked,
+// claimed and claimable now by the recipient, and the unlock schedule.
+func (s *DynamicBondingCurve) GetCreatorVestingStatus(ctx context.Context, pool solanago.PublicKey) (CreatorVestingStatus, error) {
+	poolState, err := s.GetPool(ctx, pool)
+	if err != nil {
+		return CreatorVestingStatus{}, err
+	}
+	config, err := s.GetPoolConfig(ctx, poolState.Config)
+	if err != nil {
+		return CreatorVestingStatus{}, err
+	}
+	vesting := config.LockedVestingConfig
+	if vesting.AmountPerPeriod == 0 && vesting.CliffUnlockAmount == 0 {
+		return CreatorVestingStatus{}, fmt.Errorf("config %s has no locked vesting", poolState.Config)
+	}
+
+	escrowAddress := helpers.DeriveEscrow(helpers.DeriveBaseKeyForLocker(pool))
+	escrow, err := s.GetVestingEscrow(ctx, escrowAddress)
+	if err != nil {
+		return CreatorVestingStatus{}, err
+	}
+	if escrow == nil {
+		return CreatorVestingStatus{
+			Pool:        pool,
+			Recipient:   poolState.Creator,
+			TokenMint:   poolState.BaseMint,
+			TotalLocked: vesting.CliffUnlockAmount + vesting.AmountPerPeriod*vesting.NumberOfPeriod,
+			Schedule:    []VestingUnlock{},
+		}, nil
+	}
+
+	escrowToken, err := helpers.FindAssociatedTokenAddress(escrowAddress, escrow.TokenMint, helpers.GetTokenProgram(TokenType(escrow.TokenProgramFlag)))
+	if err != nil {
+		return CreatorVestingStatus{}, err
+	}
+	now := CurrentPointForActivation(ctx, s.RPC, s.Commitment, ActivationTypeTimestamp).Uint64()
+	return helpers.VestingStatusFromEscrow(pool, escrowAddress, escrowToken, escrow, now), nil
+}
+
+// ClaimCreatorVesting builds the locker claim of the unlocked vesting of a pool. The escrow recipient,
+// the pool creator unless changed, signs and receives the tokens in its associated token account.
+func (s *DynamicBondingCurve) ClaimCreatorVesting(ctx context.Context, params ClaimCreatorVestingParams) (pre []solanago.Instruction, ix solanago.Instruction, err error) {
+	escrowAddress := helpers.DeriveEscrow(helpers.DeriveBaseKeyForLocker(params.Pool))
+	escrow, err := s.GetVestingEscrow(ctx, escrowAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if escrow == nil {
+		return nil, nil, fmt.Errorf("locker of pool %s has not been created", params.Pool)
+	}
+
+	maxAmount := uint64(math.MaxUint64)
+	if params.MaxAmount != nil {
+		if maxAmount, err = helpers.BigIntToU64(params.MaxAmount); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tokenProgram := helpers.GetTokenProgram(TokenType(escrow.TokenProgramFlag))
+	escrowToken, err := helpers.FindAssociatedTokenAddress(escrowAddress, escrow.TokenMint, tokenProgram)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipientToken, err := helpers.FindAssociatedTokenAddress(escrow.Recipient, escrow.TokenMint, tokenProgram)
+	if err != nil {
+		return nil, nil, err
+	}
+	pre = []solanago.Instruction{
+		helpers.CreateAssociatedTokenAccountIdempotentInstruction(params.Payer, recipientToken, escrow.Recipient, escrow.TokenMint, tokenProgram),
+	}
+	ix = helpers.ClaimVestingInstruction(escrowAddress, escrow.TokenMint, escrowToken, escrow.Recipient, recipientToken, tokenProgram, maxAmount)
+	return pre, ix, nil
+}
@@ -0,0 +1,78 @@
+// Package checked narrows big integers to the fixed-width integers of the on-chain programs,
+// failing the way their checked arithmetic does instead of wrapping.
+package checked
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrMathOverflow is returned when a value leaves the range allowed by the program's checked arithmetic.
+var ErrMathOverflow = errors.New("math overflow")
+
+// U256Max is the largest value held by an on-chain u256 intermediate.
+var U256Max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+var (
+	u64Max  = new(big.Int).SetUint64(^uint64(0))
+	u128Max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+// MathOverflowError names the operation that overflowed; it matches ErrMathOverflow with errors.Is.
+type MathOverflowError struct {
+	Op string
+}
+
+func (e *MathOverflowError) Error() string {
+	return "math overflow: " + e.Op
+}
+
+func (e *MathOverflowError) Is(target error) bool {
+	return target == ErrMathOverflow
+}
+
+// NewMathOverflowError returns a MathOverflowError for op.
+func NewMathOverflowError(op string) error {
+	return &MathOverflowError{Op: op}
+}
+
+// U64 narrows v to u64, failing when it is negative or above the u64 maximum. A nil v is zero.
+func U64(v *big.Int, op string) (uint64, error) {
+	if v == nil {
+		return 0, nil
+	}
+	if err := inRange(v, u64Max, op); err != nil {
+		return 0, err
+	}
+	return v.Uint64(), nil
+}
+
+// U128 fails when v does not fit in a u128.
+func U128(v *big.Int, op string) error {
+	return inRange(v, u128Max, op)
+}
+
+// U256 fails when v does not fit in a u256.
+func U256(v *big.Int, op string) error {
+	return inRange(v, U256Max, op)
+}
+
+// AllU128 fails for op when any value does not fit in a u128.
+func AllU128(op string, values ...*big.Int) error {
+	for _, v := range values {
+		if err := U128(v, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inRange(v, max *big.Int, op string) error {
+	if v == nil {
+		return nil
+	}
+	if v.Sign() < 0 || v.Cmp(max) > 0 {
+		return NewMathOverflowError(op)
+	}
+	return nil
+}
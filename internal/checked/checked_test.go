@@ -0,0 +1,72 @@
+package checked
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func pow2(n uint) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), n)
+}
+
+func minusOne(v *big.Int) *big.Int {
+	return new(big.Int).Sub(v, big.NewInt(1))
+}
+
+func TestBoundaries(t *testing.T) {
+	u64 := func(v *big.Int) error {
+		_, err := U64(v, "u64")
+		return err
+	}
+	u128 := func(v *big.Int) error { return U128(v, "u128") }
+	u256 := func(v *big.Int) error { return U256(v, "u256") }
+
+	tests := []struct {
+		name     string
+		check    func(*big.Int) error
+		value    *big.Int
+		overflow bool
+	}{
+		{"u64 nil", u64, nil, false},
+		{"u64 zero", u64, big.NewInt(0), false},
+		{"u64 max", u64, minusOne(pow2(64)), false},
+		{"u64 max+1", u64, pow2(64), true},
+		{"u64 negative", u64, big.NewInt(-1), true},
+		{"u128 max", u128, minusOne(pow2(128)), false},
+		{"u128 max+1", u128, pow2(128), true},
+		{"u128 negative", u128, big.NewInt(-1), true},
+		{"u256 max", u256, minusOne(pow2(256)), false},
+		{"u256 max+1", u256, pow2(256), true},
+		{"u256 negative", u256, big.NewInt(-1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.value)
+			if tt.overflow != errors.Is(err, ErrMathOverflow) {
+				t.Fatalf("error = %v, want overflow %v", err, tt.overflow)
+			}
+			if !tt.overflow && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestU64Value(t *testing.T) {
+	v, err := U64(minusOne(pow2(64)), "u64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != ^uint64(0) {
+		t.Fatalf("U64() = %d, want %d", v, ^uint64(0))
+	}
+}
+
+func TestMathOverflowErrorOp(t *testing.T) {
+	_, err := U64(pow2(64), "swap amount in")
+	var overflow *MathOverflowError
+	if !errors.As(err, &overflow) || overflow.Op != "swap amount in" {
+		t.Fatalf("U64() error = %v, want op %q", err, "swap amount in")
+	}
+}
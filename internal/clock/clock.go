@@ -0,0 +1,10 @@
+// Package clock holds the nominal chain timing used to convert between durations and slots.
+package clock
+
+import "time"
+
+// SlotDurationMillis is the nominal slot time in milliseconds.
+const SlotDurationMillis = 400
+
+// SlotDuration is the nominal slot time.
+const SlotDuration = SlotDurationMillis * time.Millisecond
@@ -0,0 +1,50 @@
+// Package dynamicfee holds the volatility tracking shared by the dynamic fee of the DAMM v2 and
+// dynamic bonding curve programs, which implement the same Liquidity Book style accumulator.
+package dynamicfee
+
+import "math/big"
+
+const (
+	basisPointMax = 10_000
+	scaleOffset   = 64
+)
+
+var oneQ64 = new(big.Int).Lsh(big.NewInt(1), scaleOffset)
+
+// DecayedReference returns the volatility reference to keep once the filter period has elapsed:
+// the accumulator scaled by reductionFactor (bps) inside the decay period, zero after it.
+func DecayedReference(volatilityAccumulator *big.Int, reductionFactor, elapsed, decayPeriod uint64) *big.Int {
+	if elapsed >= decayPeriod {
+		return big.NewInt(0)
+	}
+	reference := new(big.Int).Mul(volatilityAccumulator, new(big.Int).SetUint64(reductionFactor))
+	return reference.Div(reference, big.NewInt(basisPointMax))
+}
+
+// Accumulator returns the volatility accumulator after the price moved to sqrtPrice, capped at
+// maxVolatilityAccumulator.
+func Accumulator(binStep, sqrtPrice, sqrtPriceReference, volatilityReference *big.Int, maxVolatilityAccumulator uint64) *big.Int {
+	accumulator := DeltaBinID(binStep, sqrtPrice, sqrtPriceReference)
+	accumulator.Mul(accumulator, big.NewInt(basisPointMax))
+	accumulator.Add(accumulator, volatilityReference)
+	if maxAccumulator := new(big.Int).SetUint64(maxVolatilityAccumulator); accumulator.Cmp(maxAccumulator) > 0 {
+		return maxAccumulator
+	}
+	return accumulator
+}
+
+// DeltaBinID returns twice the number of bin steps between two sqrt prices.
+func DeltaBinID(binStep, sqrtPriceA, sqrtPriceB *big.Int) *big.Int {
+	upper, lower := sqrtPriceA, sqrtPriceB
+	if upper.Cmp(lower) < 0 {
+		upper, lower = lower, upper
+	}
+	if lower.Sign() == 0 || binStep.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	priceRatio := new(big.Int).Lsh(upper, scaleOffset)
+	priceRatio.Div(priceRatio, lower)
+	deltaBin := priceRatio.Sub(priceRatio, oneQ64)
+	deltaBin.Div(deltaBin, binStep)
+	return deltaBin.Mul(deltaBin, big.NewInt(2))
+}
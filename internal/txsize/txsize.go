@@ -0,0 +1,39 @@
+// Package txsize measures serialized legacy transactions against the packet size limit.
+package txsize
+
+import (
+	solanago "github.com/gagliardetto/solana-go"
+)
+
+// MaxTransactionSize is the maximum serialized size of a legacy transaction in bytes.
+const MaxTransactionSize = 1232
+
+// Of returns the serialized size of tx once every required signature is attached.
+func Of(tx *solanago.Transaction) (int, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	signatures := int(tx.Message.Header.NumRequiredSignatures)
+	return compactU16Len(signatures) + signatures*solanago.SignatureLength + len(message), nil
+}
+
+// OfInstructions returns the serialized size of a signed legacy transaction built from instructions.
+func OfInstructions(instructions []solanago.Instruction, payer solanago.PublicKey) (int, error) {
+	tx, err := solanago.NewTransaction(instructions, solanago.Hash{}, solanago.TransactionPayer(payer))
+	if err != nil {
+		return 0, err
+	}
+	return Of(tx)
+}
+
+func compactU16Len(v int) int {
+	switch {
+	case v < 0x80:
+		return 1
+	case v < 0x4000:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -76,7 +76,7 @@ func TestAddLiquidity(t *testing.T) {
 	}
 
 	inAmount := new(big.Int).SetUint64(0.1 * 1e9)
-	depositQuote := cpAmm.GetDepositQuote(dammv2.GetDepositQuoteParams{
+	depositQuote, err := cpAmm.GetDepositQuote(dammv2.GetDepositQuoteParams{
 		InAmount:        inAmount,
 		IsTokenA:        true,
 		MinSqrtPrice:    pools[0].Account.SqrtMinPrice.BigInt(),
@@ -85,6 +85,9 @@ func TestAddLiquidity(t *testing.T) {
 		InputTokenInfo:  inputTokenInfo,
 		OutputTokenInfo: outputTokenInfo,
 	})
+	if err != nil {
+		t.Fatal("cpAmm.GetDepositQuote() fail", err)
+	}
 
 	txBuilder, err = cpAmm.AddLiquidity(ctx, dammv2.AddLiquidityParams{
 		Owner:                 owner,
@@ -0,0 +1,66 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+)
+
+func TestPoolBaseFeeProjection(t *testing.T) {
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	projection, err := cpAmm.ProjectBaseFee(dammv2.ProjectBaseFeeParams{
+		PoolState: pool.Account,
+		// FromPoint     *big.Int
+		// ToPoint       *big.Int
+		// Step          *big.Int
+		// SqrtPricePath []*big.Int
+		// Amount        *big.Int
+	})
+	if err != nil {
+		t.Fatal("cpAmm.ProjectBaseFee() fail", err)
+	}
+
+	fmt.Println("base fee mode:", projection.BaseFeeMode, "static after:", projection.StaticPoint)
+	for _, point := range projection.Points {
+		fmt.Println("point:", point.Point, "fee bps:", point.FeeBps)
+	}
+}
+
+func TestBaseFeeParamsProjection(t *testing.T) {
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	baseFee, err := helpers.GetFeeTimeSchedulerParams(5000, 25, dammv2.BaseFeeModeFeeTimeSchedulerExponential, 10, 600)
+	if err != nil {
+		t.Fatal("helpers.GetFeeTimeSchedulerParams() fail", err)
+	}
+
+	projection, err := cpAmm.ProjectBaseFee(dammv2.ProjectBaseFeeParams{
+		BaseFee:         &baseFee,
+		ActivationPoint: big.NewInt(0),
+	})
+	if err != nil {
+		t.Fatal("cpAmm.ProjectBaseFee() fail", err)
+	}
+
+	fmt.Println("min fee:", projection.MinFeeNumerator, "max fee:", projection.MaxFeeNumerator, "static after:", projection.StaticPoint)
+	for _, point := range projection.Points {
+		fmt.Println("point:", point.Point, "fee bps:", point.FeeBps)
+	}
+}
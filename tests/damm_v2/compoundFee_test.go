@@ -0,0 +1,97 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestCompoundFee(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	positions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+	if len(positions) == 0 {
+		t.Fatal("no position found")
+	}
+	position := positions[0]
+
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationType(pool.Account.ActivationType))
+
+	params := dammv2.CompoundPositionFeeParams{
+		Owner:              owner,
+		Pool:               pool.PublicKey,
+		PoolState:          pool.Account,
+		Position:           position.Position,
+		PositionNftAccount: position.PositionNftAccount,
+		PositionState:      position.PositionState,
+		CurrentPoint:       currentPoint,
+		Slippage:           100,
+		TokenADecimal:      9,
+		TokenBDecimal:      9,
+		Policy: &dammv2.CompoundPolicy{
+			QuoteMint:   pool.Account.TokenBMint, // Optional, defaults to token B
+			MinFeeValue: big.NewInt(10_000),      // skip when fees are worth less than this amount of the quote mint
+		},
+	}
+
+	quote, err := cpAmm.GetCompoundQuote(dammv2.GetCompoundQuoteParams{
+		PoolState:     params.PoolState,
+		PositionState: params.PositionState,
+		CurrentPoint:  params.CurrentPoint,
+		Slippage:      params.Slippage,
+		TokenADecimal: params.TokenADecimal,
+		TokenBDecimal: params.TokenBDecimal,
+		Policy:        params.Policy,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.GetCompoundQuote() fail", err)
+	}
+	fmt.Println("fee value:", quote.FeeValue, "liquidity delta:", quote.LiquidityDelta)
+	if !quote.ShouldCompound {
+		fmt.Println("fee below threshold, skip compound")
+		return
+	}
+
+	txBuilder, _, err := cpAmm.CompoundPositionFee(ctx, params)
+	if err != nil {
+		t.Fatal("cpAmm.CompoundPositionFee() fail", err)
+	}
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("CompoundPositionFee txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("CompoundPositionFee SendTransaction() fail", err)
+	}
+	fmt.Println("compound fee success Success sig:", sig.String())
+}
@@ -0,0 +1,47 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestDepthLadder(t *testing.T) {
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationType(pool.Account.ActivationType))
+
+	ladder, err := cpAmm.GetDepthLadder(dammv2.GetDepthLadderParams{
+		PoolState:    pool.Account,
+		CurrentPoint: currentPoint,
+		BandsBps:     []uint16{100, 200, 500},
+		// PriceLevels   []decimal.Decimal
+		TokenADecimal: 9,
+		TokenBDecimal: 9,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.GetDepthLadder() fail", err)
+	}
+
+	fmt.Println("price:", ladder.Price)
+	for _, level := range ladder.Asks {
+		fmt.Println("ask", level.Price, "band:", level.BandBps, "clamped:", level.Clamped, "base:", level.BaseAmount, "quote:", level.QuoteAmount, "fee:", level.FeeAmount)
+	}
+	for _, level := range ladder.Bids {
+		fmt.Println("bid", level.Price, "band:", level.BandBps, "clamped:", level.Clamped, "base:", level.BaseAmount, "quote:", level.QuoteAmount, "fee:", level.FeeAmount)
+	}
+}
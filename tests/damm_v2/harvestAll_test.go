@@ -0,0 +1,55 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestHarvestAll(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	txs, err := cpAmm.HarvestAll(ctx, dammv2.HarvestAllParams{
+		User:            owner,
+		MinFeeAmount:    big.NewInt(1000),
+		MinRewardAmount: big.NewInt(1000),
+		// CurrentTime        *big.Int
+		// MaxTransactionSize int
+	})
+	if err != nil {
+		t.Fatal("cpAmm.HarvestAll() fail", err)
+	}
+
+	for _, harvest := range txs {
+		for _, position := range harvest.Positions {
+			fmt.Println("position:", position.Position, "fee a:", position.FeeAmountA, "fee b:", position.FeeAmountB, "rewards:", position.RewardAmounts)
+		}
+		tx, err := harvest.Builder.SetFeePayer(owner).Build()
+		if err != nil {
+			t.Fatal("HarvestAll txBuilder.Build() fail", err)
+		}
+		sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+			switch {
+			case key.Equals(owner):
+				return &ownerWallet.PrivateKey
+			default:
+				return nil
+			}
+		})
+		if err != nil {
+			t.Fatal("HarvestAll SendTransaction() fail", err)
+		}
+		fmt.Println("harvest success Success sig:", sig.String())
+	}
+}
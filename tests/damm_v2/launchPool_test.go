@@ -0,0 +1,83 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+	"github.com/shopspring/decimal"
+)
+
+func TestLaunchPool(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	positionNftWallet := solana.NewWallet()
+
+	params := dammv2.LaunchPoolParams{
+		Payer:        owner,
+		Creator:      owner,
+		PositionNft:  positionNftWallet.PublicKey(),
+		TokenAMint:   baseMint,
+		TokenBMint:   solana.WrappedSol,
+		InitialPrice: decimal.NewFromFloat(0.001), // 1 base token = 0.001 SOL
+		PriceRange:   dammv2.PriceRangeHalf,
+		// MinPrice     decimal.Decimal
+		// MaxPrice     decimal.Decimal
+		TokenAAmount: decimal.NewFromInt(1_000_000),
+		TokenBAmount: decimal.NewFromInt(1),
+		Fee: dammv2.LaunchPoolFee{
+			BaseFeeMode:    dammv2.BaseFeeModeFeeTimeSchedulerExponential,
+			StartingFeeBps: 5000, // 50%
+			EndingFeeBps:   25,   // 0.25%
+			NumberOfPeriod: 60,
+			TotalDuration:  3600,
+			DynamicFee:     true,
+		},
+		CollectFeeMode: dammv2.CollectFeeModeBothToken,
+		ActivationType: dammv2.ActivationTypeTimestamp,
+		ActivationTime: time.Now().Add(time.Minute),
+		// HasAlphaVault   bool
+		// IsLockLiquidity bool
+	}
+
+	preview, err := cpAmm.PreviewLaunchPool(ctx, params)
+	if err != nil {
+		t.Fatal("cpAmm.PreviewLaunchPool() fail", err)
+	}
+	fmt.Println("pool:", preview.Pool, "range:", preview.MinPrice, "-", preview.MaxPrice)
+	fmt.Println("deposit a:", preview.TokenAUiAmount, "deposit b:", preview.TokenBUiAmount, "a value share:", preview.TokenAValueShare)
+
+	txBuilder, _, err := cpAmm.LaunchPool(ctx, params)
+	if err != nil {
+		t.Fatal("cpAmm.LaunchPool() fail", err)
+	}
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("LaunchPool txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		case key.Equals(positionNftWallet.PublicKey()):
+			return &positionNftWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("LaunchPool SendTransaction() fail", err)
+	}
+	fmt.Println("launch pool success Success sig:", sig.String())
+}
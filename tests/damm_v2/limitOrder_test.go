@@ -0,0 +1,73 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/shopspring/decimal"
+)
+
+func TestLimitOrderKeeper(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	keeper := dammv2.NewLimitOrderKeeper(cpAmm, wsClient, dammv2.LimitOrderKeeperConfig{
+		// Store:  dammv2.LimitOrderStore
+		Signer: func(key solana.PublicKey) *solana.PrivateKey {
+			switch {
+			case key.Equals(owner):
+				return &ownerWallet.PrivateKey
+			default:
+				return nil
+			}
+		},
+		OnStatus: func(order dammv2.LimitOrder) {
+			fmt.Println("order:", order.ID, "status:", order.Status, "filled:", order.FilledAmount, "received:", order.ReceivedAmount, "error:", order.LastError)
+			if order.Status == dammv2.LimitOrderStatusFilled {
+				cancel()
+			}
+		},
+		ExpiryInterval: time.Second * 5, // Optional
+	})
+
+	price := helpers.GetPriceFromSqrtPrice(pool.Account.SqrtPrice.BigInt(), 9, 9)
+
+	order, err := keeper.Submit(dammv2.LimitOrder{
+		Owner:            owner,
+		Pool:             pool.PublicKey,
+		Side:             dammv2.LimitOrderSideSell,
+		Amount:           big.NewInt(1_000_000_000),
+		LimitPrice:       price.Mul(decimal.NewFromFloat(1.01)),
+		Expiry:           time.Now().Add(10 * time.Minute),
+		AllowPartialFill: true,
+	})
+	if err != nil {
+		t.Fatal("keeper.Submit() fail", err)
+	}
+	fmt.Println("limit order:", order.ID, "limit price:", order.LimitPrice)
+
+	if err := keeper.Run(ctx); err != nil && ctx.Err() == nil {
+		t.Fatal("keeper.Run() fail", err)
+	}
+}
@@ -0,0 +1,58 @@
+package damm_v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestMathOverflow(t *testing.T) {
+
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	// one past u64::MAX
+	amountIn := new(big.Int).Lsh(big.NewInt(1), 64)
+
+	_, err = cpAmm.Swap2(ctx, dammv2.Swap2Params{
+		Payer:           owner,
+		Pool:            pool.PublicKey,
+		PoolState:       pool.Account,
+		InputTokenMint:  pool.Account.TokenAMint,
+		OutputTokenMint: pool.Account.TokenBMint,
+		// ReferralTokenAccount *solanago.PublicKey
+		// Receiver             *solanago.PublicKey
+		SwapMode:         dammv2.SwapModeExactIn,
+		AmountIn:         amountIn,
+		MinimumAmountOut: big.NewInt(0),
+		// AmountOut            *big.Int
+		// MaximumAmountIn      *big.Int
+	})
+	if !errors.Is(err, dammv2.ErrMathOverflow) {
+		t.Fatal("cpAmm.Swap2() expected ErrMathOverflow", err)
+	}
+
+	var overflow *dammv2.MathOverflowError
+	if errors.As(err, &overflow) {
+		fmt.Println("rejected op:", overflow.Op)
+	}
+}
@@ -0,0 +1,89 @@
+package damm_v2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestPoolDiscovery(t *testing.T) {
+	baseMint := solana.MustPublicKeyFromBase58("")
+	quoteMint := solana.WrappedSol
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByPair(ctx, quoteMint, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByPair() fail", err)
+	}
+	for _, pool := range pools {
+		fmt.Println("pair pool:", pool.PublicKey, "creator:", pool.Account.Creator)
+	}
+
+	pool := pools[0]
+
+	byCreator, err := cpAmm.FetchPoolStatesByCreator(ctx, pool.Account.Creator)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByCreator() fail", err)
+	}
+	fmt.Println("creator pools:", len(byCreator))
+
+	byTokenB, err := cpAmm.FetchPoolStatesByTokenBMint(ctx, pool.Account.TokenBMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenBMint() fail", err)
+	}
+	fmt.Println("token b pools:", len(byTokenB))
+
+	byConfig, err := cpAmm.FetchPoolStatesByConfig(ctx, dammv2.DeriveConfigAddress(0))
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByConfig() fail", err)
+	}
+	fmt.Println("config pools:", len(byConfig))
+
+	disabled, err := cpAmm.FetchPoolStatesByStatus(ctx, dammv2.PoolStatusDisable)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByStatus() fail", err)
+	}
+	fmt.Println("disabled pools:", len(disabled))
+}
+
+func TestPoolIndex(t *testing.T) {
+	baseMint := solana.MustPublicKeyFromBase58("")
+	quoteMint := solana.WrappedSol
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	index, err := cpAmm.BuildPoolIndex(ctx)
+	if err != nil {
+		t.Fatal("cpAmm.BuildPoolIndex() fail", err)
+	}
+	fmt.Println("indexed pools:", index.Len())
+
+	for _, entry := range index.PoolsByPair(baseMint, quoteMint) {
+		fmt.Println("pair pool:", entry.Pool, "status:", entry.PoolStatus)
+	}
+
+	// keep the index current from new transactions of the program
+	// go func() {
+	// 	if err := cpAmm.WatchPoolIndex(ctx, wsClient, index); err != nil {
+	// 		fmt.Println("cpAmm.WatchPoolIndex() stopped", err)
+	// 	}
+	// }()
+
+	var buf bytes.Buffer
+	if err := index.Save(&buf); err != nil {
+		t.Fatal("index.Save() fail", err)
+	}
+	loaded, err := dammv2.LoadPoolIndex(&buf)
+	if err != nil {
+		t.Fatal("dammv2.LoadPoolIndex() fail", err)
+	}
+	fmt.Println("loaded pools:", loaded.Len(), "mint pools:", len(loaded.PoolsByMint(baseMint)))
+}
@@ -0,0 +1,55 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestPoolSimulator(t *testing.T) {
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationType(pool.Account.ActivationType))
+	currentTime := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationTypeTimestamp)
+
+	simulator := dammv2.NewPoolSimulator(pool.Account)
+
+	for _, aToB := range []bool{false, false, true} {
+		step, err := simulator.Swap(dammv2.SimulateSwapParams{
+			Amount:       big.NewInt(1_000_000),
+			SwapMode:     dammv2.SwapModeExactIn,
+			AToB:         aToB,
+			CurrentPoint: currentPoint,
+			CurrentTime:  currentTime,
+			// HasReferral  bool
+		})
+		if err != nil {
+			t.Fatal("simulator.Swap() fail", err)
+		}
+		fmt.Println("aToB:", aToB, "output:", step.Result.OutputAmount, "fee:", step.Result.TradingFee, "next sqrt price:", step.PoolState.SqrtPrice.BigInt(), "volatility:", step.PoolState.PoolFees.DynamicFee.VolatilityAccumulator.BigInt())
+	}
+
+	change, err := simulator.RemoveLiquidity(dammv2.SimulateLiquidityParams{
+		LiquidityDelta: new(big.Int).Div(pool.Account.Liquidity.BigInt(), big.NewInt(10)),
+		CurrentTime:    currentTime,
+	})
+	if err != nil {
+		t.Fatal("simulator.RemoveLiquidity() fail", err)
+	}
+	fmt.Println("remove amount a:", change.AmountA, "amount b:", change.AmountB, "liquidity:", change.PoolState.Liquidity.BigInt())
+}
@@ -0,0 +1,69 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestPositionReport(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	positions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+	if len(positions) == 0 {
+		t.Fatal("no position found")
+	}
+	position := positions[0]
+
+	changes, err := cpAmm.GetPositionLiquidityChanges(ctx, position.Position, 0)
+	if err != nil {
+		t.Fatal("cpAmm.GetPositionLiquidityChanges() fail", err)
+	}
+	entry := dammv2.PositionEntryFromLiquidityChanges(changes)
+
+	currentTime := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationTypeTimestamp)
+
+	report, err := cpAmm.GetPositionReport(dammv2.GetPositionReportParams{
+		PoolState:     pool.Account,
+		PositionState: position.PositionState,
+		QuoteMint:     pool.Account.TokenBMint,
+		CurrentTime:   currentTime,
+		TokenADecimal: 9,
+		TokenBDecimal: 9,
+		Entry:         &entry,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.GetPositionReport() fail", err)
+	}
+
+	fmt.Println("amount a:", report.AmountA, "amount b:", report.AmountB)
+	fmt.Println("unlocked:", report.UnlockedLiquidity, "vesting:", report.VestingLiquidity, "permanent locked:", report.PermanentLockedLiquidity)
+	fmt.Println("unclaimed fee a:", report.UnclaimedFeeA, "unclaimed fee b:", report.UnclaimedFeeB)
+	fmt.Println("realized fee a:", report.RealizedFeeA, "realized fee b:", report.RealizedFeeB)
+	for _, reward := range report.Rewards {
+		fmt.Println("reward", reward.Index, "mint:", reward.Mint, "pending:", reward.PendingAmount, "claimed:", reward.ClaimedAmount)
+	}
+	fmt.Println("total value:", report.TotalValue, "hold value:", report.HoldValue)
+	fmt.Println("impermanent loss:", report.ImpermanentLoss, report.ImpermanentLossPct.String(), "pnl:", report.PnL)
+}
@@ -0,0 +1,77 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+	"github.com/shopspring/decimal"
+)
+
+func TestRewardAnalytics(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	rewardMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	currentTime := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationTypeTimestamp)
+
+	analytics, err := cpAmm.GetRewardAnalytics(dammv2.GetRewardAnalyticsParams{
+		PoolState:           pool.Account,
+		CurrentTime:         currentTime,
+		TokenAPrice:         decimal.NewFromFloat(0.01),
+		TokenBPrice:         decimal.NewFromInt(150),
+		TokenADecimal:       9,
+		TokenBDecimal:       9,
+		RewardPrices:        map[solana.PublicKey]decimal.Decimal{rewardMint: decimal.NewFromInt(1)},
+		RewardDecimals:      map[solana.PublicKey]uint8{rewardMint: 6},
+		EndingSoonThreshold: big.NewInt(24 * 60 * 60),
+		// Tvl *decimal.Decimal
+	})
+	if err != nil {
+		t.Fatal("cpAmm.GetRewardAnalytics() fail", err)
+	}
+
+	fmt.Println("tvl:", analytics.Tvl)
+	for _, reward := range analytics.Rewards {
+		fmt.Println("reward", reward.Index, "mint:", reward.Mint, "funder:", reward.Funder, "per second:", reward.RewardPerSecond, "remaining:", reward.RemainingBalance, "ineligible:", reward.IneligibleReward, "apr:", reward.Apr, "ending soon:", reward.EndingSoon)
+	}
+
+	positions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+	if len(positions) == 0 {
+		return
+	}
+
+	schedule, err := cpAmm.GetPositionRewardSchedule(dammv2.GetPositionRewardScheduleParams{
+		PoolState:       pool.Account,
+		PositionState:   positions[0].PositionState,
+		CurrentTime:     currentTime,
+		Period:          big.NewInt(24 * 60 * 60),
+		NumberOfPeriods: 7,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.GetPositionRewardSchedule() fail", err)
+	}
+	for _, point := range schedule {
+		fmt.Println("time:", point.Time, "pending:", point.PendingRewards)
+	}
+}
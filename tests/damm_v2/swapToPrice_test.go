@@ -0,0 +1,83 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+	"github.com/krazyTry/meteora-go/damm_v2/helpers"
+	"github.com/shopspring/decimal"
+)
+
+func TestSwapToPrice(t *testing.T) {
+
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationType(pool.Account.ActivationType))
+	tokenATokenInfo, err := helpers.GetTokenInfo(ctx, rpcClient, pool.Account.TokenAMint)
+	if err != nil {
+		t.Fatal("dammv2.GetTokenInfo() fail", err)
+	}
+
+	tokenBTokenInfo, err := helpers.GetTokenInfo(ctx, rpcClient, pool.Account.TokenBMint)
+	if err != nil {
+		t.Fatal("dammv2.GetTokenInfo() fail", err)
+	}
+
+	price := helpers.GetPriceFromSqrtPrice(pool.Account.SqrtPrice.BigInt(), 9, 9)
+	targetPrice := price.Mul(decimal.NewFromFloat(1.05))
+	fmt.Println("price:", price, "target price:", targetPrice)
+
+	txBuilder, quote, err := cpAmm.SwapToPrice(ctx, dammv2.SwapToPriceParams{
+		Payer:           owner,
+		Pool:            pool.PublicKey,
+		PoolState:       pool.Account,
+		CurrentPoint:    currentPoint,
+		TargetPrice:     targetPrice,
+		Slippage:        100,
+		TokenATokenInfo: tokenATokenInfo, // Optional
+		TokenBTokenInfo: tokenBTokenInfo, // Optional
+		TokenADecimal:   9,
+		TokenBDecimal:   9,
+		// ReferralTokenAccount *solanago.PublicKey
+		// Receiver             *solanago.PublicKey
+	})
+	if err != nil {
+		t.Fatal("cpAmm.SwapToPrice() fail", err)
+	}
+	fmt.Println("aToB:", quote.AToB, "clamped:", quote.Clamped, "amountIn:", quote.AmountIn, "amountOut:", quote.AmountOut, "minimumAmountOut:", quote.MinimumAmountOut)
+
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("SwapToPrice txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("SwapToPrice SendTransaction() fail", err)
+	}
+	fmt.Println("swap to price success Success sig:", sig.String())
+}
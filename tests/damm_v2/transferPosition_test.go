@@ -0,0 +1,73 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestTransferPosition(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	recipient := solana.MustPublicKeyFromBase58("")
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	userPositions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+
+	position := userPositions[0]
+
+	txBuilder, err := cpAmm.TransferPosition(ctx, dammv2.TransferPositionParams{
+		Owner:              owner,
+		Payer:              owner,
+		Recipient:          recipient,
+		PositionNftMint:    position.PositionState.NftMint,
+		PositionNftAccount: position.PositionNftAccount,
+		CloseSourceAccount: true,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.TransferPosition() fail", err)
+	}
+
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("TransferPosition txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("TransferPosition SendTransaction() fail", err)
+	}
+	fmt.Println("transfer position success Success sig:", sig.String())
+
+	positionOwner, err := cpAmm.GetPositionOwner(ctx, position.Position)
+	if err != nil {
+		t.Fatal("cpAmm.GetPositionOwner() fail", err)
+	}
+	fmt.Println("position owner:", positionOwner.Owner, "nft account:", positionOwner.PositionNftAccount)
+}
@@ -0,0 +1,104 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestVestingDesigner(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	positions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+	if len(positions) == 0 {
+		t.Fatal("no position found")
+	}
+	position := positions[0]
+
+	activationType := dammv2.ActivationType(pool.Account.ActivationType)
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, activationType)
+	now := time.Now()
+
+	design, err := cpAmm.DesignVesting(dammv2.DesignVestingParams{
+		UnlockedLiquidity: position.PositionState.UnlockedLiquidity.BigInt(),
+		PercentageBps:     5000,
+		ActivationType:    activationType,
+		CurrentPoint:      currentPoint,
+		CurrentTime:       now,
+		CliffTime:         now.Add(24 * time.Hour),
+		CliffUnlockBps:    1000,
+		VestDuration:      30 * 24 * time.Hour,
+		Cadence:           24 * time.Hour,
+		// Liquidity *big.Int
+	})
+	if err != nil {
+		t.Fatal("cpAmm.DesignVesting() fail", err)
+	}
+	for _, unlock := range design.Timeline {
+		fmt.Println("point:", unlock.Point, "unlock:", unlock.Liquidity, "cumulative:", unlock.CumulativeUnlocked)
+	}
+
+	vestingWallet := solana.NewWallet()
+	vestingAccount := vestingWallet.PublicKey()
+
+	txBuilder, err := cpAmm.LockPosition(ctx, design.ToLockPositionParams(dammv2.LockPositionParams{
+		Owner:              owner,
+		Payer:              owner,
+		Position:           position.Position,
+		PositionNftAccount: position.PositionNftAccount,
+		Pool:               pool.PublicKey,
+		VestingAccount:     &vestingAccount,
+	}))
+	if err != nil {
+		t.Fatal("cpAmm.LockPosition() fail", err)
+	}
+
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("LockPosition txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		case key.Equals(vestingAccount):
+			return &vestingWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("LockPosition SendTransaction() fail", err)
+	}
+	fmt.Println("lock position success Success sig:", sig.String())
+
+	timeline, err := cpAmm.GetPositionVestingTimeline(ctx, position.Position, position.PositionState)
+	if err != nil {
+		t.Fatal("cpAmm.GetPositionVestingTimeline() fail", err)
+	}
+	for _, unlock := range timeline {
+		fmt.Println("combined point:", unlock.Point, "unlock:", unlock.Liquidity, "cumulative:", unlock.CumulativeUnlocked)
+	}
+}
@@ -0,0 +1,82 @@
+package damm_v2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	dammv2 "github.com/krazyTry/meteora-go/damm_v2"
+)
+
+func TestZapOut(t *testing.T) {
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+	ctx := context.Background()
+
+	cpAmm := dammv2.NewCpAmm(rpcClient, rpc.CommitmentFinalized)
+
+	pools, err := cpAmm.FetchPoolStatesByTokenAMint(ctx, baseMint)
+	if err != nil {
+		t.Fatal("cpAmm.FetchPoolStatesByTokenAMint() fail", err)
+	}
+
+	pool := pools[0]
+
+	positions, err := cpAmm.GetUserPositionByPool(ctx, pool.PublicKey, owner)
+	if err != nil {
+		t.Fatal("cpAmm.GetUserPositionByPool() fail", err)
+	}
+	if len(positions) == 0 {
+		t.Fatal("no position found")
+	}
+	position := positions[0]
+
+	vestings, err := cpAmm.GetAllVestingsByPosition(ctx, position.Position)
+	if err != nil {
+		t.Fatal("cpAmm.GetAllVestingsByPosition() fail", err)
+	}
+
+	currentPoint := dammv2.CurrentPointForActivation(ctx, rpcClient, rpc.CommitmentFinalized, dammv2.ActivationType(pool.Account.ActivationType))
+
+	txBuilder, quote, err := cpAmm.ZapOut(ctx, dammv2.ZapOutParams{
+		Owner:              owner,
+		Pool:               pool.PublicKey,
+		PoolState:          pool.Account,
+		Position:           position.Position,
+		PositionNftAccount: position.PositionNftAccount,
+		PositionState:      position.PositionState,
+		OutputTokenMint:    pool.Account.TokenBMint,
+		// LiquidityDelta     *big.Int // nil removes all withdrawable liquidity
+		Slippage:      100,
+		CurrentPoint:  currentPoint,
+		Vestings:      vestings,
+		TokenADecimal: 9,
+		TokenBDecimal: 9,
+	})
+	if err != nil {
+		t.Fatal("cpAmm.ZapOut() fail", err)
+	}
+	fmt.Println("zap out quote total out:", quote.TotalOutAmount, "fee:", quote.TotalFee, "price impact:", quote.PriceImpact)
+
+	tx, err := txBuilder.SetFeePayer(owner).Build()
+	if err != nil {
+		t.Fatal("ZapOut txBuilder.Build() fail", err)
+	}
+	sig, err := SendTransaction(ctx, rpcClient, wsClient, tx, func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(owner):
+			return &ownerWallet.PrivateKey
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatal("ZapOut SendTransaction() fail", err)
+	}
+	fmt.Println("zap out success Success sig:", sig.String())
+}
@@ -0,0 +1,68 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+)
+
+func TestClaimAllFees(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	partner := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	fmt.Println("partner address:", partner.PublicKey())
+
+	poolCreator := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	fmt.Println("pool creator address:", poolCreator.PublicKey())
+
+	ctx1 := context.Background()
+
+	sign := func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(partner.PublicKey()):
+			return &partner.PrivateKey
+		case key.Equals(poolCreator.PublicKey()):
+			return &poolCreator.PrivateKey
+		default:
+			return nil
+		}
+	}
+
+	partnerResult, err := dbcService.ClaimAllPartnerFees(ctx1, dynamic_bonding_curve.ClaimAllFeesParams{
+		Owner:       partner.PublicKey(),
+		Payer:       partner.PublicKey(),
+		MinQuoteFee: 1_000_000,
+		// MinBaseFee:           0,
+		// Receiver:             solana.PublicKey{},
+		WithdrawSurplus:      true,
+		WithdrawMigrationFee: true,
+	})
+	if err != nil {
+		t.Fatal("ClaimAllPartnerFees() fail", err)
+	}
+	fmt.Println("partner skipped pools:", partnerResult.Skipped)
+
+	creatorResult, err := dbcService.ClaimAllCreatorFees(ctx1, dynamic_bonding_curve.ClaimAllFeesParams{
+		Owner:            poolCreator.PublicKey(),
+		Payer:            poolCreator.PublicKey(),
+		MinQuoteFee:      1_000_000,
+		ComputeUnitLimit: 400_000,
+	})
+	if err != nil {
+		t.Fatal("ClaimAllCreatorFees() fail", err)
+	}
+	fmt.Println("creator skipped pools:", creatorResult.Skipped)
+
+	for _, batch := range append(partnerResult.Batches, creatorResult.Batches...) {
+		sig, err := SendTransaction(ctx1, rpcClient, wsClient, batch.Transaction, sign)
+		if err != nil {
+			t.Fatal("claim all SendTransaction() fail", err)
+		}
+		fmt.Println("claim pools:", batch.Pools, "success Success sig:", sig.String())
+	}
+}
@@ -0,0 +1,214 @@
+package dynamic_bonding_curve
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/shopspring/decimal"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+func TestConfigFile(t *testing.T) {
+	migratedPoolBaseFeeMode := shared.DammV2BaseFeeModeFeeTimeSchedulerLinear
+
+	buildCurveBaseParams := shared.BuildCurveBaseParams{
+		TotalTokenSupply:  1000000000,
+		MigrationOption:   shared.MigrationOptionMetDammV2,
+		TokenBaseDecimal:  shared.TokenDecimalSix,
+		TokenQuoteDecimal: shared.TokenDecimalNine,
+		LockedVestingParams: shared.LockedVestingParams{
+			TotalLockedVestingAmount:       0,
+			NumberOfVestingPeriod:          0,
+			CliffUnlockAmount:              0,
+			TotalVestingDuration:           0,
+			CliffDurationFromMigrationTime: 0,
+		},
+		BaseFeeParams: shared.BaseFeeParams{
+			BaseFeeMode: shared.BaseFeeModeFeeSchedulerLinear,
+			FeeSchedulerParam: &shared.FeeSchedulerParams{
+				StartingFeeBps: 100,
+				EndingFeeBps:   100,
+				NumberOfPeriod: 0,
+				TotalDuration:  0,
+			},
+		},
+		DynamicFeeEnabled:          true,
+		ActivationType:             shared.ActivationTypeSlot,
+		CollectFeeMode:             shared.CollectFeeModeQuoteToken,
+		MigrationFeeOption:         shared.MigrationFeeOptionFixedBps100,
+		TokenType:                  shared.TokenTypeSPL,
+		PartnerLiquidityPercentage: 0,
+		CreatorLiquidityPercentage: 0,
+		PartnerPermanentLockedLiquidityPercentage: 100,
+		CreatorPermanentLockedLiquidityPercentage: 0,
+		CreatorTradingFeePercentage:               0,
+		Leftover:                                  0,
+		TokenUpdateAuthority:                      0,
+		MigrationFee: struct {
+			FeePercentage        uint8
+			CreatorFeePercentage uint8
+		}{
+			FeePercentage:        0,
+			CreatorFeePercentage: 0,
+		},
+		PoolCreationFee:           1,
+		MigratedPoolBaseFeeMode:   &migratedPoolBaseFeeMode,
+		EnableFirstSwapWithMinFee: false,
+	}
+	params := shared.BuildCurveParams{
+		BuildCurveBaseParams:        buildCurveBaseParams,
+		PercentageSupplyOnMigration: 2.983257229832572,
+		MigrationQuoteThreshold:     95.07640791476408,
+	}
+
+	cfg, err := helpers.BuildCurve(params)
+	if err != nil {
+		t.Fatal("BuildCurve() fail", err)
+	}
+
+	intended := shared.CreateConfigParams{
+		ConfigParameters: cfg,
+		FeeClaimer:       solana.MustPublicKeyFromBase58("9DqoJJgRUL8kFj5e7Xbd3Tta7Wsd1GzVXeHvUBBtUbk4"),
+		LeftoverReceiver: solana.MustPublicKeyFromBase58("9DqoJJgRUL8kFj5e7Xbd3Tta7Wsd1GzVXeHvUBBtUbk4"),
+		QuoteMint:        solana.WrappedSol,
+	}
+
+	file, err := helpers.ConfigFileFromConfigParams(intended, shared.TokenDecimalNine)
+	if err != nil {
+		t.Fatal("ConfigFileFromConfigParams() fail", err)
+	}
+
+	data, err := helpers.MarshalConfigFile(file)
+	if err != nil {
+		t.Fatal("MarshalConfigFile() fail", err)
+	}
+	fmt.Println(string(data))
+
+	loaded, err := helpers.LoadConfigFile(data)
+	if err != nil {
+		t.Fatal("LoadConfigFile() fail", err)
+	}
+
+	got, _ := json.Marshal(loaded)
+	want, _ := json.Marshal(intended)
+	if string(got) != string(want) {
+		t.Fatalf("config params changed on round trip:\n%s\n%s", got, want)
+	}
+
+	file.MigrationMarketCap = file.MigrationMarketCap.Mul(decimal.NewFromInt(2))
+	data, err = helpers.MarshalConfigFile(file)
+	if err != nil {
+		t.Fatal("MarshalConfigFile() fail", err)
+	}
+	if _, err := helpers.LoadConfigFile(data); err == nil {
+		t.Fatal("LoadConfigFile() accepted a migration market cap that does not match the curve")
+	}
+}
+
+func TestConfigFileHumanUnits(t *testing.T) {
+	migratedPoolBaseFeeMode := shared.DammV2BaseFeeModeFeeTimeSchedulerLinear
+
+	params := shared.BuildCurveParams{
+		BuildCurveBaseParams: shared.BuildCurveBaseParams{
+			TotalTokenSupply:  1000000000,
+			MigrationOption:   shared.MigrationOptionMetDammV2,
+			TokenBaseDecimal:  shared.TokenDecimalSix,
+			TokenQuoteDecimal: shared.TokenDecimalNine,
+			LockedVestingParams: shared.LockedVestingParams{
+				TotalLockedVestingAmount: 10000000,
+				NumberOfVestingPeriod:    4,
+				CliffUnlockAmount:        1000000,
+				TotalVestingDuration:     4 * 86400,
+			},
+			BaseFeeParams: shared.BaseFeeParams{
+				BaseFeeMode: shared.BaseFeeModeFeeSchedulerLinear,
+				FeeSchedulerParam: &shared.FeeSchedulerParams{
+					StartingFeeBps: 5000,
+					EndingFeeBps:   100,
+					NumberOfPeriod: 10,
+					TotalDuration:  100,
+				},
+			},
+			ActivationType:     shared.ActivationTypeSlot,
+			CollectFeeMode:     shared.CollectFeeModeQuoteToken,
+			MigrationFeeOption: shared.MigrationFeeOptionFixedBps100,
+			TokenType:          shared.TokenTypeSPL,
+			PartnerPermanentLockedLiquidityPercentage: 100,
+			PoolCreationFee:         1,
+			MigratedPoolBaseFeeMode: &migratedPoolBaseFeeMode,
+		},
+		PercentageSupplyOnMigration: 2.983257229832572,
+		MigrationQuoteThreshold:     95.07640791476408,
+	}
+
+	cfg, err := helpers.BuildCurve(params)
+	if err != nil {
+		t.Fatal("BuildCurve() fail", err)
+	}
+	leftoverReceiver := solana.MustPublicKeyFromBase58("9DqoJJgRUL8kFj5e7Xbd3Tta7Wsd1GzVXeHvUBBtUbk4")
+	file, err := helpers.ConfigFileFromConfigParams(shared.CreateConfigParams{ConfigParameters: cfg, LeftoverReceiver: leftoverReceiver, QuoteMint: solana.WrappedSol}, shared.TokenDecimalNine)
+	if err != nil {
+		t.Fatal("ConfigFileFromConfigParams() fail", err)
+	}
+	if file.TokenType != "spl" || file.ActivationType != "slot" || file.BaseFee.BaseFeeMode != "feeSchedulerLinear" || file.MigrationOption != "metDammV2" {
+		t.Fatalf("enums not named: %s %s %s %s", file.TokenType, file.ActivationType, file.BaseFee.BaseFeeMode, file.MigrationOption)
+	}
+	if want := decimal.NewFromInt(int64(cfg.PoolFees.BaseFee.SecondFactor) * shared.SlotDurationMillis).Shift(-3); !file.BaseFee.PeriodFrequency.Equal(want) {
+		t.Fatalf("period frequency %s seconds, want %s", file.BaseFee.PeriodFrequency, want)
+	}
+
+	// Drop every exact field so the load derives them from prices, market caps, base amounts and the
+	// vesting percentage.
+	file.LockedVesting.AmountPerPeriod = decimal.Zero
+	file.LockedVesting.CliffUnlockAmount = decimal.Zero
+	file.SqrtStartPrice = decimal.Zero
+	file.InitialPrice = decimal.Zero
+	file.MigrationQuoteThreshold = decimal.Zero
+	file.MigrationPrice = decimal.Zero
+	for i := range file.Curve {
+		file.Curve[i].SqrtPrice = decimal.Zero
+		file.Curve[i].Liquidity = decimal.Zero
+		file.Curve[i].Price = decimal.Zero
+	}
+	data, err := helpers.MarshalConfigFile(file)
+	if err != nil {
+		t.Fatal("MarshalConfigFile() fail", err)
+	}
+	loaded, err := helpers.LoadConfigFile(data)
+	if err != nil {
+		t.Fatal("LoadConfigFile() fail", err)
+	}
+
+	if len(loaded.Curve) != len(cfg.Curve) {
+		t.Fatalf("curve has %d points, want %d", len(loaded.Curve), len(cfg.Curve))
+	}
+	tolerance := decimal.New(1, -5)
+	got := decimal.NewFromInt(int64(loaded.MigrationQuoteThreshold))
+	want := decimal.NewFromInt(int64(cfg.MigrationQuoteThreshold))
+	if got.Sub(want).Abs().GreaterThan(want.Mul(tolerance)) {
+		t.Fatalf("migration quote threshold %s, want %s", got, want)
+	}
+	vested := func(v shared.LockedVestingParameters) uint64 {
+		return v.CliffUnlockAmount + v.AmountPerPeriod*uint64(v.NumberOfPeriod)
+	}
+	if got, want := vested(loaded.LockedVesting), vested(cfg.LockedVesting); got > want || want-got > 1 {
+		t.Fatalf("locked vesting total %d, want %d", got, want)
+	}
+	if loaded.PoolFees.BaseFee != cfg.PoolFees.BaseFee {
+		t.Fatalf("base fee %+v, want %+v", loaded.PoolFees.BaseFee, cfg.PoolFees.BaseFee)
+	}
+
+	halfSecond := decimal.RequireFromString("0.5")
+	file.BaseFee.PeriodFrequency = &halfSecond
+	data, err = helpers.MarshalConfigFile(file)
+	if err != nil {
+		t.Fatal("MarshalConfigFile() fail", err)
+	}
+	if _, err := helpers.LoadConfigFile(data); err == nil {
+		t.Fatal("LoadConfigFile() accepted a period frequency that is not a whole number of slots")
+	}
+}
@@ -0,0 +1,82 @@
+package dynamic_bonding_curve
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+func TestDesignCurve(t *testing.T) {
+	migratedPoolBaseFeeMode := shared.DammV2BaseFeeModeFeeTimeSchedulerLinear
+
+	buildCurveBaseParams := shared.BuildCurveBaseParams{
+		TotalTokenSupply:  1000000000,
+		MigrationOption:   shared.MigrationOptionMetDammV2,
+		TokenBaseDecimal:  shared.TokenDecimalSix,
+		TokenQuoteDecimal: shared.TokenDecimalNine,
+		LockedVestingParams: shared.LockedVestingParams{
+			TotalLockedVestingAmount:       0,
+			NumberOfVestingPeriod:          0,
+			CliffUnlockAmount:              0,
+			TotalVestingDuration:           0,
+			CliffDurationFromMigrationTime: 0,
+		},
+		BaseFeeParams: shared.BaseFeeParams{
+			BaseFeeMode: shared.BaseFeeModeFeeSchedulerLinear,
+			FeeSchedulerParam: &shared.FeeSchedulerParams{
+				StartingFeeBps: 100,
+				EndingFeeBps:   100,
+				NumberOfPeriod: 0,
+				TotalDuration:  0,
+			},
+		},
+		DynamicFeeEnabled:          true,
+		ActivationType:             shared.ActivationTypeSlot,
+		CollectFeeMode:             shared.CollectFeeModeQuoteToken,
+		MigrationFeeOption:         shared.MigrationFeeOptionFixedBps100,
+		TokenType:                  shared.TokenTypeSPL,
+		PartnerLiquidityPercentage: 0,
+		CreatorLiquidityPercentage: 0,
+		PartnerPermanentLockedLiquidityPercentage: 100,
+		CreatorPermanentLockedLiquidityPercentage: 0,
+		CreatorTradingFeePercentage:               0,
+		Leftover:                                  0,
+		TokenUpdateAuthority:                      0,
+		MigrationFee: struct {
+			FeePercentage        uint8
+			CreatorFeePercentage uint8
+		}{
+			FeePercentage:        0,
+			CreatorFeePercentage: 0,
+		},
+		PoolCreationFee:           1,
+		MigratedPoolBaseFeeMode:   &migratedPoolBaseFeeMode,
+		EnableFirstSwapWithMinFee: false,
+	}
+	buildCurveBaseParams.Leftover = 10000
+
+	goals := shared.CurveDesignGoals{
+		BuildCurveBaseParams:  buildCurveBaseParams,
+		InitialMarketCap:      20,
+		MigrationMarketCap:    600,
+		PercentageSoldOnCurve: 80,
+		VestingPercentage:     5,
+		ImpactBuyAmount:       1,
+		MaxPriceImpact:        10,
+		MaxCandidates:         5,
+		LeftoverReceiver:      solana.MustPublicKeyFromBase58("9DqoJJgRUL8kFj5e7Xbd3Tta7Wsd1GzVXeHvUBBtUbk4"),
+	}
+
+	candidates, err := helpers.DesignCurve(goals)
+	if err != nil {
+		t.Fatal("DesignCurve() fail", err)
+	}
+
+	for _, candidate := range candidates {
+		fmt.Println("builder:", candidate.Builder, "score:", candidate.Score, "initial market cap:", candidate.InitialMarketCap, "migration market cap:", candidate.MigrationMarketCap, "raise:", candidate.Raise, "sold on curve:", candidate.PercentageSoldOnCurve, "vesting:", candidate.VestingPercentage, "price impact:", candidate.PriceImpact, "meets price impact:", candidate.MeetsPriceImpact, "validation:", candidate.ValidationError)
+	}
+}
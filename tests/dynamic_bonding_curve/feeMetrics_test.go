@@ -0,0 +1,90 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+func TestFeeMetrics(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+
+	ctx1 := context.Background()
+
+	poolState, err := dbcService.GetPoolByBaseMint(ctx1, baseMint)
+	if err != nil {
+		t.Fatal("GetPoolByBaseMint() fail", err)
+	}
+
+	poolFees, err := dbcService.GetPoolFeeMetrics(ctx1, poolState.Pubkey, true)
+	if err != nil {
+		t.Fatal("GetPoolFeeMetrics() fail", err)
+	}
+	fmt.Println("partner unclaimed:", poolFees.Partner.Unclaimed.Quote, "claimed:", poolFees.Partner.Claimed.Quote, "value:", poolFees.Partner.Unclaimed.Value)
+	fmt.Println("creator unclaimed:", poolFees.Creator.Unclaimed.Quote, "claimed:", poolFees.Creator.Claimed.Quote, "value:", poolFees.Creator.Unclaimed.Value)
+	fmt.Println("protocol unclaimed:", poolFees.Protocol.Unclaimed.Quote, "claimed:", poolFees.Protocol.Claimed.Quote, "value:", poolFees.Protocol.Unclaimed.Value)
+
+	configFees, err := dbcService.GetFeesByConfig(ctx1, poolState.Account.Config, true)
+	if err != nil {
+		t.Fatal("GetFeesByConfig() fail", err)
+	}
+	creatorFees, err := dbcService.GetFeesByCreator(ctx1, poolState.Account.Creator, false)
+	if err != nil {
+		t.Fatal("GetFeesByCreator() fail", err)
+	}
+	for _, totals := range append(configFees.Totals, creatorFees.Totals...) {
+		fmt.Println("quote mint:", totals.QuoteMint, "pools:", totals.Pools, "partner unclaimed:", totals.Partner.Unclaimed.Quote, "creator unclaimed:", totals.Creator.Unclaimed.Quote)
+	}
+}
+
+func TestFeeMetricsTotals(t *testing.T) {
+	quoteMint := solana.NewWallet().PublicKey()
+	config := &shared.PoolConfig{QuoteMint: quoteMint, CreatorTradingFeePercentage: 20}
+
+	pools := []*shared.VirtualPool{
+		{PartnerBaseFee: 300, CreatorBaseFee: 100, PartnerQuoteFee: 0, ProtocolBaseFee: 50},
+		{PartnerBaseFee: 700, CreatorBaseFee: 200, PartnerQuoteFee: 40, CreatorQuoteFee: 10},
+	}
+	pools[0].Metrics.TotalTradingBaseFee = 1_000
+	pools[0].Metrics.TotalProtocolBaseFee = 80
+	pools[1].Metrics.TotalTradingBaseFee = 2_000
+	pools[1].Metrics.TotalTradingQuoteFee = 100
+
+	var metrics shared.FeeMetrics
+	for _, pool := range pools {
+		helpers.AddPoolFeeMetrics(&metrics, helpers.PoolFeeMetricsFromState(solana.NewWallet().PublicKey(), pool, config, false))
+	}
+	if len(metrics.Totals) != 1 || metrics.Totals[0].Pools != 2 {
+		t.Fatalf("totals = %+v", metrics.Totals)
+	}
+	totals := metrics.Totals[0]
+	for _, c := range []struct {
+		name string
+		got  *big.Int
+		want int64
+	}{
+		{"partner unclaimed base", totals.Partner.Unclaimed.Base, 1_000},
+		{"creator unclaimed base", totals.Creator.Unclaimed.Base, 300},
+		{"protocol unclaimed base", totals.Protocol.Unclaimed.Base, 50},
+		// lifetime partner base 800 + 1600, creator 200 + 400.
+		{"partner claimed base", totals.Partner.Claimed.Base, 1_400},
+		{"creator claimed base", totals.Creator.Claimed.Base, 300},
+		{"protocol claimed base", totals.Protocol.Claimed.Base, 30},
+		{"partner claimed quote", totals.Partner.Claimed.Quote, 40},
+		{"creator claimed quote", totals.Creator.Claimed.Quote, 10},
+	} {
+		if c.got == nil || c.got.Cmp(big.NewInt(c.want)) != 0 {
+			t.Fatalf("%s = %v, want %d", c.name, c.got, c.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+)
+
+func TestMigrationKeeper(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	configAddress := solana.MustPublicKeyFromBase58("")
+
+	ownerWallet := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	owner := ownerWallet.PublicKey()
+	fmt.Println("owner address:", owner)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+
+	ctx1 := context.Background()
+
+	poolState, err := dbcService.GetPoolByBaseMint(ctx1, baseMint)
+	if err != nil {
+		t.Fatal("GetPoolByBaseMint() fail", err)
+	}
+
+	keeper := dynamic_bonding_curve.NewMigrationKeeper(dbcService, wsClient, dynamic_bonding_curve.MigrationKeeperConfig{
+		// Store: dynamic_bonding_curve.NewMemoryMigrationStore(),
+		Payer: owner,
+		Signer: func(key solana.PublicKey) *solana.PrivateKey {
+			switch {
+			case key.Equals(owner):
+				return &ownerWallet.PrivateKey
+			default:
+				return nil
+			}
+		},
+		Configs:     []solana.PublicKey{configAddress}, // Optional
+		MaxAttempts: 3,
+		RetryDelay:  time.Second * 5,
+		OnStep: func(job dynamic_bonding_curve.MigrationJob, step dynamic_bonding_curve.MigrationStep) {
+			fmt.Println("migration step", step, "success Success sig:", job.Signatures[len(job.Signatures)-1].String())
+		},
+	})
+
+	job, err := keeper.Add(poolState.Pubkey, solana.PublicKey{})
+	if err != nil {
+		t.Fatal("keeper.Add() fail", err)
+	}
+	fmt.Println("tracking pool:", job.Pool)
+
+	// run the remaining steps once without subscriptions.
+	job, err = keeper.Process(ctx1, poolState.Pubkey)
+	if err != nil {
+		t.Fatal("keeper.Process() fail", err)
+	}
+	fmt.Println("completed steps:", job.Completed, "done:", job.Done)
+
+	ctx2, cancel := context.WithTimeout(ctx1, time.Minute*2)
+	defer cancel()
+	if err := keeper.Run(ctx2); err != nil && err != context.DeadlineExceeded {
+		t.Fatal("keeper.Run() fail", err)
+	}
+}
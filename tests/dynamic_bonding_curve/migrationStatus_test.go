@@ -0,0 +1,41 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+)
+
+func TestMigrationStatus(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+
+	ctx1 := context.Background()
+
+	poolState, err := dbcService.GetPoolByBaseMint(ctx1, baseMint)
+	if err != nil {
+		t.Fatal("GetPoolByBaseMint() fail", err)
+	}
+
+	status, err := dbcService.GetMigrationStatus(ctx1, poolState.Pubkey)
+	if err != nil {
+		t.Fatal("GetMigrationStatus() fail", err)
+	}
+
+	fmt.Println("phase:", status.Phase, "progress:", status.MigrationProgress, "option:", status.MigrationOption)
+	fmt.Println("quote reserve:", status.QuoteReserve, "threshold:", status.MigrationQuoteThreshold)
+	fmt.Println("damm config:", status.DammConfig, "damm pool:", status.DammPool)
+	fmt.Println("lock escrow:", status.LockEscrow, "migration metadata:", status.MigrationMetadata)
+	for _, step := range status.Remaining {
+		fmt.Println("remaining step:", step.Step, "ready:", step.Ready, "signer:", step.Signer, "recipient:", step.Recipient)
+	}
+	for _, position := range status.Positions {
+		fmt.Println("position:", position.Position, "nft mint:", position.NftMint)
+	}
+}
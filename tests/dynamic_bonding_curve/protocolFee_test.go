@@ -0,0 +1,106 @@
+package dynamic_bonding_curve
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+)
+
+func TestProtocolFee(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	// program admin of the (localnet) deployment.
+	admin := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	fmt.Println("admin address:", admin.PublicKey())
+
+	operator := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	fmt.Println("operator address:", operator.PublicKey())
+
+	configAddress := solana.MustPublicKeyFromBase58("")
+
+	ctx1 := context.Background()
+
+	sign := func(key solana.PublicKey) *solana.PrivateKey {
+		switch {
+		case key.Equals(admin.PublicKey()):
+			return &admin.PrivateKey
+		case key.Equals(operator.PublicKey()):
+			return &operator.PrivateKey
+		default:
+			return nil
+		}
+	}
+
+	feeOperator, err := dbcService.GetClaimFeeOperator(ctx1, operator.PublicKey())
+	if err != nil {
+		t.Fatal("GetClaimFeeOperator() fail", err)
+	}
+	if feeOperator == nil {
+		ix, err := dbcService.CreateClaimFeeOperator(ctx1, dynamic_bonding_curve.CreateClaimFeeOperatorParams{
+			Operator: operator.PublicKey(),
+			Admin:    admin.PublicKey(),
+			Payer:    admin.PublicKey(),
+		})
+		if err != nil {
+			t.Fatal("CreateClaimFeeOperator() fail", err)
+		}
+		sig, err := SendInstruction(ctx1, rpcClient, wsClient, []solana.Instruction{ix}, admin.PublicKey(), sign)
+		if err != nil {
+			t.Fatal("create operator SendTransaction() fail", err)
+		}
+		fmt.Println("create operator success Success sig:", sig.String())
+	}
+
+	operators, err := dbcService.GetClaimFeeOperators(ctx1)
+	if err != nil {
+		t.Fatal("GetClaimFeeOperators() fail", err)
+	}
+	for _, op := range operators {
+		fmt.Println("claim fee operator:", op.Pubkey, "operator:", op.Account.Operator)
+	}
+
+	owed, err := dbcService.GetProtocolFeesOwed(ctx1, configAddress)
+	if err != nil {
+		t.Fatal("GetProtocolFeesOwed() fail", err)
+	}
+	for _, o := range owed {
+		fmt.Println("pool:", o.Pool, "base fee:", o.BaseFee, "quote fee:", o.QuoteFee, "pool creation fee:", o.PoolCreationFee)
+	}
+
+	result, err := dbcService.ClaimAllProtocolFees(ctx1, dynamic_bonding_curve.ClaimAllProtocolFeesParams{
+		Operator:             operator.PublicKey(),
+		Payer:                operator.PublicKey(),
+		Configs:              []solana.PublicKey{configAddress},
+		ClaimPoolCreationFee: true,
+		// Treasury: solana.PublicKey{}, // treasury of a localnet build
+	})
+	if err != nil {
+		t.Fatal("ClaimAllProtocolFees() fail", err)
+	}
+	for _, batch := range result.Batches {
+		sig, err := SendTransaction(ctx1, rpcClient, wsClient, batch.Transaction, sign)
+		if err != nil {
+			t.Fatal("claim protocol fee SendTransaction() fail", err)
+		}
+		fmt.Println("claim pools:", batch.Pools, "success Success sig:", sig.String())
+	}
+
+	closeIx, err := dbcService.CloseClaimFeeOperator(ctx1, dynamic_bonding_curve.CloseClaimFeeOperatorParams{
+		Operator:     operator.PublicKey(),
+		Admin:        admin.PublicKey(),
+		RentReceiver: admin.PublicKey(),
+	})
+	if err != nil {
+		t.Fatal("CloseClaimFeeOperator() fail", err)
+	}
+	sig, err := SendInstruction(ctx1, rpcClient, wsClient, []solana.Instruction{closeIx}, admin.PublicKey(), sign)
+	if err != nil {
+		t.Fatal("close operator SendTransaction() fail", err)
+	}
+	fmt.Println("close operator success Success sig:", sig.String())
+}
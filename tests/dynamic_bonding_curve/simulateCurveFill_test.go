@@ -0,0 +1,92 @@
+package dynamic_bonding_curve
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+func TestSimulateCurveFill(t *testing.T) {
+	migratedPoolBaseFeeMode := shared.DammV2BaseFeeModeFeeTimeSchedulerLinear
+
+	buildCurveBaseParams := shared.BuildCurveBaseParams{
+		TotalTokenSupply:  1000000000,
+		MigrationOption:   shared.MigrationOptionMetDammV2,
+		TokenBaseDecimal:  shared.TokenDecimalSix,
+		TokenQuoteDecimal: shared.TokenDecimalNine,
+		LockedVestingParams: shared.LockedVestingParams{
+			TotalLockedVestingAmount:       0,
+			NumberOfVestingPeriod:          0,
+			CliffUnlockAmount:              0,
+			TotalVestingDuration:           0,
+			CliffDurationFromMigrationTime: 0,
+		},
+		BaseFeeParams: shared.BaseFeeParams{
+			BaseFeeMode: shared.BaseFeeModeFeeSchedulerLinear,
+			FeeSchedulerParam: &shared.FeeSchedulerParams{
+				StartingFeeBps: 100,
+				EndingFeeBps:   100,
+				NumberOfPeriod: 0,
+				TotalDuration:  0,
+			},
+		},
+		DynamicFeeEnabled:                         true,
+		ActivationType:                            shared.ActivationTypeSlot,
+		CollectFeeMode:                            shared.CollectFeeModeQuoteToken,
+		MigrationFeeOption:                        shared.MigrationFeeOptionFixedBps100,
+		TokenType:                                 shared.TokenTypeSPL,
+		PartnerLiquidityPercentage:                0,
+		CreatorLiquidityPercentage:                0,
+		PartnerPermanentLockedLiquidityPercentage: 100,
+		CreatorPermanentLockedLiquidityPercentage: 0,
+		CreatorTradingFeePercentage:               0,
+		Leftover:                                  0,
+		TokenUpdateAuthority:                      0,
+		MigrationFee: struct {
+			FeePercentage        uint8
+			CreatorFeePercentage uint8
+		}{
+			FeePercentage:        0,
+			CreatorFeePercentage: 0,
+		},
+		PoolCreationFee:           1,
+		MigratedPoolBaseFeeMode:   &migratedPoolBaseFeeMode,
+		EnableFirstSwapWithMinFee: false,
+	}
+	params := shared.BuildCurveParams{
+		BuildCurveBaseParams:        buildCurveBaseParams,
+		PercentageSupplyOnMigration: 2.983257229832572,
+		MigrationQuoteThreshold:     95.07640791476408,
+	}
+
+	cfg, err := helpers.BuildCurve(params)
+	if err != nil {
+		t.Fatal("BuildCurve() fail", err)
+	}
+
+	config, err := helpers.PoolConfigFromConfigParameters(cfg)
+	if err != nil {
+		t.Fatal("PoolConfigFromConfigParameters() fail", err)
+	}
+
+	report, err := helpers.SimulateCurveFill(config, shared.SimulateCurveFillParams{
+		Steps:           10,
+		QuoteDecimal:    shared.TokenDecimalNine,
+		ActivationPoint: 0,
+		Timeline:        []uint64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+		// HasReferral: true,
+	})
+	if err != nil {
+		t.Fatal("SimulateCurveFill() fail", err)
+	}
+
+	for _, step := range report.Steps {
+		fmt.Println("point:", step.Point, "price:", step.Price, "market cap:", step.MarketCap, "base sold:", step.BaseSold, "quote raised:", step.QuoteRaised, "protocol fee:", step.ProtocolFee, "partner fee:", step.PartnerFee, "creator fee:", step.CreatorFee, "referral fee:", step.ReferralFee)
+	}
+	fmt.Println("base sold:", report.BaseSold, "quote raised:", report.QuoteRaised, "migration market cap:", report.MigrationMarketCap)
+	fmt.Println("protocol fee:", report.TotalProtocolFee, "partner fee:", report.TotalPartnerFee, "creator fee:", report.TotalCreatorFee, "referral fee:", report.TotalReferralFee)
+	fmt.Println("partner migration fee:", report.PartnerMigrationFee, "creator migration fee:", report.CreatorMigrationFee)
+	fmt.Println("surplus:", report.Surplus, "leftover:", report.Leftover)
+}
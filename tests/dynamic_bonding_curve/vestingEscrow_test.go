@@ -0,0 +1,113 @@
+package dynamic_bonding_curve
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/helpers"
+	"github.com/krazyTry/meteora-go/dynamic_bonding_curve/shared"
+)
+
+func TestVestingEscrowSchedule(t *testing.T) {
+	escrow := shared.VestingEscrow{
+		Recipient:          solana.NewWallet().PublicKey(),
+		TokenMint:          solana.NewWallet().PublicKey(),
+		CliffTime:          1_000,
+		Frequency:          100,
+		CliffUnlockAmount:  500,
+		AmountPerPeriod:    50,
+		NumberOfPeriod:     10,
+		TotalClaimedAmount: 550,
+	}
+
+	discriminator := sha256.Sum256([]byte("account:VestingEscrow"))
+	buf := new(bytes.Buffer)
+	buf.Write(discriminator[:8])
+	if err := bin.NewBorshEncoder(buf).Encode(escrow); err != nil {
+		t.Fatal("Encode() fail", err)
+	}
+	if buf.Len() != 296 {
+		t.Fatalf("escrow account size = %d, want 296", buf.Len())
+	}
+	parsed, err := helpers.ParseVestingEscrow(buf.Bytes())
+	if err != nil {
+		t.Fatal("ParseVestingEscrow() fail", err)
+	}
+	if *parsed != escrow {
+		t.Fatalf("ParseVestingEscrow() = %+v, want %+v", *parsed, escrow)
+	}
+
+	status := helpers.VestingStatusFromEscrow(solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, parsed, 1_250)
+	fmt.Println("total locked:", status.TotalLocked, "unlocked:", status.Unlocked, "claimed:", status.Claimed, "claimable:", status.ClaimableNow)
+	if status.TotalLocked != 1_000 || status.Unlocked != 600 || status.ClaimableNow != 50 {
+		t.Fatalf("status = %+v", status)
+	}
+	if len(status.Schedule) != 11 || status.Schedule[10].Cumulative != status.TotalLocked {
+		t.Fatalf("schedule = %+v", status.Schedule)
+	}
+	if status.NextUnlock == nil || status.NextUnlock.Time != 1_300 {
+		t.Fatalf("next unlock = %+v", status.NextUnlock)
+	}
+
+	if unlocked := helpers.VestingUnlockedAmount(parsed, 999); unlocked != 0 {
+		t.Fatalf("unlocked before cliff = %d", unlocked)
+	}
+	if unlocked := helpers.VestingUnlockedAmount(parsed, 1_000_000); unlocked != status.TotalLocked {
+		t.Fatalf("unlocked after schedule = %d", unlocked)
+	}
+}
+
+func TestClaimCreatorVesting(t *testing.T) {
+
+	dbcService := dynamic_bonding_curve.NewDynamicBondingCurve(rpcClient, rpc.CommitmentFinalized)
+
+	poolCreator := &solana.Wallet{PrivateKey: solana.MustPrivateKeyFromBase58("")}
+	fmt.Println("pool creator address:", poolCreator.PublicKey())
+
+	baseMint := solana.MustPublicKeyFromBase58("")
+
+	ctx1 := context.Background()
+
+	poolState, err := dbcService.GetPoolByBaseMint(ctx1, baseMint)
+	if err != nil {
+		t.Fatal("GetPoolByBaseMint() fail", err)
+	}
+
+	status, err := dbcService.GetCreatorVestingStatus(ctx1, poolState.Pubkey)
+	if err != nil {
+		t.Fatal("GetCreatorVestingStatus() fail", err)
+	}
+	fmt.Println("escrow:", status.Escrow, "total locked:", status.TotalLocked, "claimed:", status.Claimed, "claimable:", status.ClaimableNow)
+	if status.NextUnlock != nil {
+		fmt.Println("next unlock at:", status.NextUnlock.Time, "amount:", status.NextUnlock.Amount)
+	}
+
+	if status.ClaimableNow > 0 {
+		pre, claimIx, err := dbcService.ClaimCreatorVesting(ctx1, dynamic_bonding_curve.ClaimCreatorVestingParams{
+			Pool:  poolState.Pubkey,
+			Payer: poolCreator.PublicKey(),
+		})
+		if err != nil {
+			t.Fatal("ClaimCreatorVesting() fail", err)
+		}
+		sig, err := SendInstruction(ctx1, rpcClient, wsClient, append(pre, claimIx), poolCreator.PublicKey(), func(key solana.PublicKey) *solana.PrivateKey {
+			switch {
+			case key.Equals(poolCreator.PublicKey()):
+				return &poolCreator.PrivateKey
+			default:
+				return nil
+			}
+		})
+		if err != nil {
+			t.Fatal("claim vesting SendTransaction() fail", err)
+		}
+		fmt.Println("claim vesting success Success sig:", sig.String())
+	}
+}